@@ -0,0 +1,275 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+//OverflowPolicy decides what AsyncLog does when its buffer is full
+type OverflowPolicy int
+
+const (
+	//OverflowBlock waits for space in the buffer, no entries are lost
+	OverflowBlock OverflowPolicy = iota
+	//OverflowDropOldest discards the oldest buffered entry to make room, logging never blocks
+	OverflowDropOldest
+)
+
+//asyncEntry is a buffered call waiting to be written
+type asyncEntry struct {
+	level string
+	v     []interface{}
+}
+
+//AsyncLog wraps a Logger so that writes happen on a background goroutine
+//Entries are delivered in order, Close must be called to flush the buffer and stop the goroutine
+type AsyncLog struct {
+	//enqueued and dropped are updated atomically, they come first to keep them 64 bit aligned
+	enqueued uint64
+	dropped  uint64
+
+	mu      sync.Mutex
+	logger  Logger
+	entries chan asyncEntry
+	done    chan struct{}
+	policy  OverflowPolicy
+	closed  bool
+
+	//highWater is the buffer utilization, from 0 to 1, at which onHighWater is called
+	highWater   float64
+	onHighWater func(AsyncStats)
+	aboveHigh   bool
+
+	//progress guards processed, the count of entries written or dropped, which Flush waits on
+	progress  sync.Mutex
+	processed uint64
+	cond      *sync.Cond
+}
+
+//NewAsyncLog returns an AsyncLog that buffers up to size entries for l
+func NewAsyncLog(l Logger, size int) *AsyncLog {
+	s := &AsyncLog{
+		logger:  l,
+		entries: make(chan asyncEntry, size),
+		done:    make(chan struct{}),
+	}
+	s.cond = sync.NewCond(&s.progress)
+	go s.drain()
+	return s
+}
+
+//SetOverflowPolicy sets the behaviour when the buffer is full, OverflowBlock is the default
+func (s *AsyncLog) SetOverflowPolicy(p OverflowPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policy = p
+}
+
+//AsyncStats is a snapshot of an AsyncLog's buffer
+type AsyncStats struct {
+	//Enqueued is the number of entries accepted into the buffer
+	Enqueued uint64
+	//Dropped is the number of entries discarded, by OverflowDropOldest or because they were logged after Close
+	Dropped uint64
+	//Depth is the number of entries currently buffered and Capacity the size of the buffer
+	Depth    int
+	Capacity int
+}
+
+//Stats returns the buffer counters, it never blocks so it is safe to call while logging is blocked on a full buffer
+func (s *AsyncLog) Stats() AsyncStats {
+	return AsyncStats{
+		Enqueued: atomic.LoadUint64(&s.enqueued),
+		Dropped:  atomic.LoadUint64(&s.dropped),
+		Depth:    len(s.entries),
+		Capacity: cap(s.entries),
+	}
+}
+
+//SetHighWater calls f when the buffer utilization rises to threshold, a fraction of the capacity such as 0.8
+//f is called again only once the utilization has fallen back below threshold, so it can be used to alert before entries are dropped
+//f is called from Log and must not block, a nil f disables it
+func (s *AsyncLog) SetHighWater(threshold float64, f func(AsyncStats)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.highWater = threshold
+	s.onHighWater = f
+	s.aboveHigh = false
+}
+
+//crossedHighWater reports whether the buffer has just reached the high-water mark, s.mu must be held
+func (s *AsyncLog) crossedHighWater() bool {
+	if s.onHighWater == nil || cap(s.entries) == 0 {
+		return false
+	}
+	above := float64(len(s.entries)) >= s.highWater*float64(cap(s.entries))
+	crossed := above && !s.aboveHigh
+	s.aboveHigh = above
+	return crossed
+}
+
+//drain writes buffered entries to the wrapped logger until the buffer is closed
+func (s *AsyncLog) drain() {
+	defer close(s.done)
+	for e := range s.entries {
+		s.logger.Log(e.level, e.v...)
+		s.markProcessed()
+	}
+}
+
+//markProcessed records that an entry has left the buffer and wakes any waiting Flush
+func (s *AsyncLog) markProcessed() {
+	s.progress.Lock()
+	s.processed++
+	s.progress.Unlock()
+	s.cond.Broadcast()
+}
+
+//Flush waits until every entry logged before the call has been written, then flushes the wrapped logger
+func (s *AsyncLog) Flush() error {
+	s.mu.Lock()
+	target := atomic.LoadUint64(&s.enqueued)
+	s.mu.Unlock()
+
+	s.progress.Lock()
+	for s.processed < target {
+		s.cond.Wait()
+	}
+	s.progress.Unlock()
+
+	if fc, ok := s.logger.(FlushCloser); ok {
+		return fc.Flush()
+	}
+	return nil
+}
+
+//Close stops accepting entries, returns once every buffered entry has been written
+//and then closes the wrapped logger if it holds resources
+func (s *AsyncLog) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.entries)
+	}
+	s.mu.Unlock()
+	<-s.done
+	if c, ok := s.logger.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+//Enabled reports whether the wrapped logger would write an entry at level
+func (s *AsyncLog) Enabled(level string) bool {
+	return enabled(s.logger, level)
+}
+
+//Init initializes the wrapped logger
+func (s *AsyncLog) Init() error {
+	return s.logger.Init()
+}
+
+//OnInit registers initializers on the wrapped logger
+func (s *AsyncLog) OnInit(f ...interface{}) {
+	s.logger.OnInit(f...)
+}
+
+//OnInitLogger registers an initializer on the wrapped logger, it is called with the wrapped logger
+func (s *AsyncLog) OnInitLogger(f func(s Logger)) {
+	s.OnInit(f)
+}
+
+func (s *AsyncLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *AsyncLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *AsyncLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *AsyncLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *AsyncLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *AsyncLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *AsyncLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *AsyncLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *AsyncLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *AsyncLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *AsyncLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *AsyncLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *AsyncLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *AsyncLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *AsyncLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *AsyncLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *AsyncLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+
+//Log buffers the entry, entries logged after Close are dropped
+func (s *AsyncLog) Log(level string, v ...interface{}) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		atomic.AddUint64(&s.dropped, 1)
+		return
+	}
+	s.enqueue(asyncEntry{level: level, v: v})
+	crossed, f := s.crossedHighWater(), s.onHighWater
+	s.mu.Unlock()
+	//Called without the lock so that f may log through s
+	if crossed {
+		f(s.Stats())
+	}
+}
+
+//enqueue adds e to the buffer according to the overflow policy, s.mu must be held
+func (s *AsyncLog) enqueue(e asyncEntry) {
+	if s.policy == OverflowBlock {
+		s.entries <- e
+		atomic.AddUint64(&s.enqueued, 1)
+		return
+	}
+	for {
+		select {
+		case s.entries <- e:
+			atomic.AddUint64(&s.enqueued, 1)
+			return
+		default:
+		}
+		//Make room by discarding the oldest entry, unless the drain goroutine got there first
+		select {
+		case <-s.entries:
+			atomic.AddUint64(&s.dropped, 1)
+			s.markProcessed()
+		default:
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+//slowLog records entries after a short delay to keep the async buffer full
+type slowLog struct {
+	recordLog
+}
+
+func (s *slowLog) Log(level string, v ...interface{}) {
+	time.Sleep(time.Millisecond)
+	s.recordLog.Log(level, v...)
+}
+
+func TestAsyncLogOrdered(t *testing.T) {
+	rec := new(recordLog)
+	al := NewAsyncLog(rec, 1)
+	for i := 0; i < 1000; i++ {
+		al.Info(i)
+	}
+	al.Close()
+
+	if len(rec.lines) != 1000 {
+		t.Fatal("expected 1000 lines, got", len(rec.lines))
+	}
+	for i, line := range rec.lines {
+		if line != fmt.Sprintf("Info [%d]", i) {
+			t.Fatal("entries delivered out of order at", i, line)
+		}
+	}
+}
+
+func TestAsyncLogCloseFlushes(t *testing.T) {
+	slow := new(slowLog)
+	al := NewAsyncLog(slow, 100)
+	for i := 0; i < 50; i++ {
+		al.Debug(i)
+	}
+	al.Close()
+	if len(slow.lines) != 50 {
+		t.Error("Close returned before flushing, got", len(slow.lines))
+	}
+
+	//Entries after Close are dropped rather than panicking
+	al.Info("late")
+	if len(slow.lines) != 50 {
+		t.Error("entry written after Close")
+	}
+}
+
+func TestAsyncLogDropOldest(t *testing.T) {
+	slow := new(slowLog)
+	al := NewAsyncLog(slow, 2)
+	al.SetOverflowPolicy(OverflowDropOldest)
+	for i := 0; i < 100; i++ {
+		al.Info(i)
+	}
+	al.Close()
+
+	if len(slow.lines) >= 100 || len(slow.lines) == 0 {
+		t.Error("expected some entries to be dropped, got", len(slow.lines))
+	}
+	if slow.lines[len(slow.lines)-1] != "Info [99]" {
+		t.Error("the newest entry must never be dropped", slow.lines)
+	}
+}
+
+func TestAsyncLogFlush(t *testing.T) {
+	slow := new(slowLog)
+	al := NewAsyncLog(slow, 100)
+	defer al.Close()
+	for i := 0; i < 20; i++ {
+		al.Info(i)
+	}
+	if err := al.Flush(); err != nil {
+		t.Error("unexpected Flush error", err)
+	}
+	slow.mu.Lock()
+	n := len(slow.lines)
+	slow.mu.Unlock()
+	if n != 20 {
+		t.Error("Flush returned before the buffer was written, got", n)
+	}
+}
+
+//blockLog blocks every write until release is closed
+type blockLog struct {
+	recordLog
+	release chan struct{}
+}
+
+func (b *blockLog) Log(level string, v ...interface{}) {
+	<-b.release
+	b.recordLog.Log(level, v...)
+}
+
+func TestAsyncLogStats(t *testing.T) {
+	blocked := &blockLog{release: make(chan struct{})}
+	al := NewAsyncLog(blocked, 2)
+	al.SetOverflowPolicy(OverflowDropOldest)
+	for i := 0; i < 10; i++ {
+		al.Info(i)
+	}
+	stats := al.Stats()
+	if stats.Enqueued != 10 || stats.Capacity != 2 || stats.Depth != 2 {
+		t.Error("unexpected stats", stats)
+	}
+	//At most one entry is held by the blocked write and two by the buffer
+	if stats.Dropped < 7 {
+		t.Error("expected the overflow to be counted as dropped, got", stats.Dropped)
+	}
+
+	close(blocked.release)
+	al.Close()
+	al.Info("late")
+	stats = al.Stats()
+	if uint64(len(blocked.lines))+stats.Dropped != 11 {
+		t.Error("expected every entry to be written or dropped", len(blocked.lines), stats)
+	}
+	if stats.Depth != 0 {
+		t.Error("expected an empty buffer after Close, got", stats.Depth)
+	}
+}
+
+func TestAsyncLogHighWater(t *testing.T) {
+	blocked := &blockLog{release: make(chan struct{})}
+	al := NewAsyncLog(blocked, 4)
+	var alerts []AsyncStats
+	al.SetHighWater(0.75, func(stats AsyncStats) {
+		alerts = append(alerts, stats)
+	})
+	//One entry may be taken by the blocked write, the buffer then fills to 3 of 4
+	for i := 0; i < 5; i++ {
+		al.Info(i)
+	}
+	if len(alerts) != 1 {
+		t.Fatal("expected a single alert while the buffer stays full, got", len(alerts))
+	}
+	if alerts[0].Depth < 3 || alerts[0].Dropped != 0 {
+		t.Error("unexpected stats in the alert", alerts[0])
+	}
+
+	//Once the buffer drains the next crossing alerts again
+	close(blocked.release)
+	al.Flush()
+	al.SetOverflowPolicy(OverflowDropOldest)
+	blocked.release = make(chan struct{})
+	al.Info("wait")
+	for al.Stats().Depth != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 0; i < 4; i++ {
+		al.Info(i)
+	}
+	if len(alerts) != 2 {
+		t.Error("expected a second alert, got", len(alerts))
+	}
+	close(blocked.release)
+	al.Close()
+}
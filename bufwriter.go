@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"bufio"
+	"io"
+	"sync"
+	"time"
+)
+
+//bufferedWriter buffers writes to a destination, flushing when the buffer fills, when Flush is called and,
+//with an interval, periodically
+//A failed flush is reported by the next Write or Flush
+type bufferedWriter struct {
+	mu   sync.Mutex
+	w    *bufio.Writer
+	done chan struct{}
+}
+
+//newBufferedWriter returns a writer buffering up to size bytes for dst, flushing every interval when it is positive
+func newBufferedWriter(dst io.Writer, size int, interval time.Duration) *bufferedWriter {
+	b := &bufferedWriter{w: bufio.NewWriterSize(dst, size)}
+	if interval > 0 {
+		b.done = make(chan struct{})
+		go b.flushEvery(interval, b.done)
+	}
+	return b
+}
+
+//flushEvery flushes the buffer every d until Close is called
+func (b *bufferedWriter) flushEvery(d time.Duration, done chan struct{}) {
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			b.Flush()
+		case <-done:
+			return
+		}
+	}
+}
+
+//Write implements io.Writer
+func (b *bufferedWriter) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.w.Write(p)
+}
+
+//Flush writes out anything buffered
+func (b *bufferedWriter) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.w.Flush()
+}
+
+//Close stops the periodic flush and flushes the buffer, the destination is left open
+func (b *bufferedWriter) Close() error {
+	b.mu.Lock()
+	if b.done != nil {
+		close(b.done)
+		b.done = nil
+	}
+	b.mu.Unlock()
+	return b.Flush()
+}
+
+//flushesAt reports whether an entry at level is severe enough to be flushed as soon as it is written
+func flushesAt(level string) bool {
+	sev, err := ParseLevel(level)
+	return err == nil && sev <= LevelError
+}
@@ -0,0 +1,159 @@
+package logger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+//countWriter counts the calls to Write
+type countWriter struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	writes int
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writes++
+	return c.buf.Write(p)
+}
+
+func (c *countWriter) String() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.buf.String()
+}
+
+func TestWriterLogBuffer(t *testing.T) {
+	out := new(countWriter)
+	wl := new(WriterLog)
+	wl.SetOutput(out)
+	wl.SetBuffer(4096, 0)
+
+	wl.Debug("first")
+	wl.Info("second")
+	testOutput(out.String(), "", t)
+
+	//Error flushes straight away, taking the buffered entries with it
+	wl.Error("third")
+	testOutput(out.String(), "Debug first\nInfo second\nError third\n", t)
+	if out.writes != 1 {
+		t.Error("expected a single write, got", out.writes)
+	}
+
+	wl.Debug("fourth")
+	testOutput(out.String(), "Debug first\nInfo second\nError third\n", t)
+	if err := wl.Close(); err != nil {
+		t.Fatal(err)
+	}
+	testOutput(out.String(), "Debug first\nInfo second\nError third\nDebug fourth\n", t)
+
+	//Changing the output writes out what was buffered for the old one
+	other := new(countWriter)
+	wl.Info("fifth")
+	wl.SetOutput(other)
+	wl.Info("sixth")
+	wl.Flush()
+	if out.writes != 3 || other.String() != "Info sixth\n" {
+		t.Error("unexpected output", out.String(), other.String())
+	}
+}
+
+func TestWriterLogBufferInterval(t *testing.T) {
+	out := new(countWriter)
+	wl := new(WriterLog)
+	wl.SetOutput(out)
+	wl.SetBuffer(4096, 10*time.Millisecond)
+	defer wl.Close()
+	wl.Debug("buffered")
+	deadline := time.Now().Add(time.Second)
+	for out.String() == "" && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	testOutput(out.String(), "Debug buffered\n", t)
+}
+
+func TestFileLogBuffer(t *testing.T) {
+	fl := new(FileLog)
+	fl.OnInit(tlCallback)
+	fl.OnInit(func(s *FileLog) {
+		s.SetBuffer(4096, 0)
+	})
+	if err := fl.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(fl.logPath)
+
+	read := func() string {
+		b, err := ioutil.ReadFile(fl.logPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	fl.Debug("first")
+	testOutput(read(), "", t)
+	fl.Critical("second")
+	testOutput(read(), "Debug first\nCritical second\n", t)
+	fl.Info("third")
+	testOutput(read(), "Debug first\nCritical second\n", t)
+	if err := fl.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	testOutput(read(), "Debug first\nCritical second\nInfo third\n", t)
+	fl.Debug("fourth")
+	fl.Close()
+	testOutput(read(), "Debug first\nCritical second\nInfo third\nDebug fourth\n", t)
+}
+
+func benchmarkWriterLog(b *testing.B, size int) {
+	out := new(countWriter)
+	wl := new(WriterLog)
+	wl.SetOutput(out)
+	wl.SetBuffer(size, 0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wl.Info("request served", i)
+	}
+	wl.Close()
+	b.ReportMetric(float64(out.writes)/float64(b.N), "writes/op")
+}
+
+func BenchmarkWriterLogUnbuffered(b *testing.B) {
+	benchmarkWriterLog(b, 0)
+}
+
+func BenchmarkWriterLogBuffered(b *testing.B) {
+	benchmarkWriterLog(b, 64*1024)
+}
+
+//benchmarkFileLog writes small entries to a file, where each unbuffered entry is a write system call
+func benchmarkFileLog(b *testing.B, size int) {
+	fl := new(FileLog)
+	fl.OnInit(tlCallback)
+	fl.OnInit(func(s *FileLog) {
+		s.SetBuffer(size, 0)
+	})
+	if err := fl.Init(); err != nil {
+		b.Fatal(err)
+	}
+	defer os.Remove(fl.logPath)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fl.Info("request served", i)
+	}
+	fl.Close()
+}
+
+func BenchmarkFileLogUnbuffered(b *testing.B) {
+	benchmarkFileLog(b, 0)
+}
+
+func BenchmarkFileLogBuffered(b *testing.B) {
+	benchmarkFileLog(b, 64*1024)
+}
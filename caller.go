@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+//pkgDir is the directory holding this package's source, used to skip internal frames
+var pkgDir string
+
+func init() {
+	_, file, _, _ := runtime.Caller(0)
+	pkgDir = filepath.Dir(file)
+}
+
+//SetCaller enables or disables reporting of the file and line of the log call
+func (l *LogBase) SetCaller(enabled bool) {
+	l.caller = enabled
+}
+
+//CallerFunc controls whether the function making the log call is reported, in a "func" field
+type CallerFunc int
+
+const (
+	//NoFunc leaves the function out, it is the default
+	NoFunc CallerFunc = iota
+	//ShortFunc reports the package name and function, e.g. "http.(*Server).Serve"
+	ShortFunc
+	//FullFunc reports the function with its full package path, e.g. "net/http.(*Server).Serve"
+	FullFunc
+)
+
+//SetCallerFunc sets whether the function of the log call is reported alongside or instead of its file and line
+//Text lines write it after the caller as "(http.(*Server).Serve) ", the other formats as a "func" field
+func (l *LogBase) SetCallerFunc(mode CallerFunc) {
+	l.callerFunc = mode
+}
+
+//format returns the function name fn as the mode reports it
+func (mode CallerFunc) format(fn string) string {
+	if mode == ShortFunc {
+		return fn[strings.LastIndex(fn, "/")+1:]
+	}
+	return fn
+}
+
+//callerFrame returns the first frame outside of this package
+//Walking the frames rather than using a fixed depth means the level methods, Log and any
+//decorators or stacks in between are all skipped, whichever entry point the user called
+func callerFrame() (runtime.Frame, bool) {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !internalFrame(frame.File) {
+			return frame, true
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+//callerFields adds the caller location and function to fields as the logger reports them
+func (l *LogBase) callerFields(fields map[string]interface{}) {
+	frame, ok := callerFrame()
+	if l.caller {
+		fields["caller"] = "???:0"
+		if ok {
+			fields["caller"] = fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+		}
+	}
+	if l.callerFunc != NoFunc {
+		fields["func"] = "???"
+		if ok && frame.Function != "" {
+			fields["func"] = l.callerFunc.format(frame.Function)
+		}
+	}
+}
+
+//internalFrame reports whether file is one of this package's non test source files
+func internalFrame(file string) bool {
+	return filepath.Dir(file) == pkgDir && !strings.HasSuffix(file, "_test.go")
+}
@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//CEFFormatter renders entries in ArcSight Common Event Format for SIEM ingestion:
+//CEF:0|vendor|product|version|signatureID|name|severity|extension
+//The level is used as the signature ID, the message as the name, and fields and
+//map[string]interface{} arguments become the key=value extension
+type CEFFormatter struct {
+	vendor  string
+	product string
+	version string
+}
+
+//SetVendor sets the Device Vendor header
+func (f *CEFFormatter) SetVendor(vendor string) {
+	f.vendor = vendor
+}
+
+//SetProduct sets the Device Product header
+func (f *CEFFormatter) SetProduct(product string) {
+	f.product = product
+}
+
+//SetVersion sets the Device Version header
+func (f *CEFFormatter) SetVersion(version string) {
+	f.version = version
+}
+
+//cefSeverities maps each RFC 5424 severity to the 0-10 CEF scale, indexed by Severity
+var cefSeverities = []int{10, 9, 8, 7, 5, 4, 3, 1}
+
+//cefHeaderEscaper escapes backslashes and pipes in header values, which may not span lines
+var cefHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r\n", " ", "\n", " ", "\r", " ")
+
+//cefExtensionEscaper escapes backslashes, equals signs and line breaks in extension values
+var cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r\n", `\n`, "\n", `\n`, "\r", `\r`)
+
+//cefInvalidKey matches the characters not allowed in extension keys, which are written unescaped
+var cefInvalidKey = regexp.MustCompile(`[^A-Za-z0-9_.]`)
+
+//cefKey turns a field name into a valid extension key, e.g. "user name=x" becomes "user_name_x"
+func cefKey(k string) string {
+	k = cefInvalidKey.ReplaceAllString(k, "_")
+	if k == "" {
+		return "_"
+	}
+	return k
+}
+
+//Format implements Formatter
+func (f *CEFFormatter) Format(level string, fields map[string]interface{}, args []interface{}) ([]byte, error) {
+	severity := 3
+	if sev, err := ParseLevel(level); err == nil {
+		severity = cefSeverities[sev.standard()]
+	}
+
+	ext := make(map[string]interface{}, len(fields))
+	for k, v := range flattenGroups(fields) {
+		ext[cefKey(k)] = v
+	}
+	msg := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		if m, ok := arg.(map[string]interface{}); ok {
+			for k, v := range m {
+				ext[cefKey(k)] = v
+			}
+			continue
+		}
+		msg = append(msg, arg)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "CEF:0|%s|%s|%s|%s|%s|%d|",
+		cefHeaderEscaper.Replace(f.vendor),
+		cefHeaderEscaper.Replace(f.product),
+		cefHeaderEscaper.Replace(f.version),
+		cefHeaderEscaper.Replace(level),
+		cefHeaderEscaper.Replace(joinArgs(msg)),
+		severity)
+
+	keys := make([]string, 0, len(ext))
+	for k := range ext {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(k + "=" + cefExtensionEscaper.Replace(fmt.Sprint(ext[k])))
+	}
+	b.WriteByte('\n')
+	return []byte(b.String()), nil
+}
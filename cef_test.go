@@ -0,0 +1,48 @@
+package logger
+
+import "testing"
+
+func newTestCEFFormatter() *CEFFormatter {
+	f := new(CEFFormatter)
+	f.SetVendor("owtorg")
+	f.SetProduct("logger")
+	f.SetVersion("1.0")
+	return f
+}
+
+func TestCEFFormatter(t *testing.T) {
+	b, err := newTestCEFFormatter().Format("Error", map[string]interface{}{"src": "10.0.0.1"}, []interface{}{"login failed", map[string]interface{}{"suser": "bob"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testOutput(string(b), "CEF:0|owtorg|logger|1.0|Error|login failed|7|src=10.0.0.1 suser=bob\n", t)
+}
+
+func TestCEFEscaping(t *testing.T) {
+	f := new(CEFFormatter)
+	f.SetVendor(`a|b\c`)
+	f.SetProduct("multi\nline")
+	b, _ := f.Format("Warning", map[string]interface{}{"msg": "a=b\\c\nd|e"}, []interface{}{"pipe | and \\ slash"})
+	expected := `CEF:0|a\|b\\c|multi line||Warning|pipe \| and \\ slash|5|msg=a\=b\\c\nd|e` + "\n"
+	testOutput(string(b), expected, t)
+}
+
+func TestCEFHostileKeys(t *testing.T) {
+	fields := map[string]interface{}{"user name": "bob", "act=deny suser": "mallory", "a|b\nc": 1, "": "empty"}
+	b, _ := newTestCEFFormatter().Format("Error", fields, []interface{}{"x", map[string]interface{}{"http.status": 500}})
+	expected := "CEF:0|owtorg|logger|1.0|Error|x|7|_=empty a_b_c=1 act_deny_suser=mallory http.status=500 user_name=bob\n"
+	testOutput(string(b), expected, t)
+}
+
+func TestCEFSeverity(t *testing.T) {
+	f := newTestCEFFormatter()
+	expected := map[string]string{
+		"Emergency": "10", "Alert": "9", "Critical": "8", "Error": "7",
+		"Warning": "5", "Notice": "4", "Info": "3", "Debug": "1", "custom": "3",
+	}
+	for level, sev := range expected {
+		b, _ := f.Format(level, nil, []interface{}{"x"})
+		want := "CEF:0|owtorg|logger|1.0|" + level + "|x|" + sev + "|\n"
+		testOutput(string(b), want, t)
+	}
+}
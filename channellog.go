@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+//defaultChannelBuffer is the capacity of the channel created by Subscribe
+const defaultChannelBuffer = 100
+
+//ChannelLog sends each entry to a channel so another part of the program can follow the log, e.g. a live tail endpoint
+//Sends never block, an entry is dropped and counted when the channel is full
+type ChannelLog struct {
+	//dropped is updated atomically, it comes first to keep it 64 bit aligned
+	dropped uint64
+	LogBase
+	mu sync.Mutex
+	ch chan Entry
+}
+
+//SetChannel sets the channel entries are sent to, it should be buffered since entries that do not fit are dropped
+func (s *ChannelLog) SetChannel(ch chan Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ch = ch
+}
+
+//Subscribe creates a channel buffering 100 entries, makes it the channel entries are sent to and returns it
+//e.g. for e := range l.Subscribe() { ... } follows the log until Close is called
+func (s *ChannelLog) Subscribe() <-chan Entry {
+	ch := make(chan Entry, defaultChannelBuffer)
+	s.SetChannel(ch)
+	return ch
+}
+
+//Dropped returns the number of entries discarded because the channel was full or not set
+func (s *ChannelLog) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+//Close closes the channel, ending any range over it, later entries are dropped
+func (s *ChannelLog) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ch != nil {
+		close(s.ch)
+		s.ch = nil
+	}
+	return nil
+}
+
+//Init expects input to be a list of func(s *ChannelLog) which will be called on initialization
+func (s *ChannelLog) Init() error {
+	for _, fn := range s.initializers {
+		switch funct := fn.(type) {
+		case func(s *ChannelLog):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *ChannelLog) or func(s Logger)")
+		}
+	}
+	return nil
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *ChannelLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *ChannelLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *ChannelLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *ChannelLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *ChannelLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *ChannelLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
+func (s *ChannelLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *ChannelLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *ChannelLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *ChannelLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *ChannelLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *ChannelLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *ChannelLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *ChannelLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *ChannelLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *ChannelLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *ChannelLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *ChannelLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *ChannelLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *ChannelLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *ChannelLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *ChannelLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *ChannelLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+func (s *ChannelLog) Log(level string, v ...interface{}) {
+	if !s.shouldLog(level) {
+		return
+	}
+	fields, args := s.prepare(level, v)
+	e := s.newEntry(level, fields, args)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case s.ch <- e:
+	default:
+		//A full or nil channel would block
+		atomic.AddUint64(&s.dropped, 1)
+	}
+}
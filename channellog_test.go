@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestChannelLog(t *testing.T) {
+	cl := new(ChannelLog)
+	if err := cl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	entries := cl.Subscribe()
+	at := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	cl.SetClock(newFakeClock(at))
+
+	var (
+		wg       sync.WaitGroup
+		received []Entry
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for e := range entries {
+			received = append(received, e)
+		}
+	}()
+	cl.Info("first")
+	cl.Errorf("second %d", 2)
+	cl.Close()
+	wg.Wait()
+
+	if len(received) != 2 || cl.Dropped() != 0 {
+		t.Fatal("expected 2 entries and none dropped, got", received, cl.Dropped())
+	}
+	if received[0].Level != "Info" || received[0].Message != "first" || !received[0].Time.Equal(at) {
+		t.Error("unexpected entry", received[0])
+	}
+	if received[1].Level != "Error" || received[1].Message != "second 2" {
+		t.Error("unexpected entry", received[1])
+	}
+
+	//Entries logged after Close are dropped rather than sent on the closed channel
+	cl.Info("after close")
+	if cl.Dropped() != 1 {
+		t.Error("expected the entry to be dropped, got", cl.Dropped())
+	}
+}
+
+func TestChannelLogFull(t *testing.T) {
+	cl := new(ChannelLog)
+	ch := make(chan Entry, 2)
+	cl.SetChannel(ch)
+	for i := 0; i < 5; i++ {
+		cl.Info("entry", i)
+	}
+	if len(ch) != 2 || cl.Dropped() != 3 {
+		t.Fatal("expected 2 entries buffered and 3 dropped, got", len(ch), cl.Dropped())
+	}
+	if e := <-ch; e.Message != "entry 0" {
+		t.Error("expected the oldest entries to be kept, got", e.Message)
+	}
+	cl.Info("fits")
+	if len(ch) != 2 || cl.Dropped() != 3 {
+		t.Error("expected the entry to fit once the channel was drained, got", len(ch), cl.Dropped())
+	}
+}
+
+func TestChannelLogWithoutChannel(t *testing.T) {
+	cl := new(ChannelLog)
+	cl.Info("nowhere to go")
+	if cl.Dropped() != 1 {
+		t.Error("expected the entry to be dropped, got", cl.Dropped())
+	}
+}
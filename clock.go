@@ -0,0 +1,39 @@
+package logger
+
+import "time"
+
+//Clock tells the time, loggers read every timestamp through it so that a fixed clock makes output deterministic in tests
+type Clock interface {
+	Now() time.Time
+}
+
+//realClock is the Clock used when none is set
+type realClock struct{}
+
+//Now implements Clock
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+//SetClock sets the clock timestamps are taken from, the system clock is used when c is nil
+func (l *LogBase) SetClock(c Clock) {
+	l.clock = c
+}
+
+//now returns the current time according to the logger's clock
+func (l *LogBase) now() time.Time {
+	return clockNow(l.clock)
+}
+
+//newEntry is NewEntry stamped with the logger's clock
+func (l *LogBase) newEntry(level string, fields map[string]interface{}, args []interface{}) Entry {
+	return entryAt(l.now(), level, fields, args)
+}
+
+//clockNow returns the time from c, or the system time when c is nil
+func clockNow(c Clock) time.Time {
+	if c == nil {
+		return realClock{}.Now()
+	}
+	return c.Now()
+}
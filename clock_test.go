@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+//fakeClock is a Clock that only moves when advanced
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func newFakeClock(t time.Time) *fakeClock {
+	return &fakeClock{t: t}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+//Advance moves the clock forward by d
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func TestClockJSONLog(t *testing.T) {
+	var buf bytes.Buffer
+	clock := newFakeClock(time.Date(2017, 6, 29, 12, 30, 0, 0, time.UTC))
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	jl.SetClock(clock)
+
+	jl.Info("first")
+	jl.WithFields(map[string]interface{}{"k": "v"}).Error("second")
+	clock.Advance(time.Minute)
+	jl.Info("third")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	expected := []string{"2017-06-29T12:30:00Z", "2017-06-29T12:30:00Z", "2017-06-29T12:31:00Z"}
+	if len(lines) != len(expected) {
+		t.Fatal("unexpected output", buf.String())
+	}
+	for i, line := range lines {
+		if m := decodeJSONLine([]byte(line), t); m["time"] != expected[i] {
+			t.Error("unexpected time", i, m["time"])
+		}
+	}
+}
+
+func TestClockMemoryLog(t *testing.T) {
+	at := time.Date(2017, 6, 29, 12, 30, 0, 0, time.UTC)
+	ml := new(MemoryLog)
+	ml.SetClock(newFakeClock(at))
+	ml.Info("a")
+	ml.Error("b")
+	for _, e := range ml.Entries() {
+		if !e.Time.Equal(at) {
+			t.Error("expected the frozen time, got", e.Time)
+		}
+	}
+}
+
+func TestClockFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	wl.SetFormatter(JSONFormatter{})
+	wl.SetClock(newFakeClock(time.Date(2017, 6, 29, 12, 30, 0, 0, time.UTC)))
+	wl.Info("a")
+	wl.Info("b")
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if m := decodeJSONLine([]byte(line), t); m["time"] != "2017-06-29T12:30:00Z" {
+			t.Error("unexpected time", m["time"])
+		}
+	}
+
+	buf.Reset()
+	if err := wl.SetTemplate("{time:15:04:05} {level} {msg}"); err != nil {
+		t.Fatal(err)
+	}
+	wl.SetFormatter(nil)
+	wl.Info("c")
+	testOutput(buf.String(), "12:30:00 Info c\n", t)
+}
@@ -0,0 +1,43 @@
+package logger
+
+import "sync/atomic"
+
+//CloneLogger is implemented by loggers whose configuration can be copied into an independent logger
+type CloneLogger interface {
+	Logger
+	//Clone returns a logger with the same configuration, fields, name and level
+	//Changing the clone's configuration leaves the original unchanged, the two keep writing to the same destination
+	Clone() Logger
+}
+
+//detach gives l, a copy of another logger's base, its own fields, groups, hooks, initializers and redact keys
+//so that configuring either logger leaves the other unchanged, the copy also tracks its severities separately
+func (l *LogBase) detach() {
+	l.fields = copyFields(l.fields)
+	l.groups = append([]string(nil), l.groups...)
+	l.hooks = append([]Hook(nil), l.hooks...)
+	l.initializers = append([]interface{}(nil), l.initializers...)
+	if l.redactKeys != nil {
+		keys := make(map[string]bool, len(l.redactKeys))
+		for k, v := range l.redactKeys {
+			keys[k] = v
+		}
+		l.redactKeys = keys
+	}
+	l.seen = atomic.Value{}
+}
+
+//copyFields returns a copy of fields, groups are copied too
+func copyFields(fields map[string]interface{}) map[string]interface{} {
+	if fields == nil {
+		return nil
+	}
+	c := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		if g, ok := v.(fieldGroup); ok {
+			v = fieldGroup(copyFields(g))
+		}
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+var (
+	_ CloneLogger = new(FmtLog)
+	_ CloneLogger = new(StdLog)
+	_ CloneLogger = new(FileLog)
+	_ CloneLogger = new(WriterLog)
+	_ CloneLogger = new(JSONLog)
+	_ CloneLogger = new(LogfmtLog)
+	_ CloneLogger = new(NetLog)
+	_ CloneLogger = new(GobLog)
+	_ CloneLogger = new(JournaldLog)
+)
+
+func TestWriterLogClone(t *testing.T) {
+	var buf bytes.Buffer
+	base := new(WriterLog)
+	base.SetOutput(&buf)
+	base.SetLevel("Info")
+	base.SetPrefix("app")
+	parent := base.WithFields(map[string]interface{}{"env": "prod"}).(*WriterLog)
+
+	clone := parent.Clone().(*WriterLog)
+	clone.SetLevel("Error")
+	clone.SetPrefix("worker")
+	clone.SetFormatter(JSONFormatter{})
+	clone.fields["env"] = "staging"
+	clone.SetRedactKeys("secret")
+
+	parent.Info("a", map[string]interface{}{"secret": "s"})
+	testOutput(buf.String(), "[app] Info [a map[secret:s]] env=prod\n", t)
+
+	buf.Reset()
+	clone.Info("hidden")
+	clone.Error("b")
+	m := decodeJSONLine(buf.Bytes(), t)
+	if m["logger"] != "worker" || m["env"] != "staging" || m["message"] != "b" {
+		t.Error("unexpected clone output", m)
+	}
+}
+
+func TestCloneHooks(t *testing.T) {
+	parent := new(JSONLog)
+	parent.SetOutput(new(bytes.Buffer))
+	var parentCalls, cloneCalls int
+	parent.AddHook(func(level string, fields map[string]interface{}, args []interface{}) {
+		parentCalls++
+	})
+	clone := parent.Clone().(*JSONLog)
+	clone.AddHook(func(level string, fields map[string]interface{}, args []interface{}) {
+		cloneCalls++
+	})
+	parent.Info("a")
+	clone.Info("b")
+	if parentCalls != 2 || cloneCalls != 1 {
+		t.Error("expected the clone's hook to be its own", parentCalls, cloneCalls)
+	}
+}
+
+func TestCloneGroups(t *testing.T) {
+	parent := new(WriterLog)
+	grouped := parent.WithGroup("http").(FieldLogger).WithFields(map[string]interface{}{"method": "GET"}).(*WriterLog)
+	clone := grouped.Clone().(*WriterLog)
+	clone.fields["http"].(fieldGroup)["method"] = "POST"
+	expected := map[string]interface{}{"http": fieldGroup{"method": "GET"}}
+	if !reflect.DeepEqual(grouped.fields, expected) {
+		t.Error("expected the parent's groups to be unchanged, got", grouped.fields)
+	}
+}
+
+func TestStdLogClone(t *testing.T) {
+	var buf bytes.Buffer
+	parent := new(StdLog)
+	parent.SetLogPrefix("parent: ")
+	parent.logger().SetOutput(&buf)
+
+	clone := parent.Clone().(*StdLog)
+	clone.SetLogPrefix("clone: ")
+	parent.Info("a")
+	clone.Info("b")
+	testOutput(buf.String(), "parent: Info a\nclone: Info b\n", t)
+}
+
+func TestCloneMaxSeverity(t *testing.T) {
+	parent := new(JSONLog)
+	parent.SetOutput(new(bytes.Buffer))
+	clone := parent.Clone().(*JSONLog)
+	clone.Error("only the clone")
+	if parent.HadErrors() || !clone.HadErrors() {
+		t.Error("expected the clone to track its severities separately")
+	}
+}
@@ -0,0 +1,40 @@
+package logger
+
+import "os"
+
+//ANSI escape codes used to colour level names
+const (
+	colorReset   = "\x1b[0m"
+	colorBoldRed = "\x1b[1;31m"
+	colorRed     = "\x1b[31m"
+	colorYellow  = "\x1b[33m"
+	colorCyan    = "\x1b[36m"
+	colorGreen   = "\x1b[32m"
+	colorGray    = "\x1b[90m"
+)
+
+//levelColors holds the colour for each severity, indexed by Severity
+var levelColors = []string{colorBoldRed, colorBoldRed, colorBoldRed, colorRed, colorYellow, colorCyan, colorGreen, colorGray}
+
+//colorize wraps level in the ANSI colour for its severity, custom levels are left as they are
+func colorize(level string) string {
+	return colorizeName(level, level)
+}
+
+//colorizeName wraps name in the ANSI colour for the severity of level, e.g. the Short name of the level
+func colorizeName(level, name string) string {
+	sev, err := ParseLevel(level)
+	if err != nil {
+		return name
+	}
+	return levelColors[sev.standard()] + name + colorReset
+}
+
+//isTerminal reports whether f is a character device such as a terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
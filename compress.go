@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+)
+
+//gzipFile compresses path into path.gz and removes the original
+//The archive is written to a temporary file and renamed into place once complete,
+//so an interrupted compression never leaves a truncated .gz behind
+func gzipFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			dst.Close()
+			os.Remove(tmp)
+		}
+	}()
+
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); err != nil {
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		return err
+	}
+	if err = dst.Sync(); err != nil {
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, path+".gz"); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
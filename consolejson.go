@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+//colorDim is the ANSI escape code for faint text, used for the fields of console lines
+const colorDim = "\x1b[2m"
+
+//consoleTimeFormat is the layout of the timestamp starting console lines
+const consoleTimeFormat = "15:04:05.000"
+
+//SetConsoleJSON writes each entry as a coloured line for reading in a terminal rather than as a JSON object
+//e.g. "12:30:00.000 Error payment declined order=42" with the level coloured and the fields dimmed
+//It only applies when the output is a terminal, so the same logger still writes JSON when piped or redirected
+func (s *JSONLog) SetConsoleJSON(enabled bool) {
+	s.console = enabled
+}
+
+//useConsole reports whether entries should be written as console lines
+func (s *JSONLog) useConsole() bool {
+	if !s.console {
+		return false
+	}
+	if s.isTerminal != nil {
+		return s.isTerminal()
+	}
+	f, ok := s.output().(*os.File)
+	return ok && isTerminal(f)
+}
+
+//consoleLine renders an entry with the same keys as jsonLine as "time level message key=value..."
+func consoleLine(level, name string, t time.Time, fields map[string]interface{}, v []interface{}) string {
+	obj := make(map[string]interface{}, len(fields))
+	for k, val := range fields {
+		obj[k] = val
+	}
+	for k, val := range errorFields(v) {
+		obj[k] = val
+	}
+	args := make([]interface{}, 0, len(v))
+	for _, arg := range v {
+		if m, ok := arg.(map[string]interface{}); ok {
+			for k, val := range m {
+				obj[k] = val
+			}
+			continue
+		}
+		args = append(args, arg)
+	}
+	line := t.Format(consoleTimeFormat) + " " + colorizeName(level, name)
+	if len(args) > 0 {
+		line += " " + joinArgs(args)
+	}
+	obj = flattenGroups(obj)
+	if len(obj) == 0 {
+		return line
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", k, obj[k])
+	}
+	return line + " " + colorDim + strings.Join(pairs, " ") + colorReset
+}
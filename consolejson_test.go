@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConsoleJSON(t *testing.T) {
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	jl.SetClock(newFakeClock(time.Date(2024, 3, 1, 12, 30, 5, 250e6, time.UTC)))
+	jl.SetConsoleJSON(true)
+	jl.isTerminal = func() bool { return true }
+
+	jl.WithFields(map[string]interface{}{"service": "api"}).Error("payment declined", map[string]interface{}{"order": 42})
+	testOutput(buf.String(), "12:30:05.250 \x1b[31mError\x1b[0m payment declined \x1b[2morder=42 service=api\x1b[0m\n", t)
+
+	buf.Reset()
+	jl.Log("custom level", "no fields")
+	jl.Warning(errors.New("disk full"))
+	testOutput(buf.String(), "12:30:05.250 custom level no fields\n12:30:05.250 \x1b[33mWarning\x1b[0m disk full \x1b[2merror=disk full\x1b[0m\n", t)
+
+	//Groups are written as dotted keys
+	buf.Reset()
+	jl.WithGroup("http").(FieldLogger).WithFields(map[string]interface{}{"status": 500}).Info("served")
+	testOutput(buf.String(), "12:30:05.250 \x1b[32mInfo\x1b[0m served \x1b[2mhttp.status=500\x1b[0m\n", t)
+}
+
+func TestConsoleJSONNotTerminal(t *testing.T) {
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	jl.SetConsoleJSON(true)
+
+	//A bytes.Buffer is not a terminal so the output stays JSON
+	jl.Error("payment declined", map[string]interface{}{"order": 42})
+	line := decodeJSONLine(buf.Bytes(), t)
+	if line["message"] != "payment declined" || line["order"] != 42.0 {
+		t.Error("unexpected line", line)
+	}
+
+	//Forced on and then switched off
+	buf.Reset()
+	jl.isTerminal = func() bool { return true }
+	jl.SetConsoleJSON(false)
+	jl.Info("plain")
+	if line := decodeJSONLine(buf.Bytes(), t); line["message"] != "plain" {
+		t.Error("unexpected line", line)
+	}
+
+	buf.Reset()
+	jl.isTerminal = func() bool { return false }
+	jl.SetConsoleJSON(true)
+	jl.Info("piped")
+	if line := decodeJSONLine(buf.Bytes(), t); line["message"] != "piped" {
+		t.Error("unexpected line", line)
+	}
+}
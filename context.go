@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"context"
+	"sync"
+)
+
+//ContextLogger is implemented by loggers that can pick up fields from a context
+type ContextLogger interface {
+	Logger
+	//WithContext returns a child logger carrying the registered context values as fields
+	WithContext(ctx context.Context) Logger
+}
+
+//contextField maps a context key to the field it is written as
+type contextField struct {
+	key  interface{}
+	name string
+}
+
+var (
+	contextMu     sync.RWMutex
+	contextFields []contextField
+	spanExtractor SpanExtractor
+)
+
+//SpanExtractor returns the IDs of the trace span carried by ctx, ok is false when there is none
+type SpanExtractor func(ctx context.Context) (traceID, spanID string, ok bool)
+
+//RegisterSpanExtractor makes WithContext add the trace_id and span_id fields for contexts carrying a span
+//This keeps tracing libraries out of the package's dependencies, e.g. for OpenTelemetry:
+//
+//	logger.RegisterSpanExtractor(func(ctx context.Context) (string, string, bool) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		return sc.TraceID().String(), sc.SpanID().String(), sc.IsValid()
+//	})
+//
+//A nil extractor stops adding the fields
+func RegisterSpanExtractor(f SpanExtractor) {
+	contextMu.Lock()
+	defer contextMu.Unlock()
+	spanExtractor = f
+}
+
+//RegisterContextField declares that the value stored in a context under key is written as fieldName
+//by WithContext, e.g. RegisterContextField(requestIDKey, "request_id")
+func RegisterContextField(key interface{}, fieldName string) {
+	contextMu.Lock()
+	defer contextMu.Unlock()
+	contextFields = append(contextFields, contextField{key: key, name: fieldName})
+}
+
+//fieldsFromContext returns the registered values present in ctx, missing keys are skipped,
+//and the trace and span IDs when a span extractor finds a span
+func fieldsFromContext(ctx context.Context) map[string]interface{} {
+	contextMu.RLock()
+	defer contextMu.RUnlock()
+	fields := make(map[string]interface{})
+	for _, cf := range contextFields {
+		if v := ctx.Value(cf.key); v != nil {
+			fields[cf.name] = v
+		}
+	}
+	if spanExtractor != nil {
+		if traceID, spanID, ok := spanExtractor(ctx); ok {
+			fields["trace_id"] = traceID
+			fields["span_id"] = spanID
+		}
+	}
+	return fields
+}
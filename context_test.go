@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+type testContextKey string
+
+func TestWithContext(t *testing.T) {
+	RegisterContextField(testContextKey("request_id"), "request_id")
+	RegisterContextField(testContextKey("missing"), "missing")
+	ctx := context.WithValue(context.Background(), testContextKey("request_id"), "abc")
+
+	stdLog := new(StdLog)
+	output := captureOutput(func() {
+		stdLog.WithContext(ctx).Info("This is a message")
+	})
+	testOutput(output, "Info This is a message request_id=abc\n", t)
+
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	var cl ContextLogger = jl
+	cl.WithContext(ctx).Error("This is a message")
+	m := decodeJSONLine(buf.Bytes(), t)
+	if m["request_id"] != "abc" {
+		t.Error("expected the context value as a field", m)
+	}
+	if _, ok := m["missing"]; ok {
+		t.Error("missing context values must be skipped", m)
+	}
+}
+
+//fakeSpan stands in for a tracing library's span context
+type fakeSpan struct {
+	traceID, spanID string
+}
+
+type fakeSpanKey struct{}
+
+func TestWithContextSpan(t *testing.T) {
+	RegisterSpanExtractor(func(ctx context.Context) (string, string, bool) {
+		span, ok := ctx.Value(fakeSpanKey{}).(fakeSpan)
+		return span.traceID, span.spanID, ok
+	})
+	defer RegisterSpanExtractor(nil)
+
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	ctx := context.WithValue(context.Background(), fakeSpanKey{}, fakeSpan{"4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"})
+	jl.WithContext(ctx).Info("This is a message")
+	m := decodeJSONLine(buf.Bytes(), t)
+	if m["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" || m["span_id"] != "00f067aa0ba902b7" {
+		t.Error("expected the span IDs as fields", m)
+	}
+
+	//Nothing is added without a span
+	buf.Reset()
+	jl.WithContext(context.Background()).Info("This is a message")
+	m = decodeJSONLine(buf.Bytes(), t)
+	if _, ok := m["trace_id"]; ok {
+		t.Error("expected no trace_id without a span", m)
+	}
+	if _, ok := m["span_id"]; ok {
+		t.Error("expected no span_id without a span", m)
+	}
+}
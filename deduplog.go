@@ -0,0 +1,185 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+//DedupLog wraps a Logger and collapses identical consecutive entries
+//The first entry is written straight away, repeats are counted and reported as a single
+//"(last message repeated N times)" entry once a different entry arrives, the timeout passes, or on Flush or Close
+type DedupLog struct {
+	mu      sync.Mutex
+	logger  Logger
+	level   string
+	key     string
+	repeats int
+	timeout time.Duration
+	timer   *time.Timer
+	clock   Clock
+	//due is when pending repeats are reported by the logger's clock
+	due time.Time
+}
+
+//NewDedupLog returns a DedupLog that collapses repeated entries before writing them to l
+func NewDedupLog(l Logger) *DedupLog {
+	return &DedupLog{logger: l}
+}
+
+//SetTimeout reports pending repeats after d even if no different entry arrives, 0 (the default) waits indefinitely
+func (s *DedupLog) SetTimeout(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timeout = d
+}
+
+//SetClock sets the clock the timeout is measured with
+//With a fixed clock pending repeats are only reported once the clock is advanced past the timeout
+func (s *DedupLog) SetClock(c Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = c
+}
+
+//flushDue reports pending repeats if the timeout has passed, otherwise it checks again after another timeout
+func (s *DedupLog) flushDue() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer == nil {
+		return
+	}
+	if clockNow(s.clock).Before(s.due) {
+		s.timer.Reset(s.timeout)
+		return
+	}
+	s.flushRepeats()
+}
+
+//flushRepeats reports pending repeats, it must be called with mu held
+func (s *DedupLog) flushRepeats() {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if s.repeats == 0 {
+		return
+	}
+	s.logger.Log(s.level, fmt.Sprintf("(last message repeated %d times)", s.repeats))
+	s.repeats = 0
+}
+
+//Flush reports pending repeats and flushes the wrapped logger
+func (s *DedupLog) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flushRepeats()
+	if fc, ok := s.logger.(FlushCloser); ok {
+		return fc.Flush()
+	}
+	return nil
+}
+
+//Close reports pending repeats and closes the wrapped logger
+func (s *DedupLog) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flushRepeats()
+	s.key = ""
+	if c, ok := s.logger.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+//Enabled reports whether the wrapped logger would write an entry at level
+func (s *DedupLog) Enabled(level string) bool {
+	return enabled(s.logger, level)
+}
+
+//Init initializes the wrapped logger
+func (s *DedupLog) Init() error {
+	return s.logger.Init()
+}
+
+//OnInit registers initializers on the wrapped logger
+func (s *DedupLog) OnInit(f ...interface{}) {
+	s.logger.OnInit(f...)
+}
+
+//OnInitLogger registers an initializer on the wrapped logger, it is called with the wrapped logger
+func (s *DedupLog) OnInitLogger(f func(s Logger)) {
+	s.OnInit(f)
+}
+
+func (s *DedupLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *DedupLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *DedupLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *DedupLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *DedupLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *DedupLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *DedupLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *DedupLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *DedupLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *DedupLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *DedupLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *DedupLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *DedupLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *DedupLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *DedupLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *DedupLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *DedupLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+
+//Log writes the entry unless it repeats the previous one, entries are compared by level and formatted arguments
+func (s *DedupLog) Log(level string, v ...interface{}) {
+	key := level + "\x00" + fmt.Sprintf("%v", v)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key == s.key {
+		s.repeats++
+		if s.timeout > 0 && s.timer == nil {
+			s.due = clockNow(s.clock).Add(s.timeout)
+			s.timer = time.AfterFunc(s.timeout, s.flushDue)
+		}
+		return
+	}
+	s.flushRepeats()
+	s.level = level
+	s.key = key
+	s.logger.Log(level, v...)
+}
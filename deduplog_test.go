@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDedupLog(t *testing.T) {
+	ml := new(MemoryLog)
+	dl := NewDedupLog(ml)
+
+	dl.Info("connection refused")
+	dl.Info("connection refused")
+	dl.Info("connection refused")
+	dl.Info("connected")
+
+	//The two messages are written once each, with the repeats reported in between
+	entries := ml.Entries()
+	if len(entries) != 3 {
+		t.Fatal("expected 3 entries, got", entries)
+	}
+	if entries[0].Args[0] != "connection refused" || entries[2].Args[0] != "connected" {
+		t.Error("unexpected entries", entries)
+	}
+	if entries[1].Level != "Info" || entries[1].Args[0] != "(last message repeated 2 times)" {
+		t.Error("unexpected repeat notice", entries[1])
+	}
+
+	//Same message at a different level is not a repeat
+	dl.Error("connected")
+	if n := len(ml.Entries()); n != 4 {
+		t.Error("expected a different level to be written, got", n)
+	}
+
+	dl.Error("connected")
+	dl.Close()
+	if last, _ := ml.LastEntry(); last.Args[0] != "(last message repeated 1 times)" {
+		t.Error("expected Close to report pending repeats", last)
+	}
+}
+
+func TestDedupLogTimeout(t *testing.T) {
+	ml := new(MemoryLog)
+	dl := NewDedupLog(ml)
+	dl.SetTimeout(10 * time.Millisecond)
+
+	dl.Warning("disk almost full")
+	dl.Warning("disk almost full")
+	deadline := time.Now().Add(time.Second)
+	for len(ml.Entries()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if last, _ := ml.LastEntry(); last.Args[0] != "(last message repeated 1 times)" {
+		t.Error("expected the timeout to report pending repeats", last)
+	}
+}
+
+func TestDedupLogTimeoutClock(t *testing.T) {
+	ml := new(MemoryLog)
+	clock := newFakeClock(time.Date(2017, 6, 29, 12, 0, 0, 0, time.UTC))
+	dl := NewDedupLog(ml)
+	dl.SetClock(clock)
+	dl.SetTimeout(5 * time.Millisecond)
+
+	dl.Warning("disk almost full")
+	dl.Warning("disk almost full")
+	//The clock has not moved so the repeats stay pending however long we wait
+	time.Sleep(30 * time.Millisecond)
+	if n := len(ml.Entries()); n != 1 {
+		t.Fatal("expected the repeats to wait for the clock, got entries", n)
+	}
+
+	clock.Advance(5 * time.Millisecond)
+	deadline := time.Now().Add(time.Second)
+	for len(ml.Entries()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if last, _ := ml.LastEntry(); last.Args[0] != "(last message repeated 1 times)" {
+		t.Error("expected the timeout to report pending repeats once the clock moved", last)
+	}
+}
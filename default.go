@@ -0,0 +1,73 @@
+package logger
+
+import "sync"
+
+var (
+	stdMu sync.RWMutex
+	std   Logger = new(StdLog)
+)
+
+//SetDefault replaces the logger used by the package level functions
+//It is safe to call while other goroutines are logging
+func SetDefault(l Logger) {
+	stdMu.Lock()
+	defer stdMu.Unlock()
+	std = l
+}
+
+//Default returns the logger used by the package level functions, a StdLog unless SetDefault has been called
+func Default() Logger {
+	stdMu.RLock()
+	defer stdMu.RUnlock()
+	return std
+}
+
+//Enabled reports whether the default logger would write an entry at level
+func Enabled(level string) bool {
+	return enabled(Default(), level)
+}
+
+//Emergency logs to the default logger
+func Emergency(v ...interface{}) {
+	Default().Emergency(v...)
+}
+
+//Alert logs to the default logger
+func Alert(v ...interface{}) {
+	Default().Alert(v...)
+}
+
+//Critical logs to the default logger
+func Critical(v ...interface{}) {
+	Default().Critical(v...)
+}
+
+//Error logs to the default logger
+func Error(v ...interface{}) {
+	Default().Error(v...)
+}
+
+//Warning logs to the default logger
+func Warning(v ...interface{}) {
+	Default().Warning(v...)
+}
+
+//Notice logs to the default logger
+func Notice(v ...interface{}) {
+	Default().Notice(v...)
+}
+
+//Info logs to the default logger
+func Info(v ...interface{}) {
+	Default().Info(v...)
+}
+
+//Debug logs to the default logger
+func Debug(v ...interface{}) {
+	Default().Debug(v...)
+}
+
+//Log logs to the default logger at an arbitrary level
+func Log(level string, v ...interface{}) {
+	Default().Log(level, v...)
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+//recordLog records each call as "Level [args]"
+type recordLog struct {
+	StdLog
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *recordLog) Emergency(v ...interface{}) { r.Log("Emergency", v...) }
+func (r *recordLog) Alert(v ...interface{})     { r.Log("Alert", v...) }
+func (r *recordLog) Critical(v ...interface{})  { r.Log("Critical", v...) }
+func (r *recordLog) Error(v ...interface{})     { r.Log("Error", v...) }
+func (r *recordLog) Warning(v ...interface{})   { r.Log("Warning", v...) }
+func (r *recordLog) Notice(v ...interface{})    { r.Log("Notice", v...) }
+func (r *recordLog) Info(v ...interface{})      { r.Log("Info", v...) }
+func (r *recordLog) Debug(v ...interface{})     { r.Log("Debug", v...) }
+func (r *recordLog) Log(level string, v ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, fmt.Sprintf("%s %v", level, v))
+}
+
+func TestDefaultLogger(t *testing.T) {
+	rec := new(recordLog)
+	previous := Default()
+	SetDefault(rec)
+	defer SetDefault(previous)
+
+	Emergency("a")
+	Alert("b")
+	Critical("c")
+	Error("d")
+	Warning("e")
+	Notice("f")
+	Info("g")
+	Debug("h")
+	Log("custom", "i")
+
+	expected := []string{"Emergency [a]", "Alert [b]", "Critical [c]", "Error [d]", "Warning [e]", "Notice [f]", "Info [g]", "Debug [h]", "custom [i]"}
+	if fmt.Sprint(rec.lines) != fmt.Sprint(expected) {
+		t.Error("unexpected lines", rec.lines)
+	}
+}
+
+func TestDefaultLoggerStdLog(t *testing.T) {
+	output := captureOutput(func() {
+		Info("This is a message")
+	})
+	testOutput(output, "Info This is a message\n", t)
+}
+
+func TestSetDefaultConcurrent(t *testing.T) {
+	previous := Default()
+	defer SetDefault(previous)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			SetDefault(new(recordLog))
+		}()
+		go func() {
+			defer wg.Done()
+			Info("This is a message")
+		}()
+	}
+	wg.Wait()
+}
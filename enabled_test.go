@@ -0,0 +1,93 @@
+package logger
+
+import "testing"
+
+var (
+	_ EnabledLogger = new(StdLog)
+	_ EnabledLogger = new(FileLog)
+	_ EnabledLogger = new(NopLog)
+	_ EnabledLogger = new(Stack)
+	_ EnabledLogger = new(LevelLog)
+	_ EnabledLogger = new(SyncLog)
+	_ EnabledLogger = new(AsyncLog)
+)
+
+func TestEnabled(t *testing.T) {
+	levels := []string{"Emergency", "Alert", "Critical", "Error", "Warning", "Notice", "Info", "Debug"}
+	for i, threshold := range levels {
+		ml := new(MemoryLog)
+		ml.SetLevel(threshold)
+		for j, level := range levels {
+			if got, want := ml.Enabled(level), j <= i; got != want {
+				t.Errorf("threshold %s: expected Enabled(%s) to be %v", threshold, level, want)
+			}
+		}
+		//Custom levels are never filtered
+		if !ml.Enabled("custom level") {
+			t.Errorf("threshold %s: expected custom levels to be enabled", threshold)
+		}
+	}
+
+	ml := new(MemoryLog)
+	if !ml.Enabled("Debug") {
+		t.Error("expected every level to be enabled without a threshold")
+	}
+	if new(NopLog).Enabled("Emergency") {
+		t.Error("expected NopLog to never be enabled")
+	}
+}
+
+func TestEnabledDecorators(t *testing.T) {
+	ml := new(MemoryLog)
+	ml.SetLevel("Info")
+
+	ll := NewLevelLog(ml, "Warning")
+	if ll.Enabled("Notice") || !ll.Enabled("Warning") {
+		t.Error("expected LevelLog to apply its own minimum level")
+	}
+	ll.SetLevel("Debug")
+	if ll.Enabled("Debug") || !ll.Enabled("Info") {
+		t.Error("expected LevelLog to apply the wrapped logger's threshold")
+	}
+
+	sl := NewSyncLog(ml)
+	if sl.Enabled("Debug") || !sl.Enabled("Info") {
+		t.Error("expected SyncLog to report the wrapped logger's threshold")
+	}
+}
+
+func TestStackEnabled(t *testing.T) {
+	stack := new(Stack)
+	if stack.Enabled("Emergency") {
+		t.Error("expected an empty stack to never be enabled")
+	}
+
+	errorsOnly := new(MemoryLog)
+	errorsOnly.SetLevel("Error")
+	infoAndUp := new(MemoryLog)
+	infoAndUp.SetLevel("Info")
+	stack.Add(errorsOnly, infoAndUp)
+
+	if !stack.Enabled("Error") || !stack.Enabled("Info") {
+		t.Error("expected the stack to be enabled when any member is")
+	}
+	if stack.Enabled("Debug") {
+		t.Error("expected the stack to be disabled when no member is")
+	}
+
+	//Members that can not report are assumed to write everything
+	stack.Add(struct{ Logger }{new(NopLog)})
+	if !stack.Enabled("Debug") {
+		t.Error("expected a member without Enabled to count as enabled")
+	}
+}
+
+func TestDefaultEnabled(t *testing.T) {
+	defer SetDefault(Default())
+	ml := new(MemoryLog)
+	ml.SetLevel("Warning")
+	SetDefault(ml)
+	if Enabled("Info") || !Enabled("Error") {
+		t.Error("expected Enabled to report the default logger's threshold")
+	}
+}
@@ -0,0 +1,68 @@
+package logger
+
+import "time"
+
+//Entry is a single log call in a form that sinks outside of this package can build on
+//MemoryLog and RingLog record entries and FormatEntry renders one with any Formatter
+type Entry struct {
+	Time time.Time
+	//Level is the level name as it was logged, e.g. "Warning"
+	Level string
+	//Severity is the severity of Level, LevelInfo for levels that are not known
+	Severity Severity
+	//Message is the arguments joined with spaces
+	Message string
+	//Fields holds the structured fields, including "logger" and "caller" when they are set
+	Fields map[string]interface{}
+	//Args are the arguments as they were logged, after lazy values are resolved and redaction is applied
+	Args []interface{}
+}
+
+//NewEntry returns an entry stamped with the current time, joining args into the message the same way as the text loggers
+func NewEntry(level string, fields map[string]interface{}, args []interface{}) Entry {
+	return entryAt(clockNow(nil), level, fields, args)
+}
+
+//entryAt returns the entry for a call made at t
+func entryAt(t time.Time, level string, fields map[string]interface{}, args []interface{}) Entry {
+	severity, err := ParseLevel(level)
+	if err != nil {
+		severity = LevelInfo
+	}
+	return Entry{
+		Time:     t,
+		Level:    level,
+		Severity: severity,
+		Message:  joinArgs(args),
+		Fields:   fields,
+		Args:     args,
+	}
+}
+
+//FormatEntry renders e with f, e.g. FormatEntry(JSONFormatter{}, e)
+//Entries built without Args are formatted from their Message
+func FormatEntry(f Formatter, e Entry) ([]byte, error) {
+	args := e.Args
+	if args == nil {
+		args = []interface{}{e.Message}
+	}
+	return f.Format(e.Level, e.Fields, args)
+}
+
+//ReplayEntry logs e to dst at its level, the entry's fields are added with WithFields
+//or passed as a trailing map argument when dst does not implement FieldLogger
+//dst stamps the entry with its own time
+func ReplayEntry(dst Logger, e Entry) {
+	args := e.Args
+	if args == nil {
+		args = []interface{}{e.Message}
+	}
+	if len(e.Fields) > 0 {
+		if fl, ok := dst.(FieldLogger); ok {
+			fl.WithFields(e.Fields).Log(e.Level, args...)
+			return
+		}
+		args = append(append([]interface{}(nil), args...), e.Fields)
+	}
+	dst.Log(e.Level, args...)
+}
@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	_ Logger = new(FmtLog)
+	_ Logger = new(StdLog)
+	_ Logger = new(FileLog)
+	_ Logger = new(JSONLog)
+	_ Logger = new(LogfmtLog)
+	_ Logger = new(WriterLog)
+	_ Logger = new(NetLog)
+	_ Logger = new(ESLog)
+	_ Logger = new(OTelLog)
+	_ Logger = new(WebhookLog)
+	_ Logger = new(GobLog)
+	_ Logger = new(MemoryLog)
+	_ Logger = new(RingLog)
+	_ Logger = new(ChannelLog)
+	_ Logger = new(SyslogLog)
+	_ Logger = new(EventLog)
+	_ Logger = new(JournaldLog)
+	_ Logger = new(NopLog)
+	_ Logger = new(Stack)
+	_ Logger = new(SyncLog)
+	_ Logger = new(LevelLog)
+	_ Logger = new(AsyncLog)
+	_ Logger = new(RateLimitLog)
+	_ Logger = new(DedupLog)
+	_ Logger = new(SampleLog)
+	_ Logger = new(FilterLog)
+	_ Logger = new(SummaryLog)
+	_ Logger = new(FingerprintLog)
+)
+
+func TestNewEntry(t *testing.T) {
+	before := time.Now()
+	fields := map[string]interface{}{"user": "bob"}
+	e := NewEntry("Warning", fields, []interface{}{"disk", 95, errors.New("full")})
+	if e.Time.Before(before) || e.Time.After(time.Now()) {
+		t.Error("unexpected time", e.Time)
+	}
+	if e.Level != "Warning" || e.Severity != LevelWarning {
+		t.Error("unexpected level", e.Level, e.Severity)
+	}
+	if e.Message != "disk 95 full" {
+		t.Error("unexpected message", e.Message)
+	}
+	if e.Fields["user"] != "bob" || len(e.Args) != 3 {
+		t.Error("unexpected fields or args", e.Fields, e.Args)
+	}
+
+	if e := NewEntry("custom level", nil, nil); e.Severity != LevelInfo || e.Message != "" {
+		t.Error("unexpected entry for an unknown level", e)
+	}
+}
+
+func TestMemoryLogEntry(t *testing.T) {
+	ml := new(MemoryLog)
+	ml.SetPrefix("db")
+	ml.Error("query failed", 3)
+	e, ok := ml.LastEntry()
+	if !ok {
+		t.Fatal("expected an entry")
+	}
+	if e.Severity != LevelError || e.Message != "query failed 3" || e.Fields["logger"] != "db" || e.Time.IsZero() {
+		t.Error("unexpected entry", e)
+	}
+}
+
+func TestFormatEntry(t *testing.T) {
+	e := NewEntry("Info", map[string]interface{}{"k": "v"}, []interface{}{"This is a message"})
+	b, err := FormatEntry(TextFormatter{}, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testOutput(string(b), "Info This is a message k=v\n", t)
+
+	//Entries built by hand only need a message
+	b, err = FormatEntry(JSONFormatter{}, Entry{Level: "Error", Message: "This is a message"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := decodeJSONLine(b, t)
+	if line["message"] != "This is a message" || line["level"] != "Error" {
+		t.Error("unexpected JSON", line)
+	}
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+//InitFromEnv configures the logger from environment variables when Init runs, so the level can be changed without recompiling
+//e.g. InitFromEnv("APP") reads
+//
+//	APP_LEVEL	the minimum level as for SetLevel, e.g. "warning"
+//	APP_FORMAT	"text", "json" or "logfmt", for loggers with SetFormatter such as WriterLog and FileLog
+//
+//Unset variables are ignored, invalid values are reported as a Warning through the package level logger
+//and the logger keeps its own configuration
+func (l *LogBase) InitFromEnv(prefix string) {
+	l.OnInitLogger(func(s Logger) {
+		l.applyEnv(s, prefix)
+	})
+}
+
+//envFormatters holds the formatters that can be selected with <PREFIX>_FORMAT
+var envFormatters = map[string]Formatter{
+	"text":   nil,
+	"json":   JSONFormatter{},
+	"logfmt": LogfmtFormatter{},
+}
+
+//applyEnv applies the <PREFIX>_LEVEL and <PREFIX>_FORMAT variables to s, whose LogBase is l
+func (l *LogBase) applyEnv(s Logger, prefix string) {
+	if key, level := envValue(prefix, "LEVEL"); level != "" {
+		if _, err := ParseLevel(level); err != nil {
+			Warning(fmt.Sprintf("ignoring %s=%q, it is not a known level", key, level))
+		} else {
+			l.SetLevel(level)
+		}
+	}
+	if key, format := envValue(prefix, "FORMAT"); format != "" {
+		f, known := envFormatters[strings.ToLower(format)]
+		fs, ok := s.(interface{ SetFormatter(Formatter) })
+		switch {
+		case !known:
+			Warning(fmt.Sprintf("ignoring %s=%q, the format must be text, json or logfmt", key, format))
+		case !ok:
+			Warning(fmt.Sprintf("ignoring %s=%q, %T does not support formatters", key, format, s))
+		default:
+			fs.SetFormatter(f)
+		}
+	}
+}
+
+//envValue returns the name of the variable for prefix and name, e.g. APP_LEVEL, and its trimmed value
+func envValue(prefix, name string) (key, value string) {
+	key = name
+	if prefix != "" {
+		key = strings.ToUpper(prefix) + "_" + name
+	}
+	return key, strings.TrimSpace(os.Getenv(key))
+}
@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestInitFromEnvLevel(t *testing.T) {
+	os.Setenv("TESTAPP_LEVEL", "warning")
+	defer os.Unsetenv("TESTAPP_LEVEL")
+
+	ml := new(MemoryLog)
+	ml.InitFromEnv("testapp")
+	if err := ml.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	if ml.Enabled("Notice") || !ml.Enabled("Warning") {
+		t.Error("expected the threshold to be Warning")
+	}
+	ml.Info("hidden")
+	ml.Error("shown")
+	if len(ml.Entries()) != 1 {
+		t.Error("unexpected entries", ml.Entries())
+	}
+}
+
+func TestInitFromEnvInvalid(t *testing.T) {
+	rec := new(MemoryLog)
+	previous := Default()
+	SetDefault(rec)
+	defer SetDefault(previous)
+
+	os.Setenv("TESTAPP_LEVEL", "loud")
+	os.Setenv("TESTAPP_FORMAT", "xml")
+	defer os.Unsetenv("TESTAPP_LEVEL")
+	defer os.Unsetenv("TESTAPP_FORMAT")
+
+	wl := new(WriterLog)
+	wl.SetLevel("Error")
+	wl.InitFromEnv("TESTAPP")
+	if err := wl.Init(); err != nil {
+		t.Fatal("Init must not fail on invalid values", err)
+	}
+	if wl.Enabled("Warning") || !wl.Enabled("Error") {
+		t.Error("expected the configured threshold to be kept")
+	}
+	entries := rec.Entries()
+	if len(entries) != 2 {
+		t.Fatal("expected a warning for each invalid value, got", entries)
+	}
+	for _, e := range entries {
+		if e.Level != "Warning" {
+			t.Error("unexpected level", e.Level)
+		}
+	}
+	if !strings.Contains(entries[0].Message, `TESTAPP_LEVEL="loud"`) || !strings.Contains(entries[1].Message, `TESTAPP_FORMAT="xml"`) {
+		t.Error("unexpected warnings", entries[0].Message, entries[1].Message)
+	}
+}
+
+func TestInitFromEnvFormat(t *testing.T) {
+	os.Setenv("TESTAPP_FORMAT", "JSON")
+	defer os.Unsetenv("TESTAPP_FORMAT")
+
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	wl.InitFromEnv("TESTAPP")
+	if err := wl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	wl.Info("a")
+	if m := decodeJSONLine(buf.Bytes(), t); m["message"] != "a" {
+		t.Error("expected JSON output, got", buf.String())
+	}
+
+	//Loggers without formatters keep their output and get a warning
+	rec := new(MemoryLog)
+	previous := Default()
+	SetDefault(rec)
+	defer SetDefault(previous)
+	ml := new(MemoryLog)
+	ml.InitFromEnv("TESTAPP")
+	if err := ml.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	if len(rec.Entries()) != 1 {
+		t.Error("expected a warning, got", rec.Entries())
+	}
+}
+
+func TestInitFromEnvStack(t *testing.T) {
+	os.Setenv("TESTAPP_LEVEL", "error")
+	defer os.Unsetenv("TESTAPP_LEVEL")
+
+	ml := new(MemoryLog)
+	stack := new(Stack)
+	stack.InitFromEnv("testapp")
+	if err := stack.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	stack.Add(ml)
+	if stack.Enabled("Debug") || stack.Enabled("Warning") || !stack.Enabled("Error") {
+		t.Error("expected the stack threshold to be Error")
+	}
+	stack.Debug("hidden")
+	stack.Warning("hidden")
+	stack.LogAll("Info", "hidden")
+	stack.LogTo([]int{0}, "Notice", "hidden")
+	stack.Error("shown")
+	if entries := ml.Entries(); len(entries) != 1 || entries[0].Level != "Error" {
+		t.Error("unexpected entries", entries)
+	}
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//errorText renders err with every layer of its chain
+//Errors implementing fmt.Formatter are rendered with %+v so that details such as stack traces are kept,
+//otherwise each wrapped error whose message is not already part of the outer message is appended
+func errorText(err error) string {
+	if _, ok := err.(fmt.Formatter); ok {
+		return fmt.Sprintf("%+v", err)
+	}
+	text := err.Error()
+	for inner := errors.Unwrap(err); inner != nil; inner = errors.Unwrap(inner) {
+		if msg := inner.Error(); !strings.Contains(text, msg) {
+			text += ": " + msg
+		}
+	}
+	return text
+}
+
+//errorArgs returns v with any error arguments replaced by their errorText
+func errorArgs(v []interface{}) []interface{} {
+	var args []interface{}
+	for i, arg := range v {
+		err, ok := arg.(error)
+		if !ok || err == nil {
+			continue
+		}
+		if args == nil {
+			args = make([]interface{}, len(v))
+			copy(args, v)
+		}
+		args[i] = errorText(err)
+	}
+	if args == nil {
+		return v
+	}
+	return args
+}
+
+//errorFields returns the error and cause fields the structured loggers write for the first error argument
+//cause lists the message of each wrapped error, outermost first, and is left out when nothing is wrapped
+func errorFields(v []interface{}) map[string]interface{} {
+	for _, arg := range v {
+		err, ok := arg.(error)
+		if !ok || err == nil {
+			continue
+		}
+		fields := map[string]interface{}{"error": errorText(err)}
+		var causes []string
+		for inner := errors.Unwrap(err); inner != nil; inner = errors.Unwrap(inner) {
+			causes = append(causes, inner.Error())
+		}
+		if len(causes) > 0 {
+			fields["cause"] = causes
+		}
+		return fields
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+//opaqueError wraps an error without including its message
+type opaqueError struct {
+	err error
+}
+
+func (e opaqueError) Error() string { return "request failed" }
+func (e opaqueError) Unwrap() error { return e.err }
+
+//detailedError implements fmt.Formatter, printing extra detail for %+v like pkg/errors
+type detailedError struct{}
+
+func (e detailedError) Error() string { return "detailed" }
+func (e detailedError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprint(s, "detailed\nwith more detail")
+		return
+	}
+	fmt.Fprint(s, "detailed")
+}
+
+func TestErrorArgsText(t *testing.T) {
+	root := errors.New("connection refused")
+	wrapped := fmt.Errorf("dial db: %w", root)
+	chain := opaqueError{wrapped}
+
+	stdLog := new(StdLog)
+	output := captureOutput(func() {
+		stdLog.Error("query", wrapped)
+		stdLog.Error(chain)
+		stdLog.Error(detailedError{})
+	})
+	testOutput(output, "Error [query dial db: connection refused]\n"+
+		"Error [request failed: dial db: connection refused]\n"+
+		"Error [detailed\nwith more detail]\n", t)
+}
+
+func TestErrorArgsJSON(t *testing.T) {
+	root := errors.New("connection refused")
+	chain := opaqueError{fmt.Errorf("dial db: %w", root)}
+
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	jl.Error("query failed", chain)
+	entry := decodeJSONLine(buf.Bytes(), t)
+	if entry["error"] != "request failed: dial db: connection refused" {
+		t.Error("unexpected error field", entry["error"])
+	}
+	cause, _ := entry["cause"].([]interface{})
+	if len(cause) != 2 || cause[0] != "dial db: connection refused" || cause[1] != "connection refused" {
+		t.Error("expected each wrapped layer in cause, got", entry["cause"])
+	}
+	if entry["message"] != "query failed request failed: dial db: connection refused" {
+		t.Error("unexpected message", entry["message"])
+	}
+
+	//Unwrapped errors have no cause
+	buf.Reset()
+	jl.Error(root)
+	entry = decodeJSONLine(buf.Bytes(), t)
+	if _, ok := entry["cause"]; ok || entry["error"] != "connection refused" {
+		t.Error("unexpected fields for an unwrapped error", entry)
+	}
+}
+
+func TestErrorArgsLogfmt(t *testing.T) {
+	var buf bytes.Buffer
+	ll := new(LogfmtLog)
+	ll.SetOutput(&buf)
+	ll.Error(fmt.Errorf("dial db: %w", errors.New("refused")))
+	line := buf.String()
+	if !strings.Contains(line, `error="dial db: refused"`) || !strings.Contains(line, `cause="[refused]"`) {
+		t.Error("expected error and cause keys, got", line)
+	}
+}
+
+func TestWithError(t *testing.T) {
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+
+	if jl.WithError(nil) != Logger(jl) {
+		t.Error("expected a nil error to return the same logger")
+	}
+
+	err := fmt.Errorf("insert: %w", errors.New("disk full"))
+	jl.WithError(err).Error("db write failed")
+	entry := decodeJSONLine(buf.Bytes(), t)
+	if entry["message"] != "db write failed" || entry["error"] != "insert: disk full" {
+		t.Error("unexpected entry", entry)
+	}
+	if cause, _ := entry["cause"].([]interface{}); len(cause) != 1 || cause[0] != "disk full" {
+		t.Error("unexpected cause", entry["cause"])
+	}
+
+	output := captureOutput(func() {
+		new(StdLog).WithError(errors.New("disk full")).Error("db write failed")
+	})
+	testOutput(output, "Error db write failed error=disk full\n", t)
+}
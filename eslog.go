@@ -0,0 +1,406 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+//ESLog indexes entries into Elasticsearch, buffering them and sending them to the _bulk endpoint
+//A batch is sent in the background once it holds SetBatchSize entries, every SetFlushInterval and on Flush or Close
+//Failed requests are retried with exponential backoff, failures that persist are passed to the OnError handler
+//Each document is given its own _id so that a retry never indexes a document twice, and when Elasticsearch
+//rejects only some documents of a batch just those are sent again
+type ESLog struct {
+	LogBase
+	url           string
+	index         string
+	client        *http.Client
+	batchSize     int
+	flushInterval time.Duration
+	retries       int
+	minBackoff    time.Duration
+	maxBackoff    time.Duration
+
+	mu sync.Mutex
+	//entries holds the buffered action and document line pairs
+	entries [][]byte
+	//sendMu serializes requests so that batches are indexed in order
+	sendMu sync.Mutex
+	done   chan struct{}
+	//full wakes the periodic flush when a batch is full so that Log does not wait for the request
+	full chan struct{}
+	wg   sync.WaitGroup
+}
+
+//Defaults for ESLog
+const (
+	defaultESIndex         = "logs-{date}"
+	defaultESBatchSize     = 100
+	defaultESFlushInterval = 5 * time.Second
+	defaultESRetries       = 3
+)
+
+//SetURL sets the base URL of the Elasticsearch cluster, e.g. "http://localhost:9200"
+func (s *ESLog) SetURL(url string) {
+	s.url = strings.TrimSuffix(url, "/")
+}
+
+//SetIndex sets the index entries are written to, "logs-{date}" by default
+//{date} is replaced by the entry's date as 2006.01.02 and {date:layout} by the date in any time.Format layout
+func (s *ESLog) SetIndex(index string) {
+	s.index = index
+}
+
+//SetClient sets the HTTP client used for requests, a client with a 10 second timeout is used when none is set
+func (s *ESLog) SetClient(c *http.Client) {
+	s.client = c
+}
+
+//SetBatchSize sets how many entries are buffered before they are sent, 100 by default
+func (s *ESLog) SetBatchSize(n int) {
+	s.batchSize = n
+}
+
+//SetFlushInterval sets how often buffered entries are sent, 5 seconds by default
+func (s *ESLog) SetFlushInterval(d time.Duration) {
+	s.flushInterval = d
+}
+
+//SetRetries sets how many times a failed request is retried before the batch is dropped, 3 by default,
+//a negative value disables retries
+func (s *ESLog) SetRetries(n int) {
+	s.retries = n
+}
+
+//SetBackoff sets the first and the longest delay between retries
+func (s *ESLog) SetBackoff(min, max time.Duration) {
+	s.minBackoff = min
+	s.maxBackoff = max
+}
+
+//Init expects input to be a list of func(s *ESLog), typically used to call SetURL, and then starts the periodic flush
+func (s *ESLog) Init() error {
+	for _, fn := range s.initializers {
+		switch funct := fn.(type) {
+		case func(s *ESLog):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *ESLog) or func(s Logger)")
+		}
+	}
+	if s.url == "" {
+		return errors.New("Elasticsearch URL is not set, SetURL must be called")
+	}
+	if _, err := esIndexName(s.indexPattern(), s.now()); err != nil {
+		return err
+	}
+	interval := s.flushInterval
+	if interval <= 0 {
+		interval = defaultESFlushInterval
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done == nil {
+		s.done = make(chan struct{})
+		s.full = make(chan struct{}, 1)
+		s.wg.Add(1)
+		go s.flushEvery(interval, s.done, s.full)
+	}
+	return nil
+}
+
+//flushEvery sends buffered entries every d and whenever a batch fills up until done is closed
+func (s *ESLog) flushEvery(d time.Duration, done, full chan struct{}) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.Flush()
+		case <-full:
+			s.Flush()
+		case <-done:
+			return
+		}
+	}
+}
+
+//indexPattern returns the configured index or the default
+func (s *ESLog) indexPattern() string {
+	if s.index == "" {
+		return defaultESIndex
+	}
+	return s.index
+}
+
+//esIndexName replaces the {date} and {date:layout} tokens in pattern with t
+func esIndexName(pattern string, t time.Time) (string, error) {
+	var b strings.Builder
+	rest := pattern
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			b.WriteString(rest)
+			return b.String(), nil
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated token in index %q", pattern)
+		}
+		layout := "2006.01.02"
+		switch token := rest[start+1 : start+end]; {
+		case token == "date":
+		case strings.HasPrefix(token, "date:"):
+			layout = strings.TrimPrefix(token, "date:")
+		default:
+			return "", fmt.Errorf("unknown token {%s} in index %q", token, pattern)
+		}
+		b.WriteString(rest[:start])
+		b.WriteString(t.Format(layout))
+		rest = rest[start+end+1:]
+	}
+}
+
+//Flush sends any buffered entries, returning the error if they could not be indexed
+func (s *ESLog) Flush() error {
+	s.mu.Lock()
+	body := s.take()
+	s.mu.Unlock()
+	return s.post(body)
+}
+
+//Close stops the periodic flush and sends any buffered entries
+func (s *ESLog) Close() error {
+	s.mu.Lock()
+	done := s.done
+	s.done = nil
+	s.mu.Unlock()
+	if done != nil {
+		close(done)
+		s.wg.Wait()
+	}
+	return s.Flush()
+}
+
+//take returns the buffered entries and empties the buffer, it must be called with mu held
+func (s *ESLog) take() [][]byte {
+	entries := s.entries
+	s.entries = nil
+	return entries
+}
+
+//post sends entries, retrying with backoff, and reports a persistent failure to the OnError handler
+//Only the entries that failed are sent again
+func (s *ESLog) post(entries [][]byte) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	retries := s.retries
+	if retries < 0 {
+		retries = 0
+	} else if retries == 0 {
+		retries = defaultESRetries
+	}
+	pending := entries
+	err := retry(retries, s.minBackoff, s.maxBackoff, func() error {
+		failed, err := s.bulk(pending)
+		if err == nil && len(failed) > 0 {
+			pending = failed
+			err = fmt.Errorf("bulk request reported errors for %d documents", len(failed))
+		}
+		return err
+	})
+	if err == nil {
+		return nil
+	}
+	err = fmt.Errorf("indexing %d of %d entries into Elasticsearch failed after %d attempts: %w", len(pending), len(entries), retries+1, err)
+	s.handleError(err)
+	return err
+}
+
+//bulk makes a single _bulk request and returns the entries Elasticsearch rejected
+func (s *ESLog) bulk(entries [][]byte) (failed [][]byte, err error) {
+	client := s.client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Post(s.url+"/_bulk", "application/x-ndjson", bytes.NewReader(bytes.Join(entries, nil)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("bulk request returned %s", resp.Status)
+	}
+	//Elasticsearch reports failures of individual documents in a successful response, with an item per entry in order
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+		} `json:"items"`
+	}
+	if json.Unmarshal(b, &result) != nil || !result.Errors {
+		return nil, nil
+	}
+	if len(result.Items) != len(entries) {
+		//The documents that failed can not be told apart, the _ids make sending them all again safe
+		return entries, nil
+	}
+	for i, item := range result.Items {
+		for _, r := range item {
+			if r.Status < 200 || r.Status > 299 {
+				failed = append(failed, entries[i])
+			}
+		}
+	}
+	return failed, nil
+}
+
+//esDocumentID returns a random _id for a document
+func esDocumentID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *ESLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *ESLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *ESLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *ESLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *ESLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *ESLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
+func (s *ESLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *ESLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *ESLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *ESLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *ESLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *ESLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *ESLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *ESLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *ESLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *ESLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *ESLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *ESLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *ESLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *ESLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *ESLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *ESLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *ESLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+
+//Log buffers the entry, the batch is sent in the background once it is full
+func (s *ESLog) Log(level string, v ...interface{}) {
+	if !s.shouldLog(level) {
+		return
+	}
+	t := s.now()
+	index, err := esIndexName(s.indexPattern(), t)
+	if err != nil {
+		s.handleError(err)
+		return
+	}
+	action, _ := json.Marshal(map[string]interface{}{"index": map[string]string{"_index": index, "_id": esDocumentID()}})
+	fields, args := s.prepare(level, v)
+	doc := jsonLine(s.levelName(level), t, fields, args)
+	entry := make([]byte, 0, len(action)+len(doc)+2)
+	entry = append(append(append(append(entry, action...), '\n'), doc...), '\n')
+
+	size := s.batchSize
+	if size <= 0 {
+		size = defaultESBatchSize
+	}
+	s.mu.Lock()
+	s.entries = append(s.entries, entry)
+	if len(s.entries) < size {
+		s.mu.Unlock()
+		return
+	}
+	if s.done != nil {
+		//The periodic flush sends the batch so the caller does not wait for the request and its retries
+		select {
+		case s.full <- struct{}{}:
+		default:
+		}
+		s.mu.Unlock()
+		return
+	}
+	//Without Init, or after Close, there is nothing to hand the batch to
+	entries := s.take()
+	s.mu.Unlock()
+	s.post(entries)
+}
@@ -0,0 +1,276 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+//bulkServer records the bodies of _bulk requests, failing the first failures requests with a 503
+//and rejecting the first rejects documents of the next request with a 429 item
+type bulkServer struct {
+	mu       sync.Mutex
+	bodies   []string
+	types    []string
+	failures int
+	rejects  int
+}
+
+func (b *bulkServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if r.Method != http.MethodPost || r.URL.Path != "/_bulk" {
+		http.NotFound(w, r)
+		return
+	}
+	if b.failures > 0 {
+		b.failures--
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	b.bodies = append(b.bodies, string(body))
+	b.types = append(b.types, r.Header.Get("Content-Type"))
+	docs := strings.Count(string(body), "\n") / 2
+	items := make([]string, docs)
+	for i := range items {
+		items[i] = `{"index":{"status":201}}`
+		if i < b.rejects {
+			items[i] = `{"index":{"status":429,"error":{"type":"es_rejected_execution_exception"}}}`
+		}
+	}
+	fmt.Fprintf(w, `{"took":1,"errors":%t,"items":[%s]}`, b.rejects > 0, strings.Join(items, ","))
+	b.rejects = 0
+}
+
+func (b *bulkServer) requests() ([]string, []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.bodies...), append([]string(nil), b.types...)
+}
+
+func TestESLogBulk(t *testing.T) {
+	bs := new(bulkServer)
+	srv := httptest.NewServer(bs)
+	defer srv.Close()
+
+	el := new(ESLog)
+	el.OnInit(func(s *ESLog) {
+		s.SetURL(srv.URL + "/")
+		s.SetIndex("app-{date}")
+		s.SetBatchSize(2)
+	})
+	if err := el.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	el.SetClock(newFakeClock(time.Date(2017, 6, 29, 12, 30, 0, 0, time.UTC)))
+
+	el.Info("first")
+	if bodies, _ := bs.requests(); len(bodies) != 0 {
+		t.Error("expected the entry to be buffered, got requests", len(bodies))
+	}
+	el.Error("second", map[string]interface{}{"user": "bob"})
+	//The full batch is sent in the background
+	waitForRequests(bs, 1, t)
+	el.Warning("third")
+	if err := el.Close(); err != nil {
+		t.Fatal("Close failed", err)
+	}
+
+	bodies, types := bs.requests()
+	if len(bodies) != 2 {
+		t.Fatal("expected a full batch and the remainder on Close, got", len(bodies))
+	}
+	for _, ct := range types {
+		if ct != "application/x-ndjson" {
+			t.Error("unexpected content type", ct)
+		}
+	}
+
+	//Each entry is an action line followed by the document, and the body ends with a newline
+	if !strings.HasSuffix(bodies[0], "\n") {
+		t.Error("expected the body to end with a newline")
+	}
+	lines := strings.Split(strings.TrimSuffix(bodies[0], "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatal("expected 4 lines, got", len(lines), bodies[0])
+	}
+	var action, second map[string]map[string]string
+	if err := json.Unmarshal([]byte(lines[0]), &action); err != nil || action["index"]["_index"] != "app-2017.06.29" {
+		t.Error("unexpected action line", lines[0], err)
+	}
+	json.Unmarshal([]byte(lines[2]), &second)
+	if len(action["index"]["_id"]) != 32 || action["index"]["_id"] == second["index"]["_id"] {
+		t.Error("expected each document to have its own _id", lines[0], lines[2])
+	}
+	doc := decodeJSONLine([]byte(lines[1]), t)
+	if doc["message"] != "first" || doc["level"] != "Info" {
+		t.Error("unexpected document", lines[1])
+	}
+	doc = decodeJSONLine([]byte(lines[3]), t)
+	if doc["message"] != "second" || doc["user"] != "bob" {
+		t.Error("unexpected document", lines[3])
+	}
+	if !strings.Contains(bodies[1], `"message":"third"`) {
+		t.Error("expected the last entry to be sent on Close", bodies[1])
+	}
+}
+
+func TestESLogRetry(t *testing.T) {
+	bs := &bulkServer{failures: 2}
+	srv := httptest.NewServer(bs)
+	defer srv.Close()
+
+	var handled []error
+	el := new(ESLog)
+	el.SetURL(srv.URL)
+	el.SetBackoff(time.Millisecond, 5*time.Millisecond)
+	el.OnError(func(err error) {
+		handled = append(handled, err)
+	})
+	if err := el.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer el.Close()
+
+	el.Info("This is a message")
+	if err := el.Flush(); err != nil {
+		t.Fatal("expected the retries to succeed", err)
+	}
+	if bodies, _ := bs.requests(); len(bodies) != 1 {
+		t.Error("expected one successful request, got", len(bodies))
+	}
+
+	//Failures beyond the retries are reported
+	bs.mu.Lock()
+	bs.failures = 10
+	bs.mu.Unlock()
+	el.SetRetries(1)
+	el.Info("This is a message")
+	if err := el.Flush(); err == nil {
+		t.Error("expected the flush to fail")
+	}
+	if len(handled) != 1 || !strings.Contains(handled[0].Error(), "503") {
+		t.Error("expected the failure to be passed to OnError, got", handled)
+	}
+}
+
+//waitForRequests waits until bs has received n requests
+func waitForRequests(bs *bulkServer, n int, t *testing.T) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if bodies, _ := bs.requests(); len(bodies) >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for", n, "requests")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestESLogRetryRejected(t *testing.T) {
+	bs := &bulkServer{rejects: 1}
+	srv := httptest.NewServer(bs)
+	defer srv.Close()
+
+	el := new(ESLog)
+	el.SetURL(srv.URL)
+	el.SetBackoff(time.Millisecond, time.Millisecond)
+	if err := el.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer el.Close()
+	el.Info("rejected")
+	el.Info("indexed")
+	if err := el.Flush(); err != nil {
+		t.Fatal("expected the retry to succeed", err)
+	}
+	bodies, _ := bs.requests()
+	if len(bodies) != 2 {
+		t.Fatal("expected the batch and a retry, got", len(bodies))
+	}
+	if !strings.Contains(bodies[1], `"message":"rejected"`) || strings.Contains(bodies[1], `"message":"indexed"`) {
+		t.Error("expected only the rejected document to be sent again, got", bodies[1])
+	}
+	//The retried document keeps its _id
+	if strings.SplitN(bodies[0], "\n", 2)[0] != strings.SplitN(bodies[1], "\n", 2)[0] {
+		t.Error("expected the same action line, got", bodies[0], bodies[1])
+	}
+}
+
+func TestESLogRetriesDisabled(t *testing.T) {
+	bs := &bulkServer{failures: 1}
+	srv := httptest.NewServer(bs)
+	defer srv.Close()
+
+	el := new(ESLog)
+	el.SetURL(srv.URL)
+	el.SetRetries(-1)
+	el.OnError(func(error) {})
+	if err := el.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer el.Close()
+	el.Info("This is a message")
+	if err := el.Flush(); err == nil {
+		t.Error("expected the flush to fail without retries")
+	}
+	if bodies, _ := bs.requests(); len(bodies) != 0 {
+		t.Error("expected no retry, got", len(bodies))
+	}
+}
+
+func TestESLogFullBatchDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	bs := new(bulkServer)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		bs.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	el := new(ESLog)
+	el.SetURL(srv.URL)
+	el.SetBatchSize(1)
+	if err := el.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	logged := make(chan struct{})
+	go func() {
+		el.Info("first")
+		el.Info("second")
+		close(logged)
+	}()
+	select {
+	case <-logged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Log to return while the request is in flight")
+	}
+	close(release)
+	if err := el.Close(); err != nil {
+		t.Fatal(err)
+	}
+	bodies, _ := bs.requests()
+	if all := strings.Join(bodies, ""); !strings.Contains(all, `"first"`) || !strings.Contains(all, `"second"`) {
+		t.Error("expected both entries to be sent, got", bodies)
+	}
+}
+
+func TestESLogInitErrors(t *testing.T) {
+	if err := new(ESLog).Init(); err == nil {
+		t.Error("expected an error without a URL")
+	}
+	el := new(ESLog)
+	el.SetURL("http://localhost:9200")
+	el.SetIndex("logs-{day}")
+	if err := el.Init(); err == nil {
+		t.Error("expected an error for an unknown index token")
+	}
+}
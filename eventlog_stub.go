@@ -0,0 +1,86 @@
+//go:build !windows
+
+package logger
+
+import (
+	"errors"
+	"io"
+)
+
+//errEventLogUnsupported is returned by EventLog on platforms other than Windows
+var errEventLogUnsupported = errors.New("the Windows Event Log is not supported on this platform")
+
+//EventLog is unavailable on this platform, Init always fails and nothing is written
+type EventLog struct {
+	LogBase
+}
+
+//SetSource is a no-op on this platform
+func (s *EventLog) SetSource(source string) {}
+
+//SetEventID is a no-op on this platform
+func (s *EventLog) SetEventID(id uint32) {}
+
+//Init always returns an error on this platform
+func (s *EventLog) Init() error {
+	return errEventLogUnsupported
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+func (s *EventLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *EventLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Close is a no-op on this platform
+func (s *EventLog) Close() error {
+	return nil
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *EventLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *EventLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *EventLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *EventLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
+func (s *EventLog) Emergency(v ...interface{}) {}
+func (s *EventLog) Alert(v ...interface{})     {}
+func (s *EventLog) Critical(v ...interface{})  {}
+func (s *EventLog) Error(v ...interface{})     {}
+func (s *EventLog) Warning(v ...interface{})   {}
+func (s *EventLog) Notice(v ...interface{})    {}
+func (s *EventLog) Info(v ...interface{})      {}
+func (s *EventLog) Debug(v ...interface{})     {}
+func (s *EventLog) Log(level string, v ...interface{}) {
+	s.handleError(errEventLogUnsupported)
+}
+func (s *EventLog) Emergencyf(format string, args ...interface{}) {}
+func (s *EventLog) Alertf(format string, args ...interface{})     {}
+func (s *EventLog) Criticalf(format string, args ...interface{})  {}
+func (s *EventLog) Errorf(format string, args ...interface{})     {}
+func (s *EventLog) Warningf(format string, args ...interface{})   {}
+func (s *EventLog) Noticef(format string, args ...interface{})    {}
+func (s *EventLog) Infof(format string, args ...interface{})      {}
+func (s *EventLog) Debugf(format string, args ...interface{})     {}
+func (s *EventLog) Logf(level string, format string, args ...interface{}) {
+	s.handleError(errEventLogUnsupported)
+}
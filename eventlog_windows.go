@@ -0,0 +1,225 @@
+//go:build windows
+
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	advapi32                  = syscall.NewLazyDLL("advapi32.dll")
+	procRegisterEventSource   = advapi32.NewProc("RegisterEventSourceW")
+	procDeregisterEventSource = advapi32.NewProc("DeregisterEventSource")
+	procReportEvent           = advapi32.NewProc("ReportEventW")
+)
+
+//Event types passed to ReportEvent
+const (
+	eventTypeError       = 0x0001
+	eventTypeWarning     = 0x0002
+	eventTypeInformation = 0x0004
+)
+
+//defaultEventID is the event ID written with each entry unless SetEventID is called
+const defaultEventID = 1
+
+//EventLog writes to the Windows Event Log under an event source, the program name by default
+//Emergency to Error are written as Error events, Warning as Warning events and everything else as Information events
+type EventLog struct {
+	LogBase
+	source  string
+	eventID uint32
+	h       syscall.Handle
+}
+
+//SetSource sets the event source name shown in the Event Viewer
+func (s *EventLog) SetSource(source string) {
+	s.source = source
+}
+
+//SetEventID sets the event ID written with each entry, 1 by default
+func (s *EventLog) SetEventID(id uint32) {
+	s.eventID = id
+}
+
+//Init expects input to be a list of func(s *EventLog) and then registers the event source
+func (s *EventLog) Init() error {
+	for _, fn := range s.initializers {
+		switch funct := fn.(type) {
+		case func(s *EventLog):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *EventLog) or func(s Logger)")
+		}
+	}
+	if s.source == "" {
+		s.source = strings.TrimSuffix(filepath.Base(os.Args[0]), filepath.Ext(os.Args[0]))
+	}
+	source, err := syscall.UTF16PtrFromString(s.source)
+	if err != nil {
+		return err
+	}
+	if s.h != 0 {
+		s.Close()
+	}
+	h, _, err := procRegisterEventSource.Call(0, uintptr(unsafe.Pointer(source)))
+	if h == 0 {
+		return fmt.Errorf("registering event source %q: %w", s.source, err)
+	}
+	s.h = syscall.Handle(h)
+	return nil
+}
+
+//Close deregisters the event source
+func (s *EventLog) Close() error {
+	if s.h == 0 {
+		return nil
+	}
+	r, _, err := procDeregisterEventSource.Call(uintptr(s.h))
+	s.h = 0
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+//eventType returns the event type for level, unknown levels are written as Information
+func eventType(level string) uint16 {
+	sev, err := ParseLevel(level)
+	switch {
+	case err != nil:
+		return eventTypeInformation
+	case sev <= LevelError:
+		return eventTypeError
+	case sev == LevelWarning:
+		return eventTypeWarning
+	}
+	return eventTypeInformation
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *EventLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *EventLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *EventLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *EventLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *EventLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *EventLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
+func (s *EventLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *EventLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *EventLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *EventLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *EventLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *EventLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *EventLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *EventLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+
+func (s *EventLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *EventLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *EventLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *EventLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *EventLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *EventLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *EventLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *EventLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *EventLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+func (s *EventLog) Log(level string, v ...interface{}) {
+	if !s.shouldLog(level) {
+		return
+	}
+	if s.h == 0 {
+		s.handleError(errors.New("event source is not registered, Init must be called first"))
+		return
+	}
+	msg, err := syscall.UTF16PtrFromString(strings.ReplaceAll(s.text(level, v), "\x00", ""))
+	if err != nil {
+		s.handleError(err)
+		return
+	}
+	id := s.eventID
+	if id == 0 {
+		id = defaultEventID
+	}
+	strs := []*uint16{msg}
+	r, _, err := procReportEvent.Call(
+		uintptr(s.h),
+		uintptr(eventType(level)),
+		0,
+		uintptr(id),
+		0,
+		uintptr(len(strs)),
+		0,
+		uintptr(unsafe.Pointer(&strs[0])),
+		0,
+	)
+	if r == 0 {
+		s.handleError(fmt.Errorf("reporting event: %w", err))
+	}
+}
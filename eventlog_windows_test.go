@@ -0,0 +1,62 @@
+//go:build windows
+
+package logger
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventLog(t *testing.T) {
+	el := new(EventLog)
+	el.OnInit(func(s *EventLog) {
+		s.SetSource("logger-test")
+	})
+	if err := el.Init(); err != nil {
+		t.Skip("could not register an event source", err)
+	}
+	var failed error
+	el.OnError(func(err error) {
+		failed = err
+	})
+	marker := fmt.Sprintf("logger test %d", time.Now().UnixNano())
+	el.Warning(marker)
+	el.Error(marker)
+	el.Info(marker)
+	if failed != nil {
+		t.Fatal("writing the event failed", failed)
+	}
+	if err := el.Close(); err != nil {
+		t.Error("Close failed", err)
+	}
+
+	//Reading the event back is best effort, wevtutil may be missing or the log unreadable
+	out, err := exec.Command("wevtutil", "qe", "Application", "/c:20", "/rd:true", "/f:text",
+		"/q:*[System[Provider[@Name='logger-test']]]").Output()
+	if err != nil {
+		t.Log("could not read the Application log", err)
+		return
+	}
+	if !strings.Contains(string(out), marker) {
+		t.Error("expected the event in the Application log, got", string(out))
+	}
+}
+
+func TestEventLogType(t *testing.T) {
+	cases := map[string]uint16{
+		"Emergency":    eventTypeError,
+		"Error":        eventTypeError,
+		"Warning":      eventTypeWarning,
+		"Notice":       eventTypeInformation,
+		"Debug":        eventTypeInformation,
+		"custom level": eventTypeInformation,
+	}
+	for level, want := range cases {
+		if got := eventType(level); got != want {
+			t.Error("unexpected event type for", level, got)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+)
+
+//FatalLogger is implemented by loggers that can log an entry and then exit the process
+type FatalLogger interface {
+	Logger
+	//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+	Fatal(v ...interface{})
+	//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+	Fatalf(format string, args ...interface{})
+}
+
+//SetExitFunc sets the function Fatal calls with the exit status once the entry is written, os.Exit by default
+//Tests can use it to stub the exit
+func (l *LogBase) SetExitFunc(f func(int)) {
+	l.exit = f
+}
+
+//fatal writes v at Emergency to lg, flushes lg if it buffers and then exits with status 1
+func (l *LogBase) fatal(lg Logger, v []interface{}) {
+	lg.Log("Emergency", v...)
+	if fc, ok := lg.(FlushCloser); ok {
+		if err := fc.Flush(); err != nil {
+			l.handleError(err)
+		}
+	}
+	exit := l.exit
+	if exit == nil {
+		exit = os.Exit
+	}
+	exit(1)
+}
+
+//fatalf formats its arguments and passes them to fatal
+func (l *LogBase) fatalf(lg Logger, format string, args []interface{}) {
+	l.fatal(lg, []interface{}{fmt.Sprintf(format, args...)})
+}
+
+//PanicLogger is implemented by loggers that can log an entry and then panic
+type PanicLogger interface {
+	Logger
+	//Panic logs at Emergency, flushes anything buffered and then panics with the message
+	Panic(v ...interface{})
+	//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+	Panicf(format string, args ...interface{})
+}
+
+//panic writes v at Emergency to lg, flushes lg if it buffers and then panics with the message
+//The panic value is the message as it was logged, with redaction and truncation applied, so it does not leak what the entry hid
+func (l *LogBase) panic(lg Logger, v []interface{}) {
+	v = resolveLazy(v)
+	lg.Log("Emergency", v...)
+	if fc, ok := lg.(FlushCloser); ok {
+		if err := fc.Flush(); err != nil {
+			l.handleError(err)
+		}
+	}
+	_, args := l.prepareEntry("Emergency", v)
+	panic(joinArgs(args))
+}
+
+//panicf formats its arguments and passes them to panic
+func (l *LogBase) panicf(lg Logger, format string, args []interface{}) {
+	l.panic(lg, []interface{}{fmt.Sprintf(format, args...)})
+}
@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"regexp"
+	"testing"
+)
+
+var (
+	_ FatalLogger = new(StdLog)
+	_ FatalLogger = new(FileLog)
+	_ FatalLogger = new(MemoryLog)
+	_ FatalLogger = new(Stack)
+)
+
+func TestFatal(t *testing.T) {
+	ml := new(MemoryLog)
+	code := -1
+	entries := 0
+	ml.SetExitFunc(func(c int) {
+		code = c
+		entries = len(ml.Entries())
+	})
+
+	ml.Fatal("This is a message")
+	if code != 1 {
+		t.Error("expected the exit func to be called with 1, got", code)
+	}
+	if entries != 1 {
+		t.Error("expected the entry to be written before exiting, got", entries)
+	}
+	last, _ := ml.LastEntry()
+	if last.Level != "Emergency" || last.Args[0] != "This is a message" {
+		t.Error("unexpected entry", last)
+	}
+
+	ml.Fatalf("failed %d times", 3)
+	if last, _ := ml.LastEntry(); last.Args[0] != "failed 3 times" {
+		t.Error("unexpected entry", last)
+	}
+}
+
+func TestStackFatal(t *testing.T) {
+	//The slow logger behind an AsyncLog is only written to once the stack flushes its members
+	slow := new(MemoryLog)
+	async := NewAsyncLog(slow, 10)
+	defer async.Close()
+	direct := new(MemoryLog)
+
+	stack := NewTee(direct, async)
+	exited := false
+	stack.SetExitFunc(func(c int) {
+		exited = true
+		if len(slow.Entries()) != 1 || len(direct.Entries()) != 1 {
+			t.Error("expected every member to be written and flushed before exiting")
+		}
+	})
+	stack.Fatal("This is a message")
+	if !exited {
+		t.Error("expected the exit func to be called")
+	}
+}
+
+func TestPanic(t *testing.T) {
+	var (
+		recovered interface{}
+		entries   int
+	)
+	ml := new(MemoryLog)
+	func() {
+		defer func() {
+			recovered = recover()
+			entries = len(ml.Entries())
+		}()
+		ml.Panic("invariant broken:", 42)
+	}()
+	if recovered != "invariant broken: 42" {
+		t.Errorf("unexpected panic value %#v", recovered)
+	}
+	if entries != 1 {
+		t.Fatal("expected the entry to be written before panicking, got", entries)
+	}
+	last, _ := ml.LastEntry()
+	if last.Level != "Emergency" || last.Args[0] != "invariant broken:" || last.Args[1] != 42 {
+		t.Error("unexpected entry", last)
+	}
+
+	func() {
+		defer func() {
+			recovered = recover()
+		}()
+		ml.Panicf("failed %d times", 3)
+	}()
+	if recovered != "failed 3 times" {
+		t.Errorf("unexpected panic value %#v", recovered)
+	}
+}
+
+func TestPanicRedacted(t *testing.T) {
+	var recovered interface{}
+	ml := new(MemoryLog)
+	ml.SetRedactPattern(regexp.MustCompile(`\b\d{4}-\d{4}-\d{4}-\d{4}\b`))
+	ml.SetMaxMessageLen(30)
+	func() {
+		defer func() {
+			recovered = recover()
+		}()
+		ml.Panic("card", "1234-5678-9012-3456", "declined for a long reason")
+	}()
+	last, _ := ml.LastEntry()
+	if recovered != last.Message || recovered != "card [REDACTED] declined for a"+truncatedMarker {
+		t.Errorf("expected the panic value to match the logged message %q, got %#v", last.Message, recovered)
+	}
+}
+
+func TestStackPanic(t *testing.T) {
+	slow := new(MemoryLog)
+	async := NewAsyncLog(slow, 10)
+	defer async.Close()
+	stack := NewTee(async)
+	func() {
+		defer func() {
+			if r := recover(); r != "This is a message" {
+				t.Errorf("unexpected panic value %#v", r)
+			}
+		}()
+		stack.Panic("This is a message")
+	}()
+	if len(slow.Entries()) != 1 {
+		t.Error("expected the async member to be flushed before panicking")
+	}
+}
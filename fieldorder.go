@@ -0,0 +1,35 @@
+package logger
+
+import "sort"
+
+//SetFieldOrder writes the given keys first, in that order, before the remaining fields of text and logfmt lines
+//The remaining fields, and all of them when no order is set, are written sorted by key so lines are always repeatable
+//e.g. SetFieldOrder([]string{"request_id", "user"})
+func (l *LogBase) SetFieldOrder(keys []string) {
+	l.fieldOrder = append([]string(nil), keys...)
+}
+
+//orderKeys sorts keys in place with those listed in order first, in that order, and the rest by key
+func orderKeys(keys []string, order []string) {
+	if len(order) == 0 {
+		sort.Strings(keys)
+		return
+	}
+	rank := make(map[string]int, len(order))
+	for i, k := range order {
+		if _, ok := rank[k]; !ok {
+			rank[k] = i
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ri, pinnedI := rank[keys[i]]
+		rj, pinnedJ := rank[keys[j]]
+		switch {
+		case pinnedI && pinnedJ:
+			return ri < rj
+		case pinnedI != pinnedJ:
+			return pinnedI
+		}
+		return keys[i] < keys[j]
+	})
+}
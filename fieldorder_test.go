@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestFieldOrderDefault(t *testing.T) {
+	fields := make(map[string]interface{})
+	for i := 0; i < 20; i++ {
+		fields[fmt.Sprintf("k%02d", 19-i)] = i
+	}
+	var first string
+	for run := 0; run < 10; run++ {
+		var buf bytes.Buffer
+		wl := new(WriterLog)
+		wl.SetOutput(&buf)
+		wl.WithFields(fields).Info("message")
+		if run == 0 {
+			first = buf.String()
+			continue
+		}
+		if buf.String() != first {
+			t.Fatalf("expected repeatable output, got %q and %q", first, buf.String())
+		}
+	}
+	testOutput(first[:len("Info message k00=19 k01=18 k02=17")], "Info message k00=19 k01=18 k02=17", t)
+}
+
+func TestSetFieldOrder(t *testing.T) {
+	fields := map[string]interface{}{"zone": "eu", "user": "bob", "request_id": "abc", "attempt": 2}
+	order := []string{"request_id", "user", "missing"}
+
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	wl.SetFieldOrder(order)
+	wl.WithFields(fields).Info("message")
+	testOutput(buf.String(), "Info message request_id=abc user=bob attempt=2 zone=eu\n", t)
+
+	buf.Reset()
+	wl.SetTemplate("{level} {fields}: {msg}")
+	wl.WithFields(fields).Info("message")
+	testOutput(buf.String(), "Info request_id=abc user=bob attempt=2 zone=eu: message\n", t)
+
+	buf.Reset()
+	ll := new(LogfmtLog)
+	ll.SetOutput(&buf)
+	ll.SetFieldOrder(order)
+	ll.WithFields(fields).Info("message")
+	testOutput(buf.String(), "level=Info msg=\"message\" request_id=abc user=bob attempt=2 zone=eu\n", t)
+
+	buf.Reset()
+	wl.SetTemplate("")
+	wl.SetFormatter(LogfmtFormatter{})
+	wl.WithFields(fields).Info("message")
+	testOutput(buf.String(), "level=Info msg=\"message\" request_id=abc user=bob attempt=2 zone=eu\n", t)
+
+	//The order is copied
+	order[0] = "zone"
+	buf.Reset()
+	wl.WithFields(fields).Info("message")
+	testOutput(buf.String(), "level=Info msg=\"message\" request_id=abc user=bob attempt=2 zone=eu\n", t)
+}
+
+func TestOrderKeys(t *testing.T) {
+	keys := []string{"d", "b", "a", "c"}
+	orderKeys(keys, []string{"c", "a", "c"})
+	if !reflect.DeepEqual(keys, []string{"c", "a", "b", "d"}) {
+		t.Error("unexpected order", keys)
+	}
+	orderKeys(keys, nil)
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c", "d"}) {
+		t.Error("unexpected order", keys)
+	}
+}
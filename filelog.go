@@ -0,0 +1,598 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+//Log to File
+//Write failures never panic, they are available from Err and passed to the OnError handler
+//A FileLog is safe for concurrent use, writes, rotation, Flush and Close are serialized by a mutex shared with its copies
+type FileLog struct {
+	LogBase
+	//fileHandle is shared with the copies made by WithFields and Named
+	*fileHandle
+	logPath       string
+	maxSize       int64
+	maxBackups    int
+	compress      bool
+	formatter     Formatter
+	fileMode      os.FileMode
+	dirMode       os.FileMode
+	bufSize       int
+	flushInterval time.Duration
+	//levelFiles are the extra files set with SetLevelFile
+	levelFiles []levelFile
+}
+
+//fileHandle is the open file of a FileLog, mu guards the other fields
+type fileHandle struct {
+	mu   sync.Mutex
+	f    *os.File
+	l    *log.Logger
+	size int64
+	//buf buffers writes to f when SetBuffer is used
+	buf *bufferedWriter
+	//compressing tracks the background work of rotation, backups is closed once the last queued rotation has finished
+	compressing sync.WaitGroup
+	backups     chan struct{}
+	rotations   int
+	//compressMu guards compressErr, which is set by the background work
+	compressMu  sync.Mutex
+	compressErr error
+}
+
+//SetFileMode sets the permissions used when the log file is created, 0666 by default
+//As with os.OpenFile the process umask is applied, so 0666 usually results in 0644
+//Existing files keep their permissions
+func (s *FileLog) SetFileMode(mode os.FileMode) {
+	s.fileMode = mode
+}
+
+//SetDirMode sets the permissions used when creating missing parent directories of the log file, 0755 by default
+//As with os.MkdirAll the process umask is applied
+func (s *FileLog) SetDirMode(mode os.FileMode) {
+	s.dirMode = mode
+}
+
+//SetBuffer buffers up to size bytes of output to save a write call for every entry, 0 (the default) writes each entry straight away
+//The buffer is written out when it fills, every flushInterval if it is positive, on Flush, Close and rotation,
+//and immediately after any entry at Error or more severe
+func (s *FileLog) SetBuffer(size int, flushInterval time.Duration) {
+	mu := s.lock()
+	mu.Lock()
+	s.bufSize = size
+	s.flushInterval = flushInterval
+	var err error
+	if s.f != nil {
+		err = s.buffer()
+	}
+	mu.Unlock()
+	if err != nil {
+		s.handleError(err)
+	}
+}
+
+//fileHandleMu guards creating the file handle of a FileLog
+var fileHandleMu sync.Mutex
+
+//lock returns the mutex of the logger's file handle, creating the handle if needed
+//It is called before the logger copies itself so that the copy shares the handle
+func (s *FileLog) lock() *sync.Mutex {
+	fileHandleMu.Lock()
+	defer fileHandleMu.Unlock()
+	if s.fileHandle == nil {
+		s.fileHandle = new(fileHandle)
+	}
+	return &s.mu
+}
+
+//buffer binds the logger to the open file, through a buffered writer when SetBuffer is used,
+//returning any error writing out what was already buffered
+func (s *FileLog) buffer() error {
+	var err error
+	if s.buf != nil {
+		err = s.buf.Close()
+		s.buf = nil
+	}
+	if s.bufSize <= 0 {
+		s.l = log.New(s.f, "", 0)
+		return err
+	}
+	s.buf = newBufferedWriter(s.f, s.bufSize, s.flushInterval)
+	s.l = log.New(s.buf, "", 0)
+	return err
+}
+
+//SetFormatter sets how entries are rendered, TextFormatter is used when none is set
+func (s *FileLog) SetFormatter(f Formatter) {
+	s.formatter = f
+}
+
+//SetMaxSize rotates the log file once a write would take it past bytes, 0 (the default) never rotates
+func (s *FileLog) SetMaxSize(bytes int64) {
+	s.maxSize = bytes
+}
+
+//SetMaxBackups sets how many rotated files are kept as logPath.1 (newest) to logPath.n (oldest)
+//With 0 backups the current file is discarded on rotation
+func (s *FileLog) SetMaxBackups(n int) {
+	s.maxBackups = n
+}
+
+//SetCompressBackups gzips rotated files in the background, leaving logPath.n.gz in place of logPath.n
+func (s *FileLog) SetCompressBackups(compress bool) {
+	s.compress = compress
+}
+
+//Init expects the first item passed in to be the log file location.
+//If it does not exist ./owtorg-logger will be used
+//The file is opened once here and reused for every write until Close is called
+func (s *FileLog) Init() error {
+	//Set arbitrary log path, which could be overridden by initializers
+	s.logPath = "./owtorg-logger"
+	for _, fn := range s.initializers {
+		switch funct := fn.(type) {
+		case func(s *FileLog):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *FileLog) or func(s Logger)")
+		}
+	}
+	mu := s.lock()
+	mu.Lock()
+	err := s.open()
+	mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return s.openLevelFiles()
+}
+
+//open opens the log file for appending, creating any missing parent directories,
+//and binds a dedicated logger to it so that the package level log output is never touched, s.mu must be held
+//A file already open, e.g. when Init is called again, is flushed and closed first so that no handle is leaked
+func (s *FileLog) open() error {
+	if err := s.closeFile(); err != nil {
+		return err
+	}
+	dirMode := s.dirMode
+	if dirMode == 0 {
+		dirMode = 0755
+	}
+	if err := os.MkdirAll(filepath.Dir(s.logPath), dirMode); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+	fileMode := s.fileMode
+	if fileMode == 0 {
+		fileMode = 0666
+	}
+	f, err := os.OpenFile(s.logPath, os.O_RDWR|os.O_CREATE|os.O_APPEND, fileMode)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.f = f
+	s.size = info.Size()
+	return s.buffer()
+}
+
+//rotate closes the current file, shifts the backups up by one and opens a fresh file, s.mu must be held
+//The new file is open before the next line is written so nothing is lost in between
+//With compression the old file is set aside and the backups are shifted and compressed in the background,
+//queued behind any earlier rotation, so that the write does not wait for gzip
+//An error from the background work of earlier rotations is returned once the new file is open
+func (s *FileLog) rotate() error {
+	if err := s.closeFile(); err != nil {
+		return err
+	}
+	switch {
+	case s.maxBackups <= 0:
+		if err := os.Remove(s.logPath); err != nil {
+			return err
+		}
+	case s.compress || s.backupsPending():
+		s.rotations++
+		staged := fmt.Sprintf("%s.rotating.%d", s.logPath, s.rotations)
+		if err := os.Rename(s.logPath, staged); err != nil {
+			return err
+		}
+		s.queueBackup(staged)
+	default:
+		if err := shiftBackups(s.logPath, s.maxBackups); err != nil {
+			return err
+		}
+		if err := os.Rename(s.logPath, backupPath(s.logPath, 1)); err != nil {
+			return err
+		}
+	}
+	if err := s.open(); err != nil {
+		return err
+	}
+	return s.takeCompressErr()
+}
+
+//shiftBackups removes the oldest of n backups of path and renames the others up by one, leaving path.1 free
+func shiftBackups(path string, n int) error {
+	oldest := backupPath(path, n)
+	for _, p := range []string{oldest, oldest + ".gz"} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	for i := n - 1; i > 0; i-- {
+		from, to := backupPath(path, i), backupPath(path, i+1)
+		for _, ext := range []string{"", ".gz"} {
+			err := os.Rename(from+ext, to+ext)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//backupsPending reports whether the background work of an earlier rotation is still running, s.mu must be held
+func (s *FileLog) backupsPending() bool {
+	if s.backups == nil {
+		return false
+	}
+	select {
+	case <-s.backups:
+		return false
+	default:
+		return true
+	}
+}
+
+//queueBackup makes staged the newest backup on a background goroutine once earlier rotations are done,
+//compressing the backups when enabled, s.mu must be held
+func (s *FileLog) queueBackup(staged string) {
+	path, n, compress := s.logPath, s.maxBackups, s.compress
+	h := s.fileHandle
+	previous, done := h.backups, make(chan struct{})
+	h.backups = done
+	h.compressing.Add(1)
+	go func() {
+		defer h.compressing.Done()
+		defer close(done)
+		//Backups must not be renamed while they are still being compressed
+		if previous != nil {
+			<-previous
+		}
+		err := shiftBackups(path, n)
+		if err == nil {
+			err = os.Rename(staged, backupPath(path, 1))
+		}
+		if err == nil && compress {
+			err = compressBackups(path, n)
+		}
+		if err != nil {
+			h.compressMu.Lock()
+			h.compressErr = err
+			h.compressMu.Unlock()
+		}
+	}()
+}
+
+//compressFile compresses a backup, it is replaced in tests to hold up compression
+var compressFile = gzipFile
+
+//compressBackups gzips any uncompressed backups among the n backups of path
+func compressBackups(path string, n int) error {
+	var err error
+	for i := 1; i <= n; i++ {
+		p := backupPath(path, i)
+		if _, serr := os.Stat(p); serr != nil {
+			continue
+		}
+		if cerr := compressFile(p); cerr != nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+//takeCompressErr returns and clears the error hit by the background work of rotation
+func (s *FileLog) takeCompressErr() error {
+	s.compressMu.Lock()
+	defer s.compressMu.Unlock()
+	err := s.compressErr
+	s.compressErr = nil
+	return err
+}
+
+//waitCompress waits for the background work of rotation to finish and returns any error it hit, s.mu must be held
+func (s *FileLog) waitCompress() error {
+	s.compressing.Wait()
+	return s.takeCompressErr()
+}
+
+//backupPath returns the name of the nth rotated file
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+//Flush writes out anything buffered by SetBuffer and commits the file to disk with fsync,
+//so that entries survive a crash of the machine, not just of the process
+//Syncing is expensive and should be kept off the hot path, e.g. after a critical entry, Fatal and Panic call it automatically
+func (s *FileLog) Flush() error {
+	err := s.flushFile()
+	for _, lf := range s.levelFiles {
+		if ferr := lf.file.Flush(); err == nil {
+			err = ferr
+		}
+	}
+	return err
+}
+
+//flushFile flushes and syncs the main file
+func (s *FileLog) flushFile() error {
+	mu := s.lock()
+	mu.Lock()
+	defer mu.Unlock()
+	if s.f == nil {
+		return nil
+	}
+	if s.buf != nil {
+		if err := s.buf.Flush(); err != nil {
+			return err
+		}
+	}
+	return s.f.Sync()
+}
+
+//Close flushes and closes the underlying file and any level files, waiting for background compression to finish
+func (s *FileLog) Close() error {
+	mu := s.lock()
+	mu.Lock()
+	defer mu.Unlock()
+	err := s.closeFile()
+	if cerr := s.waitCompress(); err == nil {
+		err = cerr
+	}
+	for _, lf := range s.levelFiles {
+		if cerr := lf.file.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+//closeFile flushes and closes the current file, s.mu must be held
+func (s *FileLog) closeFile() error {
+	if s.f == nil {
+		return nil
+	}
+	var err error
+	if s.buf != nil {
+		err = s.buf.Close()
+		s.buf = nil
+	}
+	if serr := s.f.Sync(); err == nil {
+		err = serr
+	}
+	if cerr := s.f.Close(); err == nil {
+		err = cerr
+	}
+	s.f = nil
+	s.l = nil
+	return err
+}
+
+//WithFields returns a copy of the logger, sharing the same file, that appends fields as key=value pairs
+func (s *FileLog) WithFields(fields map[string]interface{}) Logger {
+	s.tracker()
+	s.lock()
+	c := *s
+	c.fields = s.mergeFields(fields)
+	return &c
+}
+
+//WithContext returns a copy of the logger carrying the context values registered with RegisterContextField
+func (s *FileLog) WithContext(ctx context.Context) Logger {
+	return s.WithFields(fieldsFromContext(ctx))
+}
+
+//WithError returns a copy of the logger carrying err as an error field, with a cause field when it wraps other errors
+//A nil err returns the logger itself
+func (s *FileLog) WithError(err error) Logger {
+	if err == nil {
+		return s
+	}
+	return s.WithFields(errorFields([]interface{}{err}))
+}
+
+//Named returns a copy of the logger, sharing the same file, whose entries are tagged with name
+func (s *FileLog) Named(name string) Logger {
+	s.tracker()
+	s.lock()
+	c := *s
+	c.name = s.childName(name)
+	return &c
+}
+
+//WithGroup returns a copy of the logger whose later fields are grouped under name, e.g. WithGroup("http")
+func (s *FileLog) WithGroup(name string) Logger {
+	s.tracker()
+	s.lock()
+	c := *s
+	c.groups = s.childGroups(name)
+	return &c
+}
+
+//Clone returns a copy of the logger whose configuration can be changed independently, it shares the open file with s
+func (s *FileLog) Clone() Logger {
+	s.lock()
+	c := *s
+	c.detach()
+	c.levelFiles = append([]levelFile(nil), s.levelFiles...)
+	return &c
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *FileLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *FileLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *FileLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *FileLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *FileLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *FileLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
+func (s *FileLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *FileLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *FileLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *FileLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *FileLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *FileLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *FileLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *FileLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *FileLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *FileLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *FileLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *FileLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *FileLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *FileLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *FileLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *FileLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *FileLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+func (s *FileLog) Log(level string, v ...interface{}) {
+	if err := s.write(level, v); err != nil {
+		s.handleError(err)
+	}
+}
+
+//TryLog is Log that also returns any error hit while writing
+func (s *FileLog) TryLog(level string, v ...interface{}) error {
+	err := s.write(level, v)
+	if err != nil {
+		s.handleError(err)
+	}
+	return err
+}
+
+//write formats and writes a single entry, rotating the file first if it would grow past the max size
+func (s *FileLog) write(level string, v []interface{}) error {
+	if !s.shouldLog(level) {
+		return nil
+	}
+	formatter := s.formatter
+	if formatter == nil {
+		formatter = s.textFormatter()
+	}
+	formatter = s.configureFormatter(formatter)
+	fields, args := s.prepare(level, v)
+	line, err := formatter.Format(level, fields, args)
+	if err != nil {
+		return err
+	}
+	mu := s.lock()
+	mu.Lock()
+	rotateErr, err := s.writeLine(level, line)
+	mu.Unlock()
+	//Reported without the lock so that the OnError handler may log through s
+	if rotateErr != nil {
+		s.handleError(rotateErr)
+	}
+	if lerr := s.writeLevelFiles(level, line); err == nil {
+		err = lerr
+	}
+	return err
+}
+
+//writeLine writes a formatted entry, s.mu must be held
+//A failed rotation that leaves the current file usable is returned as rotateErr and the entry is written anyway
+func (s *FileLog) writeLine(level string, line []byte) (rotateErr, err error) {
+	//Loggers used without Init fall back to opening the file on first write
+	if s.l == nil {
+		if s.logPath == "" {
+			s.logPath = "./owtorg-logger"
+		}
+		if err := s.open(); err != nil {
+			return nil, err
+		}
+	}
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if rotateErr = s.rotate(); rotateErr != nil && s.l == nil {
+			return nil, rotateErr
+		}
+	}
+	if err := s.l.Output(3, string(line)); err != nil {
+		return rotateErr, err
+	}
+	s.size += int64(len(line))
+	if s.buf != nil && flushesAt(level) {
+		return rotateErr, s.buf.Flush()
+	}
+	return rotateErr, nil
+}
@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+)
+
+//FilterFunc decides whether an entry is kept, fields holds the FilterLog's fields and any map arguments
+//args are the arguments as they were logged, lazy arguments have not been evaluated yet
+type FilterFunc func(level string, fields map[string]interface{}, args []interface{}) bool
+
+//FilterLog wraps a Logger and only forwards entries for which a predicate returns true
+//e.g. dropping health check noise whose path field is /healthz
+//A predicate that panics keeps the entry
+type FilterLog struct {
+	logger Logger
+	keep   FilterFunc
+	fields map[string]interface{}
+}
+
+//NewFilterLog returns a FilterLog that forwards the entries of l for which keep returns true
+func NewFilterLog(l Logger, keep FilterFunc) *FilterLog {
+	return &FilterLog{logger: l, keep: keep}
+}
+
+//WithFields returns a copy of the filter whose entries carry fields, both for the predicate and the wrapped logger
+//When the wrapped logger does not implement FieldLogger the fields are passed as a trailing map argument
+func (s *FilterLog) WithFields(fields map[string]interface{}) Logger {
+	merged := make(map[string]interface{}, len(s.fields)+len(fields))
+	for k, v := range s.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	c := &FilterLog{logger: s.logger, keep: s.keep, fields: merged}
+	if fl, ok := s.logger.(FieldLogger); ok {
+		c.logger = fl.WithFields(fields)
+	}
+	return c
+}
+
+//allowed runs the predicate, treating a panic as keep
+func (s *FilterLog) allowed(level string, v []interface{}) (keep bool) {
+	if s.keep == nil {
+		return true
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			keep = true
+		}
+	}()
+	fields := make(map[string]interface{}, len(s.fields))
+	for k, val := range s.fields {
+		fields[k] = val
+	}
+	for _, arg := range v {
+		if m, ok := arg.(map[string]interface{}); ok {
+			for k, val := range m {
+				fields[k] = val
+			}
+		}
+	}
+	return s.keep(level, fields, v)
+}
+
+//Flush flushes the wrapped logger
+func (s *FilterLog) Flush() error {
+	if fc, ok := s.logger.(FlushCloser); ok {
+		return fc.Flush()
+	}
+	return nil
+}
+
+//Close closes the wrapped logger
+func (s *FilterLog) Close() error {
+	if c, ok := s.logger.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+//Enabled reports whether the wrapped logger would write an entry at level
+//Entries that the predicate drops are still reported as enabled
+func (s *FilterLog) Enabled(level string) bool {
+	return enabled(s.logger, level)
+}
+
+//Init initializes the wrapped logger
+func (s *FilterLog) Init() error {
+	return s.logger.Init()
+}
+
+//OnInit registers initializers on the wrapped logger
+func (s *FilterLog) OnInit(f ...interface{}) {
+	s.logger.OnInit(f...)
+}
+
+//OnInitLogger registers an initializer on the wrapped logger, it is called with the wrapped logger
+func (s *FilterLog) OnInitLogger(f func(s Logger)) {
+	s.OnInit(f)
+}
+
+func (s *FilterLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *FilterLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *FilterLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *FilterLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *FilterLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *FilterLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *FilterLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *FilterLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *FilterLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *FilterLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *FilterLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *FilterLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *FilterLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *FilterLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *FilterLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *FilterLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *FilterLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+func (s *FilterLog) Log(level string, v ...interface{}) {
+	if !s.allowed(level, v) {
+		return
+	}
+	if _, ok := s.logger.(FieldLogger); !ok && len(s.fields) > 0 {
+		v = append(v[:len(v):len(v)], s.fields)
+	}
+	s.logger.Log(level, v...)
+}
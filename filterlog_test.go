@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+//notHealthz drops entries whose path field is /healthz
+func notHealthz(level string, fields map[string]interface{}, args []interface{}) bool {
+	return fields["path"] != "/healthz"
+}
+
+func TestFilterLog(t *testing.T) {
+	ml := new(MemoryLog)
+	fl := NewFilterLog(ml, notHealthz)
+
+	fl.Info("request", map[string]interface{}{"path": "/healthz"})
+	fl.Info("request", map[string]interface{}{"path": "/orders"})
+	fl.WithFields(map[string]interface{}{"path": "/healthz"}).Info("request")
+	fl.WithFields(map[string]interface{}{"path": "/users"}).Info("request")
+	fl.Error("no path")
+
+	entries := ml.Entries()
+	if len(entries) != 3 {
+		t.Fatal("expected 3 entries, got", entries)
+	}
+	if entries[0].Args[1].(map[string]interface{})["path"] != "/orders" {
+		t.Error("unexpected first entry", entries[0])
+	}
+	//The fields reach the MemoryLog through its WithFields
+	if entries[1].Fields["path"] != "/users" {
+		t.Error("unexpected second entry", entries[1])
+	}
+	if entries[2].Level != "Error" {
+		t.Error("unexpected third entry", entries[2])
+	}
+}
+
+func TestFilterLogFieldLogger(t *testing.T) {
+	ml := new(MemoryLog)
+	jl := new(JSONLog)
+	hook := func(level string, fields map[string]interface{}, args []interface{}) {
+		ml.Log(level, fields["path"])
+	}
+	jl.SetOutput(ioutil.Discard)
+	jl.AddHook(hook)
+	fl := NewFilterLog(jl, notHealthz)
+
+	fl.WithFields(map[string]interface{}{"path": "/healthz"}).Info("request")
+	fl.WithFields(map[string]interface{}{"path": "/orders"}).Info("request")
+	entries := ml.Entries()
+	if len(entries) != 1 || entries[0].Args[0] != "/orders" {
+		t.Error("expected the fields to reach the wrapped logger as fields, got", entries)
+	}
+}
+
+func TestFilterLogPanic(t *testing.T) {
+	ml := new(MemoryLog)
+	fl := NewFilterLog(ml, func(level string, fields map[string]interface{}, args []interface{}) bool {
+		return args[1].(string) == "keep"
+	})
+	fl.Info("kept", "keep")
+	fl.Info("dropped", "drop")
+	//The predicate panics on the missing argument
+	fl.Info("kept after panic")
+	entries := ml.Entries()
+	if len(entries) != 2 || entries[0].Args[0] != "kept" || entries[1].Args[0] != "kept after panic" {
+		t.Error("unexpected entries", entries)
+	}
+}
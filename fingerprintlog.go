@@ -0,0 +1,212 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+//FingerprintLog wraps a Logger and writes only the first entry with each fingerprint within a window
+//Entries are fingerprinted by level and message unless SetFingerprint is used, e.g. to group errors by type and location
+//Suppressed repeats are counted and reported as "<message> (repeated N times)" once the window has passed and
+//an entry with the same fingerprint arrives, or on Flush or Close
+type FingerprintLog struct {
+	mu          sync.Mutex
+	logger      Logger
+	window      time.Duration
+	clock       Clock
+	fingerprint func(Entry) string
+	seen        map[string]*fingerprinted
+	//order holds the fingerprints in the order they were first seen so counts are reported in a stable order
+	order []string
+}
+
+//fingerprinted is the state kept for one fingerprint
+type fingerprinted struct {
+	level   string
+	message string
+	first   time.Time
+	repeats int
+}
+
+//NewFingerprintLog returns a FingerprintLog that writes the first entry with each fingerprint in every window to l
+//A window of 0 suppresses repeats until Flush or Close
+func NewFingerprintLog(l Logger, window time.Duration) *FingerprintLog {
+	return &FingerprintLog{logger: l, window: window, seen: make(map[string]*fingerprinted)}
+}
+
+//SetFingerprint sets the function entries are grouped by, nil restores the default of level and message
+func (s *FingerprintLog) SetFingerprint(f func(Entry) string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fingerprint = f
+}
+
+//SetClock sets the clock windows are measured with
+func (s *FingerprintLog) SetClock(c Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = c
+}
+
+//key returns the fingerprint of e
+func (s *FingerprintLog) key(e Entry) string {
+	if s.fingerprint != nil {
+		return s.fingerprint(e)
+	}
+	return e.Level + "\x00" + e.Message
+}
+
+//reportRepeats writes the count of suppressed repeats of fp, it must be called with mu held
+func (s *FingerprintLog) reportRepeats(fp *fingerprinted) {
+	if fp.repeats == 0 {
+		return
+	}
+	s.logger.Log(fp.level, fmt.Sprintf("%s (repeated %d times)", fp.message, fp.repeats))
+	fp.repeats = 0
+}
+
+//reportAll writes the counts of every fingerprint with suppressed repeats, it must be called with mu held
+func (s *FingerprintLog) reportAll() {
+	for _, key := range s.order {
+		s.reportRepeats(s.seen[key])
+	}
+}
+
+//prune forgets the fingerprints whose window has passed, it must be called with mu held after their repeats are reported
+func (s *FingerprintLog) prune() {
+	if s.window <= 0 {
+		return
+	}
+	now := clockNow(s.clock)
+	order := s.order[:0]
+	for _, key := range s.order {
+		if now.Before(s.seen[key].first.Add(s.window)) {
+			order = append(order, key)
+			continue
+		}
+		delete(s.seen, key)
+	}
+	s.order = order
+}
+
+//Flush reports the suppressed repeats and flushes the wrapped logger
+//Fingerprints stay suppressed until their window has passed, expired ones are forgotten to keep memory use bounded
+func (s *FingerprintLog) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reportAll()
+	s.prune()
+	if fc, ok := s.logger.(FlushCloser); ok {
+		return fc.Flush()
+	}
+	return nil
+}
+
+//Close reports the suppressed repeats, forgets every fingerprint and closes the wrapped logger
+func (s *FingerprintLog) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reportAll()
+	s.seen = make(map[string]*fingerprinted)
+	s.order = nil
+	if c, ok := s.logger.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+//Enabled reports whether the wrapped logger would write an entry at level
+func (s *FingerprintLog) Enabled(level string) bool {
+	return enabled(s.logger, level)
+}
+
+//Init initializes the wrapped logger
+func (s *FingerprintLog) Init() error {
+	return s.logger.Init()
+}
+
+//OnInit registers initializers on the wrapped logger
+func (s *FingerprintLog) OnInit(f ...interface{}) {
+	s.logger.OnInit(f...)
+}
+
+//OnInitLogger registers an initializer on the wrapped logger, it is called with the wrapped logger
+func (s *FingerprintLog) OnInitLogger(f func(s Logger)) {
+	s.OnInit(f)
+}
+
+func (s *FingerprintLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *FingerprintLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *FingerprintLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *FingerprintLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *FingerprintLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *FingerprintLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *FingerprintLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *FingerprintLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *FingerprintLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *FingerprintLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *FingerprintLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *FingerprintLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *FingerprintLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *FingerprintLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *FingerprintLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *FingerprintLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *FingerprintLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+
+//Log writes the entry unless an entry with the same fingerprint was written within the window
+//When the window has passed the repeats are reported before the entry is written and a new window starts
+func (s *FingerprintLog) Log(level string, v ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := entryAt(clockNow(s.clock), level, nil, v)
+	key := s.key(e)
+	fp, ok := s.seen[key]
+	if ok && (s.window <= 0 || e.Time.Before(fp.first.Add(s.window))) {
+		fp.repeats++
+		return
+	}
+	if ok {
+		s.reportRepeats(fp)
+		fp.level, fp.message, fp.first = level, e.Message, e.Time
+	} else {
+		s.seen[key] = &fingerprinted{level: level, message: e.Message, first: e.Time}
+		s.order = append(s.order, key)
+	}
+	s.logger.Log(level, v...)
+}
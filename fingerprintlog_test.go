@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFingerprintLog(t *testing.T) {
+	ml := new(MemoryLog)
+	clock := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	fl := NewFingerprintLog(ml, time.Minute)
+	fl.SetClock(clock)
+
+	for i := 0; i < 3; i++ {
+		fl.Error("connection refused")
+		fl.Warning("slow query")
+	}
+	fl.Info("connection refused")
+	expected := []Entry{
+		{Level: "Error", Args: []interface{}{"connection refused"}},
+		{Level: "Warning", Args: []interface{}{"slow query"}},
+		{Level: "Info", Args: []interface{}{"connection refused"}},
+	}
+	if !reflect.DeepEqual(levelArgs(ml.Entries()...), expected) {
+		t.Fatal("expected only the first of each fingerprint, got", ml.Entries())
+	}
+
+	//Once the window has passed the next entry reports the repeats and is written
+	clock.Advance(time.Minute)
+	fl.Error("connection refused")
+	expected = append(expected,
+		Entry{Level: "Error", Args: []interface{}{"connection refused (repeated 2 times)"}},
+		Entry{Level: "Error", Args: []interface{}{"connection refused"}},
+	)
+	if !reflect.DeepEqual(levelArgs(ml.Entries()...), expected) {
+		t.Fatal("unexpected entries", ml.Entries())
+	}
+
+	//Flush reports the rest, each fingerprint tracked separately
+	fl.Error("connection refused")
+	if err := fl.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	expected = append(expected,
+		Entry{Level: "Error", Args: []interface{}{"connection refused (repeated 1 times)"}},
+		Entry{Level: "Warning", Args: []interface{}{"slow query (repeated 2 times)"}},
+	)
+	if !reflect.DeepEqual(levelArgs(ml.Entries()...), expected) {
+		t.Fatal("unexpected entries", ml.Entries())
+	}
+
+	//The slow query window has passed, so Flush forgot it
+	fl.Warning("slow query")
+	if e, _ := ml.LastEntry(); e.Message != "slow query" || len(ml.Entries()) != len(expected)+1 {
+		t.Error("expected the fingerprint to start a new window, got", ml.Entries())
+	}
+}
+
+func TestFingerprintLogCustom(t *testing.T) {
+	ml := new(MemoryLog)
+	fl := NewFingerprintLog(ml, 0)
+	//Group errors by type, whatever their message
+	fl.SetFingerprint(func(e Entry) string {
+		for _, arg := range e.Args {
+			if err, ok := arg.(error); ok {
+				return fmt.Sprintf("%T", err)
+			}
+		}
+		return e.Level + e.Message
+	})
+	fl.Error(errors.New("timeout after 1s"))
+	fl.Error(errors.New("timeout after 2s"))
+	fl.Error(fmt.Errorf("wrapped: %w", errors.New("timeout")))
+	fl.Error(&MemberError{Index: 1, Err: errors.New("failed")})
+	fl.Error(&MemberError{Index: 2, Err: errors.New("failed")})
+	if len(ml.Entries()) != 3 {
+		t.Fatal("expected one entry per error type, got", ml.Entries())
+	}
+	if err := fl.Close(); err != nil {
+		t.Fatal(err)
+	}
+	entries := ml.Entries()
+	if len(entries) != 5 || entries[3].Message != "timeout after 1s (repeated 1 times)" || entries[4].Message != "logger 1: failed (repeated 1 times)" {
+		t.Error("unexpected counts", entries)
+	}
+
+	//Close forgets every fingerprint
+	fl.Error(errors.New("timeout after 3s"))
+	if e, _ := ml.LastEntry(); e.Message != "timeout after 3s" {
+		t.Error("expected the entry to be written after Close, got", e)
+	}
+}
@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+//Formatter renders a single entry, including its line ending, into the bytes that are written
+//fields holds the logger's structured fields, including "caller" when SetCaller is enabled
+type Formatter interface {
+	Format(level string, fields map[string]interface{}, args []interface{}) ([]byte, error)
+}
+
+//baseFormatter is implemented by the built in formatters, which take settings such as the clock and level style from the logger
+type baseFormatter interface {
+	withBase(l *LogBase) Formatter
+}
+
+//configureFormatter returns f using the logger's settings when f supports them, ending records with the logger's line ending
+func (l *LogBase) configureFormatter(f Formatter) Formatter {
+	if bf, ok := f.(baseFormatter); ok {
+		f = bf.withBase(l)
+	}
+	if l.lineEnding != "" && l.lineEnding != "\n" {
+		f = lineEndingFormatter{f: f, ending: l.lineEnding}
+	}
+	return f
+}
+
+//TextFormatter renders the "level [args] key=value" layout used by the text loggers
+//Logger name and caller fields are written as "[name] file:line " prefixes rather than key=value pairs
+//and a stacktrace field is written as an indented block on the lines that follow
+//The layout can be changed with SetTemplate on the logger
+type TextFormatter struct {
+	template *lineTemplate
+	//color wraps the level name in its ANSI colour
+	color bool
+	clock Clock
+	style LevelStyle
+	order []string
+}
+
+func (f TextFormatter) withBase(l *LogBase) Formatter {
+	f.clock, f.style, f.order = l.clock, l.levelStyle, l.fieldOrder
+	return f
+}
+
+//Format implements Formatter
+func (f TextFormatter) Format(level string, fields map[string]interface{}, args []interface{}) ([]byte, error) {
+	block := textBlock(fields)
+	if f.template != nil {
+		return []byte(f.template.render(clockNow(f.clock), level, f.style, f.color, fields, args, f.order) + block + "\n"), nil
+	}
+	name := f.style.format(level)
+	if f.color {
+		name = colorizeName(level, name)
+	}
+	prefix, rest := textPrefix(fields)
+	return []byte(prefix + textLine(name, args, rest, f.order) + block + "\n"), nil
+}
+
+//textBlock returns the stacktrace field indented on its own lines, or nothing when there is none
+func textBlock(fields map[string]interface{}) string {
+	trace, ok := fields["stacktrace"].(string)
+	if !ok {
+		return ""
+	}
+	return "\n\t" + strings.Replace(trace, "\n", "\n\t", -1)
+}
+
+//textPrefix splits the logger name, caller and function fields out of fields and returns them as a line prefix
+//A stacktrace field is also removed, it is written by textBlock
+func textPrefix(fields map[string]interface{}) (string, map[string]interface{}) {
+	name, hasName := fields["logger"].(string)
+	caller, hasCaller := fields["caller"].(string)
+	fn, hasFunc := fields["func"].(string)
+	_, hasTrace := fields["stacktrace"].(string)
+	if !hasName && !hasCaller && !hasFunc && !hasTrace {
+		return "", fields
+	}
+	var prefix string
+	if hasName {
+		prefix += "[" + name + "] "
+	}
+	if hasCaller {
+		prefix += caller + " "
+	}
+	if hasFunc {
+		prefix += "(" + fn + ") "
+	}
+	rest := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		if (k != "logger" || !hasName) && (k != "caller" || !hasCaller) && (k != "func" || !hasFunc) && (k != "stacktrace" || !hasTrace) {
+			rest[k] = v
+		}
+	}
+	return prefix, rest
+}
+
+//JSONFormatter renders the newline delimited JSON written by JSONLog
+type JSONFormatter struct {
+	clock Clock
+	style LevelStyle
+}
+
+func (f JSONFormatter) withBase(l *LogBase) Formatter {
+	f.clock, f.style = l.clock, l.levelStyle
+	return f
+}
+
+//Format implements Formatter
+func (f JSONFormatter) Format(level string, fields map[string]interface{}, args []interface{}) ([]byte, error) {
+	return append(jsonLine(f.style.format(level), clockNow(f.clock), fields, args), '\n'), nil
+}
+
+//LogfmtFormatter renders the logfmt lines written by LogfmtLog
+type LogfmtFormatter struct {
+	style LevelStyle
+	order []string
+}
+
+func (f LogfmtFormatter) withBase(l *LogBase) Formatter {
+	f.style, f.order = l.levelStyle, l.fieldOrder
+	return f
+}
+
+//Format implements Formatter
+func (f LogfmtFormatter) Format(level string, fields map[string]interface{}, args []interface{}) ([]byte, error) {
+	return []byte(logfmtLine(f.style.format(level), fields, args, f.order) + "\n"), nil
+}
+
+//textLine renders the "level [args]" layout used by the text loggers followed by any fields as key=value pairs
+//in the field order, see SetFieldOrder
+//A single string argument is written without the brackets and an entry without arguments as the level alone
+func textLine(level string, v []interface{}, fields map[string]interface{}, order []string) string {
+	line := level
+	if msg := textMessage(v); msg != "" {
+		line += " " + msg
+	}
+	fields = flattenGroups(fields)
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	orderKeys(keys, order)
+	for _, k := range keys {
+		line += fmt.Sprintf(" %s=%v", k, fields[k])
+	}
+	return line
+}
+
+//textMessage renders the arguments of a text line, "[a b]" for several arguments, a single string as it is
+//and nothing when there are no arguments
+func textMessage(v []interface{}) string {
+	switch len(v) {
+	case 0:
+		return ""
+	case 1:
+		if msg, ok := v[0].(string); ok {
+			return msg
+		}
+	}
+	return fmt.Sprintf("%v", errorArgs(v))
+}
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+//upperFormatter is a custom formatter used to check that formatters are pluggable
+type upperFormatter struct{}
+
+func (f upperFormatter) Format(level string, fields map[string]interface{}, args []interface{}) ([]byte, error) {
+	return []byte(fmt.Sprintf("<%s|%s|%v>\n", level, joinArgs(args), fields["user"])), nil
+}
+
+//failFormatter always fails
+type failFormatter struct{}
+
+func (f failFormatter) Format(level string, fields map[string]interface{}, args []interface{}) ([]byte, error) {
+	return nil, errors.New("format failed")
+}
+
+func TestWriterLogDefaultFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.OnInit(func(s *WriterLog) {
+		s.SetOutput(&buf)
+	})
+	if err := wl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	wl.Info("This is a message")
+	wl.WithFields(map[string]interface{}{"user": "bob"}).Error("This is a message")
+	testOutput(buf.String(), "Info This is a message\nError This is a message user=bob\n", t)
+}
+
+func TestTextLineArgs(t *testing.T) {
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	wl.Info()
+	wl.Info(nil...)
+	wl.Info("one string")
+	wl.Info("two", "strings")
+	wl.Info(42)
+	wl.WithFields(map[string]interface{}{"user": "bob"}).Info()
+	testOutput(buf.String(), "Info\nInfo\nInfo one string\nInfo [two strings]\nInfo [42]\nInfo user=bob\n", t)
+}
+
+func TestWriterLogCustomFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	wl.SetFormatter(upperFormatter{})
+	wl.WithFields(map[string]interface{}{"user": "bob"}).Warning("This is", "a message")
+	testOutput(buf.String(), "<Warning|This is a message|bob>\n", t)
+
+	wl.SetFormatter(failFormatter{})
+	wl.Info("This is a message")
+	if wl.Err() == nil {
+		t.Error("expected the format error to be reported")
+	}
+}
+
+func TestFileLogCustomFormatter(t *testing.T) {
+	fl := new(FileLog)
+	fl.OnInit(tlCallback, func(s *FileLog) {
+		s.SetFormatter(upperFormatter{})
+	})
+	if err := fl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer os.Remove(fl.logPath)
+	fl.Info("This is a message")
+	fl.Close()
+
+	b, err := ioutil.ReadFile(fl.logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testOutput(string(b), "<Info|This is a message|<nil>>\n", t)
+}
+
+func TestBuiltInFormatters(t *testing.T) {
+	fields := map[string]interface{}{"user": "bob"}
+	args := []interface{}{"This is a message"}
+
+	b, _ := TextFormatter{}.Format("Info", fields, args)
+	testOutput(string(b), "Info This is a message user=bob\n", t)
+
+	b, _ = LogfmtFormatter{}.Format("Info", fields, args)
+	testOutput(string(b), "level=Info msg=\"This is a message\" user=bob\n", t)
+
+	b, _ = JSONFormatter{}.Format("Info", fields, args)
+	m := decodeJSONLine(b, t)
+	if m["level"] != "Info" || m["message"] != "This is a message" || m["user"] != "bob" {
+		t.Error("unexpected JSON", m)
+	}
+}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+//GELFFormatter renders entries as newline delimited Graylog Extended Log Format (GELF 1.1) JSON
+//Fields and map[string]interface{} arguments become additional fields prefixed with an underscore
+//It can be used with WriterLog or FileLog, e.g. for a collector forwarding the file to Graylog
+//NetLog does not suit Graylog's GELF inputs, GELF over TCP is delimited by null bytes and NetLog neither chunks
+//nor compresses UDP datagrams, it truncates them instead
+type GELFFormatter struct {
+	//Host is the source host, os.Hostname is used when it is empty, looked up once for the process
+	Host string
+
+	clock Clock
+}
+
+func (f GELFFormatter) withBase(l *LogBase) Formatter {
+	f.clock = l.clock
+	return f
+}
+
+//gelfHost holds the host name used by formatters without a Host, it is looked up on first use only
+var gelfHost struct {
+	once sync.Once
+	name string
+}
+
+//defaultGELFHost returns the host name for formatters without a Host
+func defaultGELFHost() string {
+	gelfHost.once.Do(func() {
+		gelfHost.name, _ = osHostname()
+	})
+	return gelfHost.name
+}
+
+//gelfInvalidKey matches the characters not allowed in GELF additional field names
+var gelfInvalidKey = regexp.MustCompile(`[^\w.\-]`)
+
+//Format implements Formatter
+func (f GELFFormatter) Format(level string, fields map[string]interface{}, args []interface{}) ([]byte, error) {
+	host := f.Host
+	if host == "" {
+		host = defaultGELFHost()
+	}
+	//GELF uses the syslog numeric severity, custom levels are sent as informational
+	sev, err := ParseLevel(level)
+	if err != nil {
+		sev = LevelInfo
+	}
+
+	obj := make(map[string]interface{}, len(fields)+5)
+	for k, v := range flattenGroups(fields) {
+		obj[gelfKey(k)] = v
+	}
+	msg := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		if m, ok := arg.(map[string]interface{}); ok {
+			for k, v := range m {
+				obj[gelfKey(k)] = v
+			}
+			continue
+		}
+		msg = append(msg, arg)
+	}
+	short := joinArgs(msg)
+	if short == "" {
+		//short_message is required to be non empty
+		short = level
+	}
+	obj["version"] = "1.1"
+	obj["host"] = host
+	obj["short_message"] = short
+	obj["timestamp"] = float64(clockNow(f.clock).UnixNano()/int64(time.Millisecond)) / 1000
+	obj["level"] = int(sev.standard())
+
+	b, err := json.Marshal(obj)
+	if err != nil {
+		for k, v := range obj {
+			if k[0] == '_' {
+				obj[k] = fmt.Sprint(v)
+			}
+		}
+		if b, err = json.Marshal(obj); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, '\n'), nil
+}
+
+//gelfKey turns a field name into a valid GELF additional field name
+//"id" is reserved by Graylog so it is written as "_id_"
+func gelfKey(k string) string {
+	k = "_" + gelfInvalidKey.ReplaceAllString(k, "_")
+	if k == "_id" {
+		return "_id_"
+	}
+	return k
+}
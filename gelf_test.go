@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGELFFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	wl.SetFormatter(GELFFormatter{Host: "web-1"})
+
+	before := float64(time.Now().Unix())
+	wl.WithFields(map[string]interface{}{"request_id": "abc", "id": 7}).Error("db write failed", map[string]interface{}{"bad key!": true})
+	m := decodeJSONLine(buf.Bytes(), t)
+
+	//Required GELF fields
+	if m["version"] != "1.1" {
+		t.Error("unexpected version", m["version"])
+	}
+	if m["host"] != "web-1" {
+		t.Error("unexpected host", m["host"])
+	}
+	if m["short_message"] != "db write failed" {
+		t.Error("unexpected short_message", m["short_message"])
+	}
+	if m["level"] != float64(3) {
+		t.Error("expected the syslog severity for Error, got", m["level"])
+	}
+	ts, ok := m["timestamp"].(float64)
+	if !ok || ts < before || ts > before+5 {
+		t.Error("unexpected timestamp", m["timestamp"])
+	}
+
+	//Additional fields are prefixed and valid
+	if m["_request_id"] != "abc" || m["_bad_key_"] != true || m["_id_"] != float64(7) {
+		t.Error("unexpected additional fields", m)
+	}
+	valid := regexp.MustCompile(`^_[\w.\-]+$`)
+	for k := range m {
+		switch k {
+		case "version", "host", "short_message", "timestamp", "level":
+			continue
+		}
+		if !valid.MatchString(k) || k == "_id" {
+			t.Error("invalid additional field", k)
+		}
+	}
+}
+
+func TestGELFFormatterLevels(t *testing.T) {
+	for level, expected := range map[string]float64{"Emergency": 0, "Warning": 4, "Debug": 7, "custom": 6} {
+		b, err := GELFFormatter{Host: "h"}.Format(level, nil, []interface{}{"x"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m := decodeJSONLine(b, t); m["level"] != expected {
+			t.Error("unexpected level for", level, m["level"])
+		}
+	}
+}
+
+func TestGELFFormatterHostLookup(t *testing.T) {
+	lookups := 0
+	osHostname = func() (string, error) {
+		lookups++
+		return "web-1", nil
+	}
+	gelfHost.once = sync.Once{}
+	defer func() {
+		osHostname = os.Hostname
+		gelfHost.once = sync.Once{}
+	}()
+
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	wl.SetFormatter(GELFFormatter{})
+	for i := 0; i < 3; i++ {
+		buf.Reset()
+		wl.Info("This is a message")
+		if m := decodeJSONLine(buf.Bytes(), t); m["host"] != "web-1" {
+			t.Fatal("unexpected host", m["host"])
+		}
+	}
+	if lookups != 1 {
+		t.Error("expected the host name to be looked up once, got", lookups)
+	}
+}
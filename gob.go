@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"time"
+)
+
+//maxFrameSize limits the size of a single encoded entry so that corrupt input can not cause a huge allocation
+const maxFrameSize = 64 << 20
+
+func init() {
+	//Types that appear in fields and arguments beyond the basic types gob registers itself
+	gob.Register(time.Time{})
+	gob.Register(time.Duration(0))
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+}
+
+//Encoder writes entries as length prefixed gob frames, for shipping logs between processes
+//Each frame is a 4 byte big endian length followed by a self contained gob encoding of the entry,
+//so a reader can start at any frame boundary
+type Encoder struct {
+	w io.Writer
+}
+
+//NewEncoder returns an Encoder writing to w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+//Encode writes e as a single frame with one call to Write, so encoders sharing a writer that serializes writes do not interleave
+//Field and argument values that gob can not carry, such as errors and structs, are sent as their fmt.Sprint text
+func (enc *Encoder) Encode(e Entry) error {
+	e.Fields = gobFields(e.Fields)
+	e.Args = gobArgs(e.Args)
+	var buf bytes.Buffer
+	buf.Write(make([]byte, 4))
+	if err := gob.NewEncoder(&buf).Encode(&e); err != nil {
+		return err
+	}
+	b := buf.Bytes()
+	binary.BigEndian.PutUint32(b, uint32(len(b)-4))
+	_, err := enc.w.Write(b)
+	return err
+}
+
+//Decoder reads entries written by an Encoder
+type Decoder struct {
+	r io.Reader
+}
+
+//NewDecoder returns a Decoder reading from r
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+//Decode reads the next entry, io.EOF is returned once the input ends cleanly between frames
+func (dec *Decoder) Decode() (Entry, error) {
+	return ReadEntry(dec.r)
+}
+
+//ReadEntry reads a single frame written by an Encoder from r
+//io.EOF is returned when r ends before the frame starts and io.ErrUnexpectedEOF when it ends part way through
+func ReadEntry(r io.Reader) (Entry, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return Entry{}, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > maxFrameSize {
+		return Entry{}, fmt.Errorf("entry frame of %d bytes is larger than the %d byte limit", n, maxFrameSize)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return Entry{}, err
+	}
+	var e Entry
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&e); err != nil {
+		return Entry{}, err
+	}
+	return e, nil
+}
+
+//gobFields returns a copy of fields holding only values gob can encode
+func gobFields(fields map[string]interface{}) map[string]interface{} {
+	if fields == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		out[k] = gobValue(v)
+	}
+	return out
+}
+
+//gobArgs returns a copy of args holding only values gob can encode
+func gobArgs(args []interface{}) []interface{} {
+	if args == nil {
+		return nil
+	}
+	out := make([]interface{}, len(args))
+	for i, v := range args {
+		out[i] = gobValue(v)
+	}
+	return out
+}
+
+//gobValue returns v if gob can encode it as an interface value, or its fmt.Sprint text
+func gobValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil, bool, string, []byte,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, uintptr,
+		float32, float64, complex64, complex128,
+		time.Time, time.Duration:
+		return v
+	case map[string]interface{}:
+		return gobFields(val)
+	case fieldGroup:
+		return gobFields(val)
+	case []interface{}:
+		return gobArgs(val)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,193 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+//GobLog writes each entry to an io.Writer as a length prefixed gob frame, see Encoder
+//It is intended for passing logs to another process, which reads them back with a Decoder
+type GobLog struct {
+	LogBase
+	out io.Writer
+}
+
+//Init expects input to be a list of func(s *GobLog) which will be called on initialization
+func (s *GobLog) Init() error {
+	for _, fn := range s.initializers {
+		switch funct := fn.(type) {
+		case func(s *GobLog):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *GobLog) or func(s Logger)")
+		}
+	}
+	return nil
+}
+
+//SetOutput sets the destination writer, os.Stdout is used when none is set
+//Each entry is written with a single call to Write
+func (s *GobLog) SetOutput(w io.Writer) {
+	s.out = w
+}
+
+//WithFields returns a copy of the logger, sharing the same writer, that adds fields to every entry
+func (s *GobLog) WithFields(fields map[string]interface{}) Logger {
+	s.tracker()
+	c := *s
+	c.fields = s.mergeFields(fields)
+	return &c
+}
+
+//WithContext returns a copy of the logger carrying the context values registered with RegisterContextField
+func (s *GobLog) WithContext(ctx context.Context) Logger {
+	return s.WithFields(fieldsFromContext(ctx))
+}
+
+//WithError returns a copy of the logger carrying err as an error field, with a cause field when it wraps other errors
+//A nil err returns the logger itself
+func (s *GobLog) WithError(err error) Logger {
+	if err == nil {
+		return s
+	}
+	return s.WithFields(errorFields([]interface{}{err}))
+}
+
+//Named returns a copy of the logger, sharing the same output, whose entries are tagged with name
+func (s *GobLog) Named(name string) Logger {
+	s.tracker()
+	c := *s
+	c.name = s.childName(name)
+	return &c
+}
+
+//WithGroup returns a copy of the logger whose later fields are grouped under name, e.g. WithGroup("http")
+func (s *GobLog) WithGroup(name string) Logger {
+	s.tracker()
+	c := *s
+	c.groups = s.childGroups(name)
+	return &c
+}
+
+//Clone returns a copy of the logger whose configuration can be changed independently, it writes to the same output as s
+func (s *GobLog) Clone() Logger {
+	c := *s
+	c.detach()
+	return &c
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *GobLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *GobLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *GobLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *GobLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *GobLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *GobLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
+func (s *GobLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *GobLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *GobLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *GobLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *GobLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *GobLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *GobLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *GobLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *GobLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *GobLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *GobLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *GobLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *GobLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *GobLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *GobLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *GobLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *GobLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+func (s *GobLog) Log(level string, v ...interface{}) {
+	if err := s.write(level, v); err != nil {
+		s.handleError(err)
+	}
+}
+
+//TryLog is Log that also returns any error hit while writing
+func (s *GobLog) TryLog(level string, v ...interface{}) error {
+	err := s.write(level, v)
+	if err != nil {
+		s.handleError(err)
+	}
+	return err
+}
+
+//write encodes and writes a single entry
+func (s *GobLog) write(level string, v []interface{}) error {
+	if !s.shouldLog(level) {
+		return nil
+	}
+	out := s.out
+	if out == nil {
+		out = os.Stdout
+	}
+	fields, args := s.prepare(level, v)
+	return NewEncoder(out).Encode(s.newEntry(level, fields, args))
+}
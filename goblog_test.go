@@ -0,0 +1,128 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEncoderRoundTrip(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.UTC)
+	entries := []Entry{
+		{Time: at, Level: "Info", Severity: LevelInfo, Message: "started", Args: []interface{}{"started"}},
+		{
+			Time:     at.Add(time.Second),
+			Level:    "Error",
+			Severity: LevelError,
+			Message:  "query failed 3",
+			Fields: map[string]interface{}{
+				"user":    "bob",
+				"retries": 3,
+				"ratio":   0.5,
+				"ok":      false,
+				"elapsed": 2 * time.Second,
+				"nested":  map[string]interface{}{"id": int64(7)},
+			},
+			Args: []interface{}{"query failed", 3},
+		},
+		{Time: at.Add(2 * time.Second), Level: "custom level", Severity: LevelInfo},
+	}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dec := NewDecoder(&buf)
+	for i, want := range entries {
+		got, err := dec.Decode()
+		if err != nil {
+			t.Fatal(i, err)
+		}
+		if !got.Time.Equal(want.Time) {
+			t.Error("unexpected time", i, got.Time)
+		}
+		got.Time, want.Time = time.Time{}, time.Time{}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("entry %d decoded as %#v, want %#v", i, got, want)
+		}
+	}
+	if _, err := dec.Decode(); err != io.EOF {
+		t.Error("expected io.EOF at the end, got", err)
+	}
+}
+
+func TestEncoderUnsupportedValues(t *testing.T) {
+	type point struct{ X, Y int }
+	var buf bytes.Buffer
+	err := NewEncoder(&buf).Encode(Entry{
+		Level:  "Error",
+		Fields: map[string]interface{}{"error": errors.New("disk full"), "at": point{1, 2}, "none": nil},
+		Args:   []interface{}{point{3, 4}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := ReadEntry(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Fields["error"] != "disk full" || e.Fields["at"] != "{1 2}" || e.Fields["none"] != nil || e.Args[0] != "{3 4}" {
+		t.Error("expected unsupported values as text, got", e.Fields, e.Args)
+	}
+}
+
+func TestReadEntryTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	NewEncoder(&buf).Encode(Entry{Level: "Info", Message: "This is a message"})
+	b := buf.Bytes()
+	if _, err := ReadEntry(bytes.NewReader(b[:len(b)-1])); err != io.ErrUnexpectedEOF {
+		t.Error("expected io.ErrUnexpectedEOF, got", err)
+	}
+	if _, err := ReadEntry(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff})); err == nil {
+		t.Error("expected an error for an oversized frame")
+	}
+}
+
+func TestGobLog(t *testing.T) {
+	var buf bytes.Buffer
+	gl := new(GobLog)
+	gl.SetOutput(&buf)
+	before := time.Now()
+	gl.SetPrefix("worker")
+	gl.WithFields(map[string]interface{}{"job": 42}).Warning("slow", 1.5)
+	gl.Info("done")
+
+	dec := NewDecoder(&buf)
+	e, err := dec.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Level != "Warning" || e.Severity != LevelWarning || e.Message != "slow 1.5" || e.Time.Before(before) {
+		t.Error("unexpected entry", e)
+	}
+	if e.Fields["job"] != 42 || e.Fields["logger"] != "worker" {
+		t.Error("unexpected fields", e.Fields)
+	}
+	if e, err = dec.Decode(); err != nil || e.Message != "done" {
+		t.Error("unexpected second entry", e, err)
+	}
+
+	gl.SetOutput(errWriter{})
+	if err := gl.TryLog("Info", "x"); err == nil {
+		t.Error("expected the write error to be returned")
+	}
+}
+
+//errWriter fails every write
+type errWriter struct{}
+
+func (errWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
@@ -0,0 +1,55 @@
+package logger
+
+//fieldGroup holds the fields added under a WithGroup name
+//JSON output writes it as a nested object and text output flattens it into dotted keys
+type fieldGroup map[string]interface{}
+
+//childGroups returns the group path for a logger created with WithGroup, an empty name leaves it unchanged
+func (l *LogBase) childGroups(name string) []string {
+	if name == "" {
+		return l.groups
+	}
+	groups := make([]string, len(l.groups), len(l.groups)+1)
+	copy(groups, l.groups)
+	return append(groups, name)
+}
+
+//groupFields nests fields under the logger's group path
+func (l *LogBase) groupFields(fields map[string]interface{}) map[string]interface{} {
+	if len(fields) == 0 {
+		return fields
+	}
+	for i := len(l.groups) - 1; i >= 0; i-- {
+		fields = map[string]interface{}{l.groups[i]: fieldGroup(fields)}
+	}
+	return fields
+}
+
+//flattenGroups returns fields with each group replaced by its fields under dotted keys, e.g. "http.method"
+//fields itself is returned when it holds no groups
+func flattenGroups(fields map[string]interface{}) map[string]interface{} {
+	grouped := false
+	for _, v := range fields {
+		if _, ok := v.(fieldGroup); ok {
+			grouped = true
+			break
+		}
+	}
+	if !grouped {
+		return fields
+	}
+	flat := make(map[string]interface{}, len(fields))
+	addFlattened(flat, "", fields)
+	return flat
+}
+
+//addFlattened adds fields to flat with their keys prefixed by prefix
+func addFlattened(flat map[string]interface{}, prefix string, fields map[string]interface{}) {
+	for k, v := range fields {
+		if g, ok := v.(fieldGroup); ok {
+			addFlattened(flat, prefix+k+".", g)
+			continue
+		}
+		flat[prefix+k] = v
+	}
+}
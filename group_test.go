@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+var (
+	_ GroupLogger = new(FmtLog)
+	_ GroupLogger = new(StdLog)
+	_ GroupLogger = new(FileLog)
+	_ GroupLogger = new(WriterLog)
+	_ GroupLogger = new(JSONLog)
+	_ GroupLogger = new(LogfmtLog)
+	_ GroupLogger = new(NetLog)
+	_ GroupLogger = new(GobLog)
+	_ GroupLogger = new(JournaldLog)
+)
+
+func TestWithGroupText(t *testing.T) {
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+
+	lg := wl.WithFields(map[string]interface{}{"user": "bob"}).(GroupLogger).WithGroup("http").(FieldLogger)
+	lg.WithFields(map[string]interface{}{"method": "GET", "status": 200}).Info("served")
+	testOutput(buf.String(), "Info served http.method=GET http.status=200 user=bob\n", t)
+
+	//Groups compose and fields added at different depths are kept together
+	buf.Reset()
+	req := lg.WithFields(map[string]interface{}{"method": "POST"}).(GroupLogger).WithGroup("req").(FieldLogger)
+	req.WithFields(map[string]interface{}{"id": 7}).Info("served")
+	testOutput(buf.String(), "Info served http.method=POST http.req.id=7 user=bob\n", t)
+
+	//A group without fields adds nothing
+	buf.Reset()
+	wl.WithGroup("empty").Info("served")
+	testOutput(buf.String(), "Info served\n", t)
+}
+
+func TestWithGroupJSON(t *testing.T) {
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	jl.SetPrefix("api")
+
+	lg := jl.WithGroup("http").(GroupLogger).WithGroup("req").(FieldLogger)
+	lg.WithFields(map[string]interface{}{"method": "GET"}).Info("served")
+	m := decodeJSONLine(buf.Bytes(), t)
+	expected := map[string]interface{}{"req": map[string]interface{}{"method": "GET"}}
+	if !reflect.DeepEqual(m["http"], expected) {
+		t.Error("expected the fields nested under http.req, got", m)
+	}
+	if m["logger"] != "api" {
+		t.Error("expected the logger name to stay at the top level, got", m["logger"])
+	}
+}
+
+func TestWithGroupLogfmt(t *testing.T) {
+	var buf bytes.Buffer
+	ll := new(LogfmtLog)
+	ll.SetOutput(&buf)
+	ll.WithGroup("db").(FieldLogger).WithFields(map[string]interface{}{"table": "users"}).Info("query")
+	testOutput(buf.String(), "level=Info msg=\"query\" db.table=users\n", t)
+}
+
+func TestWithGroupRedact(t *testing.T) {
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	jl.SetRedactKeys("password")
+	jl.WithGroup("auth").(FieldLogger).WithFields(map[string]interface{}{"user": "bob", "password": "hunter2"}).Info("login")
+	m := decodeJSONLine(buf.Bytes(), t)
+	expected := map[string]interface{}{"user": "bob", "password": redacted}
+	if !reflect.DeepEqual(m["auth"], expected) {
+		t.Error("expected the grouped password to be redacted, got", m["auth"])
+	}
+}
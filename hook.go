@@ -0,0 +1,31 @@
+package logger
+
+import "fmt"
+
+//Hook is called with every entry a logger is about to write, after level filtering and redaction
+//fields and args must be treated as read only as they are shared with the logger
+type Hook func(level string, fields map[string]interface{}, args []interface{})
+
+//AddHook registers a hook that is called synchronously before each entry is written
+//e.g. to count errors for metrics without coupling the metrics code to a logger
+//A hook that panics is recovered and the panic is reported through OnError, the entry is still written
+func (l *LogBase) AddHook(h Hook) {
+	l.hooks = append(l.hooks, h)
+}
+
+//runHooks calls each hook in the order they were added
+func (l *LogBase) runHooks(level string, fields map[string]interface{}, args []interface{}) {
+	for _, h := range l.hooks {
+		l.runHook(h, level, fields, args)
+	}
+}
+
+//runHook calls a single hook, recovering a panic so that it can not break logging
+func (l *LogBase) runHook(h Hook, level string, fields map[string]interface{}, args []interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			l.handleError(fmt.Errorf("hook panicked: %v", r))
+		}
+	}()
+	h(level, fields, args)
+}
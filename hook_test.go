@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAddHook(t *testing.T) {
+	type seen struct {
+		level string
+		args  []interface{}
+	}
+	var calls []seen
+	ml := new(MemoryLog)
+	ml.SetLevel("Info")
+	ml.AddHook(func(level string, fields map[string]interface{}, args []interface{}) {
+		//Hooks run before the entry is stored
+		if n := len(ml.Entries()); n != len(calls) {
+			t.Errorf("expected hook to run before the entry is written, %d entries for %d calls", n, len(calls))
+		}
+		calls = append(calls, seen{level, args})
+	})
+
+	methods := map[string]func(v ...interface{}){
+		"Emergency": ml.Emergency,
+		"Alert":     ml.Alert,
+		"Critical":  ml.Critical,
+		"Error":     ml.Error,
+		"Warning":   ml.Warning,
+		"Notice":    ml.Notice,
+		"Info":      ml.Info,
+	}
+	for level, method := range methods {
+		calls = nil
+		ml.Reset()
+		method("message", level)
+		if len(calls) != 1 {
+			t.Fatalf("expected one hook call for %s, got %d", level, len(calls))
+		}
+		if calls[0].level != level || calls[0].args[0] != "message" || calls[0].args[1] != level {
+			t.Errorf("unexpected hook call for %s: %v", level, calls[0])
+		}
+	}
+
+	//Filtered entries do not reach hooks
+	calls = nil
+	ml.Reset()
+	ml.Debug("message")
+	if len(calls) != 0 {
+		t.Error("expected no hook call for a filtered entry, got", calls)
+	}
+}
+
+func TestAddHookFields(t *testing.T) {
+	var got map[string]interface{}
+	jl := new(JSONLog)
+	jl.SetOutput(new(bytes.Buffer))
+	jl.AddHook(func(level string, fields map[string]interface{}, args []interface{}) {
+		got = fields
+	})
+	jl.WithFields(map[string]interface{}{"user": "bob"}).Error("message")
+	if got["user"] != "bob" {
+		t.Error("expected hook to see the logger's fields, got", got)
+	}
+}
+
+func TestAddHookPanic(t *testing.T) {
+	var errs []error
+	ml := new(MemoryLog)
+	ml.OnError(func(err error) {
+		errs = append(errs, err)
+	})
+	ml.AddHook(func(level string, fields map[string]interface{}, args []interface{}) {
+		panic("broken hook")
+	})
+	called := false
+	ml.AddHook(func(level string, fields map[string]interface{}, args []interface{}) {
+		called = true
+	})
+	ml.Error("message")
+	if len(ml.Entries()) != 1 {
+		t.Error("expected the entry to be written despite the panic")
+	}
+	if !called {
+		t.Error("expected later hooks to still run")
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "broken hook") {
+		t.Error("expected the panic to be reported, got", errs)
+	}
+}
@@ -0,0 +1,32 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+)
+
+//osHostname looks up the host name, it is replaced in tests to count the lookups
+var osHostname = os.Hostname
+
+//SetIncludeHost adds a "host" field holding os.Hostname to every entry
+//The name is looked up once here rather than on every call, a failed lookup is reported through OnError and the field left out
+func (l *LogBase) SetIncludeHost(enabled bool) {
+	l.host = ""
+	if !enabled {
+		return
+	}
+	host, err := osHostname()
+	if err != nil {
+		l.handleError(fmt.Errorf("looking up the host name: %w", err))
+		return
+	}
+	l.host = host
+}
+
+//SetIncludePID adds a "pid" field holding the process id to every entry
+func (l *LogBase) SetIncludePID(enabled bool) {
+	l.pid = 0
+	if enabled {
+		l.pid = os.Getpid()
+	}
+}
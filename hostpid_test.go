@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestIncludeHostAndPID(t *testing.T) {
+	lookups := 0
+	osHostname = func() (string, error) {
+		lookups++
+		return "web-1", nil
+	}
+	defer func() { osHostname = os.Hostname }()
+
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	jl.SetIncludeHost(true)
+	jl.SetIncludePID(true)
+	child := jl.WithFields(map[string]interface{}{"user": "bob"})
+	for i := 0; i < 3; i++ {
+		buf.Reset()
+		child.Info("This is a message")
+		line := decodeJSONLine(buf.Bytes(), t)
+		if line["host"] != "web-1" || line["pid"] != float64(os.Getpid()) || line["user"] != "bob" {
+			t.Fatal("unexpected line", line)
+		}
+	}
+	if lookups != 1 {
+		t.Error("expected the host name to be looked up once, got", lookups)
+	}
+
+	buf.Reset()
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	wl.SetIncludeHost(true)
+	wl.SetIncludePID(true)
+	wl.Info("This is a message")
+	testOutput(buf.String(), fmt.Sprintf("Info This is a message host=web-1 pid=%d\n", os.Getpid()), t)
+
+	buf.Reset()
+	wl.SetIncludeHost(false)
+	wl.SetIncludePID(false)
+	wl.Info("This is a message")
+	testOutput(buf.String(), "Info This is a message\n", t)
+}
+
+func TestIncludeHostLookupError(t *testing.T) {
+	osHostname = func() (string, error) {
+		return "", errors.New("no host name")
+	}
+	defer func() { osHostname = os.Hostname }()
+
+	ml := new(MemoryLog)
+	ml.SetIncludeHost(true)
+	if ml.Err() == nil {
+		t.Error("expected the lookup error to be reported")
+	}
+	ml.Info("This is a message")
+	if e, _ := ml.LastEntry(); e.Fields["host"] != nil {
+		t.Error("expected no host field, got", e.Fields)
+	}
+}
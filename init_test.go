@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+//initLogger is a logger that also exposes OnInitLogger
+type initLogger interface {
+	Logger
+	OnInitLogger(f func(s Logger))
+}
+
+func TestOnInitLogger(t *testing.T) {
+	fl := new(FileLog)
+	fl.OnInit(tlCallback)
+	loggers := []initLogger{
+		new(FmtLog),
+		new(StdLog),
+		fl,
+		new(JSONLog),
+		new(LogfmtLog),
+		new(WriterLog),
+		new(MemoryLog),
+		new(RingLog),
+		new(Stack),
+		NewSyncLog(new(MemoryLog)),
+		NewLevelLog(new(MemoryLog), "Debug"),
+		NewDedupLog(new(MemoryLog)),
+		NewSampleLog(new(MemoryLog), nil),
+		NewRateLimitLog(new(MemoryLog), 10, time.Second),
+	}
+	for _, lg := range loggers {
+		var called []Logger
+		lg.OnInitLogger(func(s Logger) {
+			called = append(called, s)
+		})
+		if err := lg.Init(); err != nil {
+			t.Errorf("%T: Init failed %v", lg, err)
+			continue
+		}
+		if len(called) != 1 || called[0] == nil {
+			t.Errorf("%T: expected the generic callback to be called once, got %d", lg, len(called))
+		}
+	}
+	fl.Close()
+	os.Remove(fl.logPath)
+
+	//Typed and generic callbacks run together, in the order they were added
+	var order []string
+	ml := new(MemoryLog)
+	ml.OnInit(func(s *MemoryLog) {
+		order = append(order, "typed")
+	})
+	ml.OnInitLogger(func(s Logger) {
+		if s != Logger(ml) {
+			t.Error("expected the generic callback to receive the logger itself")
+		}
+		order = append(order, "generic")
+	})
+	if err := ml.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(order, ",") != "typed,generic" {
+		t.Error("unexpected order", order)
+	}
+}
+
+func TestOnInitWrongSignature(t *testing.T) {
+	fmtLog := new(FmtLog)
+	fmtLog.OnInit(func(s *StdLog) {})
+	err := fmtLog.Init()
+	if err == nil || err.Error() != "Init callbacks must have signature func(s *FmtLog) or func(s Logger)" {
+		t.Error("unexpected error", err)
+	}
+
+	stack := new(Stack)
+	stack.OnInit(func(s *Stack) {})
+	if err := stack.Init(); err != nil {
+		t.Error("expected a typed callback to be accepted by a Stack, got", err)
+	}
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+//SetInterpolate replaces {placeholders} in a leading string message with the values of a trailing context map, as PSR-3 does
+//e.g. l.Info("User {id} logged in", map[string]interface{}{"id": 42}) logs "User 42 logged in" with id as a field
+//Every key of the map is attached as a field, placeholders without a matching key are left as they are
+func (l *LogBase) SetInterpolate(enabled bool) {
+	l.interpolate = enabled
+}
+
+//interpolateFields substitutes the context map ending v into the message starting it and moves the map into a copy of fields
+func interpolateFields(fields map[string]interface{}, v []interface{}) (map[string]interface{}, []interface{}) {
+	if len(v) < 2 {
+		return fields, v
+	}
+	msg, ok := v[0].(string)
+	if !ok {
+		return fields, v
+	}
+	context, ok := v[len(v)-1].(map[string]interface{})
+	if !ok {
+		return fields, v
+	}
+	args := make([]interface{}, 0, len(v)-1)
+	args = append(args, interpolateMessage(msg, context))
+	args = append(args, v[1:len(v)-1]...)
+	return addFields(fields, context), args
+}
+
+//interpolateMessage replaces each {key} in msg with the value of key in context
+//Placeholder names are made of letters, digits, underscores and dots as PSR-3 specifies
+func interpolateMessage(msg string, context map[string]interface{}) string {
+	if !strings.Contains(msg, "{") {
+		return msg
+	}
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(msg, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(msg[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		name := msg[start+1 : end]
+		val, ok := context[name]
+		if !ok || !placeholderName(name) {
+			//Keep the brace and carry on from the next character, it may open a nested placeholder
+			b.WriteString(msg[:start+1])
+			msg = msg[start+1:]
+			continue
+		}
+		b.WriteString(msg[:start])
+		b.WriteString(fmt.Sprint(val))
+		msg = msg[end+1:]
+	}
+	b.WriteString(msg)
+	return b.String()
+}
+
+//placeholderName reports whether name is a valid PSR-3 placeholder
+func placeholderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	ml := new(MemoryLog)
+	ml.SetInterpolate(true)
+	ml.Info("User {id} logged in from {user.ip}", map[string]interface{}{"id": 42, "user.ip": "10.0.0.1"})
+	e, _ := ml.LastEntry()
+	if e.Message != "User 42 logged in from 10.0.0.1" || len(e.Args) != 1 {
+		t.Error("unexpected message", e.Message, e.Args)
+	}
+	if e.Fields["id"] != 42 || e.Fields["user.ip"] != "10.0.0.1" || len(e.Fields) != 2 {
+		t.Error("expected the context to be attached as fields, got", e.Fields)
+	}
+
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	wl.SetInterpolate(true)
+	wl.WithFields(map[string]interface{}{"service": "api"}).Warning("{count} retries", map[string]interface{}{"count": 3})
+	testOutput(buf.String(), "Warning 3 retries count=3 service=api\n", t)
+}
+
+func TestInterpolateMissingKeys(t *testing.T) {
+	expected := map[string]string{
+		"User {name} logged in":  "User {name} logged in",
+		"{id}{id} {missing}":     "77 {missing}",
+		"{bad key} {id}":         "{bad key} 7",
+		"{{id}}":                 "{7}",
+		"unclosed {id":           "unclosed {id",
+		"empty {} braces":        "empty {} braces",
+		"no placeholders at all": "no placeholders at all",
+	}
+	context := map[string]interface{}{"id": 7}
+	for msg, want := range expected {
+		if got := interpolateMessage(msg, context); got != want {
+			t.Errorf("interpolating %q: expected %q, got %q", msg, want, got)
+		}
+	}
+}
+
+func TestInterpolateExtraKeys(t *testing.T) {
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	jl.SetInterpolate(true)
+	jl.Error("payment {order} declined", map[string]interface{}{"order": 9, "amount": 12.5, "currency": "EUR"})
+	line := decodeJSONLine(buf.Bytes(), t)
+	if line["message"] != "payment 9 declined" || line["order"] != 9.0 || line["amount"] != 12.5 || line["currency"] != "EUR" {
+		t.Error("unexpected line", line)
+	}
+}
+
+func TestInterpolateDisabled(t *testing.T) {
+	ml := new(MemoryLog)
+	ml.Info("User {id}", map[string]interface{}{"id": 42})
+	e, _ := ml.LastEntry()
+	if len(e.Args) != 2 || e.Message == "User 42" {
+		t.Error("expected the arguments to be left alone by default, got", e)
+	}
+
+	//Interpolation needs a string message and a trailing map
+	ml.SetInterpolate(true)
+	ml.Info(42, map[string]interface{}{"id": 42})
+	e, _ = ml.LastEntry()
+	if len(e.Args) != 2 || len(e.Fields) != 0 {
+		t.Error("expected the arguments to be left alone, got", e)
+	}
+	ml.Info("User {id}")
+	e, _ = ml.LastEntry()
+	if e.Message != "User {id}" {
+		t.Error("unexpected message", e.Message)
+	}
+}
+
+func TestInterpolateRedacted(t *testing.T) {
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	wl.SetInterpolate(true)
+	wl.SetRedactKeys("password")
+	wl.Info("login {user} {password}", map[string]interface{}{"user": "bob", "password": "hunter2"})
+	testOutput(buf.String(), "Info login bob [REDACTED] password=[REDACTED] user=bob\n", t)
+
+	buf.Reset()
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	jl.SetInterpolate(true)
+	jl.SetRedactKeys("password")
+	jl.Info("login {password}", map[string]interface{}{"password": "hunter2"})
+	if m := decodeJSONLine(buf.Bytes(), t); m["message"] != "login [REDACTED]" || m["password"] != "[REDACTED]" {
+		t.Error("expected the redacted value to stay out of the message, got", m)
+	}
+}
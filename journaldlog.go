@@ -0,0 +1,296 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+//defaultJournalSocket is the socket journald reads native protocol datagrams from
+const defaultJournalSocket = "/run/systemd/journal/socket"
+
+//JournaldLog writes to the systemd journal using its native protocol so that fields are kept as journal fields
+//Fields and map[string]interface{} arguments are written upper cased, e.g. user_id as USER_ID,
+//alongside MESSAGE, PRIORITY and SYSLOG_IDENTIFIER
+type JournaldLog struct {
+	LogBase
+	socket     string
+	identifier string
+	conn       *net.UnixConn
+}
+
+//SetSocket sets the journald socket path, /run/systemd/journal/socket by default
+func (s *JournaldLog) SetSocket(path string) {
+	s.socket = path
+}
+
+//SetIdentifier sets SYSLOG_IDENTIFIER, the program name by default
+func (s *JournaldLog) SetIdentifier(identifier string) {
+	s.identifier = identifier
+}
+
+//Init expects input to be a list of func(s *JournaldLog) and then connects to journald
+func (s *JournaldLog) Init() error {
+	for _, fn := range s.initializers {
+		switch funct := fn.(type) {
+		case func(s *JournaldLog):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *JournaldLog) or func(s Logger)")
+		}
+	}
+	if s.socket == "" {
+		s.socket = defaultJournalSocket
+	}
+	if s.identifier == "" {
+		s.identifier = filepath.Base(os.Args[0])
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: s.socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	return nil
+}
+
+//Close closes the connection to journald
+func (s *JournaldLog) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+//WithFields returns a copy of the logger, sharing the same connection, that adds fields to every entry
+func (s *JournaldLog) WithFields(fields map[string]interface{}) Logger {
+	s.tracker()
+	c := *s
+	c.fields = s.mergeFields(fields)
+	return &c
+}
+
+//WithContext returns a copy of the logger carrying the context values registered with RegisterContextField
+func (s *JournaldLog) WithContext(ctx context.Context) Logger {
+	return s.WithFields(fieldsFromContext(ctx))
+}
+
+//WithError returns a copy of the logger carrying err as an error field, with a cause field when it wraps other errors
+//A nil err returns the logger itself
+func (s *JournaldLog) WithError(err error) Logger {
+	if err == nil {
+		return s
+	}
+	return s.WithFields(errorFields([]interface{}{err}))
+}
+
+//Named returns a copy of the logger, sharing the same connection, whose entries are tagged with name
+func (s *JournaldLog) Named(name string) Logger {
+	s.tracker()
+	c := *s
+	c.name = s.childName(name)
+	return &c
+}
+
+//WithGroup returns a copy of the logger whose later fields are grouped under name, e.g. WithGroup("http")
+func (s *JournaldLog) WithGroup(name string) Logger {
+	s.tracker()
+	c := *s
+	c.groups = s.childGroups(name)
+	return &c
+}
+
+//Clone returns a copy of the logger whose configuration can be changed independently, it shares the journald connection with s
+func (s *JournaldLog) Clone() Logger {
+	c := *s
+	c.detach()
+	return &c
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *JournaldLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *JournaldLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *JournaldLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *JournaldLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *JournaldLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *JournaldLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
+func (s *JournaldLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *JournaldLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *JournaldLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *JournaldLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *JournaldLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *JournaldLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *JournaldLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *JournaldLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *JournaldLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *JournaldLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *JournaldLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *JournaldLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *JournaldLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *JournaldLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *JournaldLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *JournaldLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *JournaldLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+func (s *JournaldLog) Log(level string, v ...interface{}) {
+	if !s.shouldLog(level) {
+		return
+	}
+	if s.conn == nil {
+		s.handleError(errors.New("journald is not connected, Init must be called first"))
+		return
+	}
+	fields, args := s.prepare(level, v)
+	if _, err := s.conn.Write(journalEntry(level, s.identifier, fields, args)); err != nil {
+		s.handleError(err)
+	}
+}
+
+//journalEntry builds a native protocol datagram for an entry
+//Map arguments override the logger's fields, and MESSAGE, PRIORITY and SYSLOG_IDENTIFIER always take precedence over both
+func journalEntry(level, identifier string, fields map[string]interface{}, v []interface{}) []byte {
+	obj := make(map[string]string, len(fields)+4)
+	for k, val := range flattenGroups(fields) {
+		obj[journalKey(k)] = fmt.Sprint(val)
+	}
+	for k, val := range errorFields(v) {
+		obj[journalKey(k)] = fmt.Sprint(val)
+	}
+	msg := make([]interface{}, 0, len(v))
+	for _, arg := range v {
+		if m, ok := arg.(map[string]interface{}); ok {
+			for k, val := range m {
+				obj[journalKey(k)] = fmt.Sprint(val)
+			}
+			continue
+		}
+		msg = append(msg, arg)
+	}
+	//Custom levels are sent with the nearest syslog priority, unknown levels as informational
+	sev, err := ParseLevel(level)
+	if err != nil {
+		sev = LevelInfo
+	}
+	obj["MESSAGE"] = joinArgs(msg)
+	obj["PRIORITY"] = fmt.Sprint(int(sev.standard()))
+	if identifier != "" {
+		obj["SYSLOG_IDENTIFIER"] = identifier
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	for _, k := range keys {
+		writeJournalField(&buf, k, obj[k])
+	}
+	return buf.Bytes()
+}
+
+//writeJournalField writes KEY=value, or for values containing a newline KEY, a 64 bit little endian length and the value
+func writeJournalField(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteByte('\n')
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+	buf.Write(size[:])
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+//journalKey turns a field name into a valid journal field name
+//Names are upper cased with any other character replaced by an underscore, may not start with an underscore or digit
+//and are at most 64 characters long
+func journalKey(k string) string {
+	b := []byte(strings.ToUpper(k))
+	for i, c := range b {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			b[i] = '_'
+		}
+	}
+	key := strings.TrimLeft(string(b), "_")
+	if key == "" || (key[0] >= '0' && key[0] <= '9') {
+		key = "F_" + key
+	}
+	if len(key) > 64 {
+		key = key[:64]
+	}
+	return key
+}
@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+//parseJournalEntry decodes a native protocol datagram
+func parseJournalEntry(b []byte, t *testing.T) map[string]string {
+	fields := map[string]string{}
+	for len(b) > 0 {
+		nl := bytes.IndexByte(b, '\n')
+		if nl < 0 {
+			t.Fatal("unterminated field", string(b))
+		}
+		line := b[:nl]
+		if eq := bytes.IndexByte(line, '='); eq >= 0 {
+			fields[string(line[:eq])] = string(line[eq+1:])
+			b = b[nl+1:]
+			continue
+		}
+		//Binary form, KEY\n then a little endian length, the value and a newline
+		b = b[nl+1:]
+		if len(b) < 8 {
+			t.Fatal("missing length for", string(line))
+		}
+		n := binary.LittleEndian.Uint64(b)
+		b = b[8:]
+		if uint64(len(b)) < n+1 || b[n] != '\n' {
+			t.Fatal("bad binary field", string(line))
+		}
+		fields[string(line)] = string(b[:n])
+		b = b[n+1:]
+	}
+	return fields
+}
+
+func TestJournaldLog(t *testing.T) {
+	addr := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		t.Skip("unix datagram sockets unavailable", err)
+	}
+	defer conn.Close()
+
+	jl := new(JournaldLog)
+	jl.OnInit(func(s *JournaldLog) {
+		s.SetSocket(addr)
+		s.SetIdentifier("logger-test")
+	})
+	if err := jl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer jl.Close()
+
+	read := func() map[string]string {
+		buf := make([]byte, 65536)
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parseJournalEntry(buf[:n], t)
+	}
+
+	jl.WithFields(map[string]interface{}{"user-id": 42, "2fa": true}).Warning("login failed", map[string]interface{}{"_trusted": "no"})
+	fields := read()
+	expected := map[string]string{
+		"MESSAGE":           "login failed",
+		"PRIORITY":          "4",
+		"SYSLOG_IDENTIFIER": "logger-test",
+		"USER_ID":           "42",
+		"F_2FA":             "true",
+		"TRUSTED":           "no",
+	}
+	for k, v := range expected {
+		if fields[k] != v {
+			t.Errorf("expected %s=%q, got %q", k, v, fields[k])
+		}
+	}
+
+	//Values with newlines use the length prefixed form
+	jl.WithError(errors.New("line one\nline two")).Log("custom level", "multi\nline")
+	fields = read()
+	if fields["MESSAGE"] != "multi\nline" || fields["ERROR"] != "line one\nline two" || fields["PRIORITY"] != "6" {
+		t.Error("unexpected fields", fields)
+	}
+
+	jl.Emergency("down")
+	if fields = read(); fields["PRIORITY"] != "0" {
+		t.Error("unexpected priority", fields["PRIORITY"])
+	}
+}
+
+func TestJournaldLogNotConnected(t *testing.T) {
+	var handled error
+	jl := new(JournaldLog)
+	jl.OnError(func(err error) {
+		handled = err
+	})
+	jl.Info("x")
+	if handled == nil {
+		t.Error("expected an error before Init")
+	}
+}
@@ -0,0 +1,256 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+//JSONLog writes newline delimited JSON objects with level, time and message keys
+//Arguments of type map[string]interface{} are merged into the top level object
+type JSONLog struct {
+	LogBase
+	out        io.Writer
+	pretty     bool
+	console    bool
+	isTerminal func() bool
+}
+
+//Init expects input to be a list of func(s *JSONLog), typically used to call SetOutput
+func (s *JSONLog) Init() error {
+	for _, fn := range s.initializers {
+		switch funct := fn.(type) {
+		case func(s *JSONLog):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *JSONLog) or func(s Logger)")
+		}
+	}
+	return nil
+}
+
+//SetOutput sets the destination writer, os.Stdout is used when none is set
+func (s *JSONLog) SetOutput(w io.Writer) {
+	s.out = w
+}
+
+//output returns the writer objects are written to
+func (s *JSONLog) output() io.Writer {
+	if s.out == nil {
+		return os.Stdout
+	}
+	return s.out
+}
+
+//SetPretty writes each object indented over several lines for reading during development
+//Objects are still separated by a newline so the output can be read with a json.Decoder
+//The default is compact, one object per line
+func (s *JSONLog) SetPretty(pretty bool) {
+	s.pretty = pretty
+}
+
+//WithFields returns a copy of the logger that adds fields as top level keys
+func (s *JSONLog) WithFields(fields map[string]interface{}) Logger {
+	s.tracker()
+	c := *s
+	c.fields = s.mergeFields(fields)
+	return &c
+}
+
+//WithContext returns a copy of the logger carrying the context values registered with RegisterContextField
+func (s *JSONLog) WithContext(ctx context.Context) Logger {
+	return s.WithFields(fieldsFromContext(ctx))
+}
+
+//WithError returns a copy of the logger carrying err as an error field, with a cause field when it wraps other errors
+//A nil err returns the logger itself
+func (s *JSONLog) WithError(err error) Logger {
+	if err == nil {
+		return s
+	}
+	return s.WithFields(errorFields([]interface{}{err}))
+}
+
+//Named returns a copy of the logger, sharing the same output, whose entries are tagged with name
+func (s *JSONLog) Named(name string) Logger {
+	s.tracker()
+	c := *s
+	c.name = s.childName(name)
+	return &c
+}
+
+//WithGroup returns a copy of the logger whose later fields are grouped under name, e.g. WithGroup("http")
+func (s *JSONLog) WithGroup(name string) Logger {
+	s.tracker()
+	c := *s
+	c.groups = s.childGroups(name)
+	return &c
+}
+
+//Clone returns a copy of the logger whose configuration can be changed independently, it writes to the same output as s
+func (s *JSONLog) Clone() Logger {
+	c := *s
+	c.detach()
+	return &c
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *JSONLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *JSONLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *JSONLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *JSONLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *JSONLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *JSONLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
+func (s *JSONLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *JSONLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *JSONLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *JSONLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *JSONLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *JSONLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *JSONLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *JSONLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *JSONLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *JSONLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *JSONLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *JSONLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *JSONLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *JSONLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *JSONLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *JSONLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *JSONLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+func (s *JSONLog) Log(level string, v ...interface{}) {
+	if !s.shouldLog(level) {
+		return
+	}
+	out := s.output()
+	fields, args := s.prepare(level, v)
+	if s.useConsole() {
+		if _, err := io.WriteString(out, consoleLine(level, s.levelName(level), s.now(), fields, args)+s.eol()); err != nil {
+			s.handleError(err)
+		}
+		return
+	}
+	b := jsonLine(s.levelName(level), s.now(), fields, args)
+	if s.pretty {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, b, "", "  "); err == nil {
+			b = buf.Bytes()
+		}
+	}
+	if _, err := out.Write(append(b, s.eol()...)); err != nil {
+		s.handleError(err)
+	}
+}
+
+//jsonLine builds the JSON object for a single entry
+//Map arguments override the logger's fields, and the level, time and message keys always take precedence over both
+//An error argument also adds error and cause keys
+func jsonLine(level string, t time.Time, fields map[string]interface{}, v []interface{}) []byte {
+	obj := make(map[string]interface{}, len(fields)+5)
+	for k, val := range fields {
+		obj[k] = val
+	}
+	for k, val := range errorFields(v) {
+		obj[k] = val
+	}
+	args := make([]interface{}, 0, len(v))
+	merged := false
+	for _, arg := range v {
+		if m, ok := arg.(map[string]interface{}); ok {
+			merged = true
+			for k, val := range m {
+				obj[k] = val
+			}
+			continue
+		}
+		args = append(args, arg)
+	}
+	if len(args) > 0 || !merged {
+		obj["message"] = joinArgs(args)
+	}
+	obj["level"] = level
+	obj["time"] = t.Format(time.RFC3339)
+
+	b, err := json.Marshal(obj)
+	if err != nil {
+		//Fall back to the string form of any value that can not be encoded
+		for k, val := range obj {
+			obj[k] = fmt.Sprint(val)
+		}
+		b, _ = json.Marshal(obj)
+	}
+	return b
+}
+
+//joinArgs joins the arguments with a single space, in the same way as fmt.Println
+//Errors are written with their whole chain
+func joinArgs(v []interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(errorArgs(v)...), "\n")
+}
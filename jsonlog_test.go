@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func decodeJSONLine(b []byte, t *testing.T) map[string]interface{} {
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal("invalid JSON", err, string(b))
+	}
+	return m
+}
+
+func TestJSONLog(t *testing.T) {
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.OnInit(func(s *JSONLog) {
+		s.SetOutput(&buf)
+	})
+	if err := jl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+
+	jl.Error("This is a", "message", 42)
+	line := buf.Bytes()
+	if line[len(line)-1] != '\n' {
+		t.Error("expected trailing newline", string(line))
+	}
+	m := decodeJSONLine(line, t)
+	if m["level"] != "Error" {
+		t.Error("unexpected level", m["level"])
+	}
+	if m["message"] != "This is a message 42" {
+		t.Error("unexpected message", m["message"])
+	}
+	if _, err := time.Parse(time.RFC3339, m["time"].(string)); err != nil {
+		t.Error("time is not RFC3339", m["time"])
+	}
+	if len(m) != 3 {
+		t.Error("unexpected keys", m)
+	}
+}
+
+func TestJSONLogMergesMap(t *testing.T) {
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+
+	jl.Info(map[string]interface{}{"request_id": "abc", "status": 200, "level": "ignored"})
+	m := decodeJSONLine(buf.Bytes(), t)
+	if m["request_id"] != "abc" || m["status"] != float64(200) {
+		t.Error("map keys were not merged", m)
+	}
+	if m["level"] != "Info" {
+		t.Error("level must not be overridden by map keys", m["level"])
+	}
+	if _, ok := m["message"]; ok {
+		t.Error("unexpected message key", m)
+	}
+}
+
+func TestJSONLogWithFields(t *testing.T) {
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	child := jl.WithFields(map[string]interface{}{"request_id": "abc"})
+
+	child.Warning("This is a message")
+	m := decodeJSONLine(buf.Bytes(), t)
+	if m["request_id"] != "abc" || m["message"] != "This is a message" {
+		t.Error("fields were not added as top level keys", m)
+	}
+}
+
+func TestJSONLogCaller(t *testing.T) {
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	jl.SetCaller(true)
+
+	_, _, line, _ := runtime.Caller(0)
+	jl.Info("This is a message")
+	m := decodeJSONLine(buf.Bytes(), t)
+	if m["caller"] != fmt.Sprintf("jsonlog_test.go:%d", line+1) {
+		t.Error("unexpected caller", m["caller"])
+	}
+}
+
+func TestJSONLogPretty(t *testing.T) {
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	jl.Info("compact")
+	if strings.Count(buf.String(), "\n") != 1 || strings.Contains(buf.String(), "  ") {
+		t.Error("expected compact output by default, got", buf.String())
+	}
+
+	buf.Reset()
+	jl.SetPretty(true)
+	jl.Info("first")
+	jl.WithFields(map[string]interface{}{"user": "bob"}).Error("second")
+	if !strings.Contains(buf.String(), "\n  \"message\": \"first\"") {
+		t.Error("expected indented output, got", buf.String())
+	}
+
+	//The output is a stream of valid JSON documents
+	dec := json.NewDecoder(&buf)
+	var entries []map[string]interface{}
+	for dec.More() {
+		var entry map[string]interface{}
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatal("invalid JSON", err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 || entries[0]["message"] != "first" || entries[1]["user"] != "bob" {
+		t.Error("unexpected entries", entries)
+	}
+}
+
+func TestJSONLogWriteError(t *testing.T) {
+	jl := new(JSONLog)
+	jl.SetOutput(errWriter{})
+	jl.Info("lost")
+	if jl.Err() == nil {
+		t.Error("expected the write error to be passed to the error handler")
+	}
+
+	jl = new(JSONLog)
+	jl.SetOutput(errWriter{})
+	jl.SetConsoleJSON(true)
+	jl.Info("lost")
+	if jl.Err() == nil {
+		t.Error("expected the console write error to be passed to the error handler")
+	}
+}
@@ -0,0 +1,40 @@
+package logger
+
+import "fmt"
+
+//badKey holds the value of a trailing key/value argument that has no key
+const badKey = "!BADKEY"
+
+//SetKeyValues treats the arguments after a leading string message as alternating keys and values, in the style of zap and logr
+//e.g. l.Info("request served", "status", 200, "path", "/") logs the message with status and path as structured fields
+//A dangling final value is kept under the "!BADKEY" field and keys that are not strings are converted with fmt.Sprint
+//Calls whose first argument is not a string are logged as they are
+func (l *LogBase) SetKeyValues(enabled bool) {
+	l.keyValues = enabled
+}
+
+//keyValueFields moves the key/value pairs following a string message in v into a copy of fields
+func keyValueFields(fields map[string]interface{}, v []interface{}) (map[string]interface{}, []interface{}) {
+	if len(v) < 2 {
+		return fields, v
+	}
+	if _, ok := v[0].(string); !ok {
+		return fields, v
+	}
+	merged := make(map[string]interface{}, len(fields)+len(v)/2)
+	for k, val := range fields {
+		merged[k] = val
+	}
+	pairs := v[1:]
+	for i := 0; i+1 < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			key = fmt.Sprint(pairs[i])
+		}
+		merged[key] = pairs[i+1]
+	}
+	if len(pairs)%2 == 1 {
+		merged[badKey] = pairs[len(pairs)-1]
+	}
+	return merged, v[:1]
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestKeyValuesEven(t *testing.T) {
+	ml := new(MemoryLog)
+	ml.SetKeyValues(true)
+	ml.Info("request served", "status", 200, "path", "/")
+	e, _ := ml.LastEntry()
+	if e.Message != "request served" || len(e.Args) != 1 {
+		t.Error("expected only the message to remain, got", e.Args)
+	}
+	if e.Fields["status"] != 200 || e.Fields["path"] != "/" || len(e.Fields) != 2 {
+		t.Error("unexpected fields", e.Fields)
+	}
+
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	wl.SetKeyValues(true)
+	wl.Info("request served", "status", 200, 7, "seven")
+	testOutput(buf.String(), "Info request served 7=seven status=200\n", t)
+}
+
+func TestKeyValuesOdd(t *testing.T) {
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	jl.SetKeyValues(true)
+	jl.WithFields(map[string]interface{}{"service": "api"}).Warning("slow request", "elapsed", 3, "dangling")
+	line := decodeJSONLine(buf.Bytes(), t)
+	if line["message"] != "slow request" || line["elapsed"] != 3.0 || line["!BADKEY"] != "dangling" || line["service"] != "api" {
+		t.Error("unexpected line", line)
+	}
+
+	ml := new(MemoryLog)
+	ml.SetKeyValues(true)
+	ml.Info("only message", "dangling")
+	e, _ := ml.LastEntry()
+	if e.Fields[badKey] != "dangling" || len(e.Args) != 1 {
+		t.Error("unexpected entry", e)
+	}
+}
+
+func TestKeyValuesDisabled(t *testing.T) {
+	ml := new(MemoryLog)
+	ml.Info("message", "key", "value")
+	e, _ := ml.LastEntry()
+	if len(e.Args) != 3 || len(e.Fields) != 0 {
+		t.Error("expected the arguments to be left alone by default, got", e)
+	}
+
+	//The first argument must be a message
+	ml.SetKeyValues(true)
+	ml.Info(42, "key", "value")
+	e, _ = ml.LastEntry()
+	if len(e.Args) != 3 || len(e.Fields) != 0 {
+		t.Error("expected non string messages to be left alone, got", e)
+	}
+}
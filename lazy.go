@@ -0,0 +1,24 @@
+package logger
+
+//resolveLazy replaces any func() interface{} arguments with the value they return
+//It is only called once an entry is known to be written, so closures for filtered levels never run
+//e.g. l.Debug("state", func() interface{} { return dump(state) })
+func resolveLazy(v []interface{}) []interface{} {
+	var args []interface{}
+	for i, arg := range v {
+		f, ok := arg.(func() interface{})
+		if !ok {
+			continue
+		}
+		//Copy on the first closure so that the caller's slice is left untouched
+		if args == nil {
+			args = make([]interface{}, len(v))
+			copy(args, v)
+		}
+		args[i] = f()
+	}
+	if args == nil {
+		return v
+	}
+	return args
+}
@@ -0,0 +1,61 @@
+package logger
+
+import "testing"
+
+func TestLazyArgs(t *testing.T) {
+	calls := 0
+	expensive := func() interface{} {
+		calls++
+		return "expensive"
+	}
+
+	ml := new(MemoryLog)
+	ml.SetLevel("Info")
+	ml.Debug("state", expensive)
+	if calls != 0 {
+		t.Error("expected the closure not to run for a filtered level, ran", calls)
+	}
+	if len(ml.Entries()) != 0 {
+		t.Error("expected no entries")
+	}
+
+	args := []interface{}{"state", expensive, 3}
+	ml.Info(args...)
+	if calls != 1 {
+		t.Error("expected the closure to run once, ran", calls)
+	}
+	last, _ := ml.LastEntry()
+	if len(last.Args) != 3 || last.Args[0] != "state" || last.Args[1] != "expensive" || last.Args[2] != 3 {
+		t.Error("expected the closure to be replaced by its result", last.Args)
+	}
+	if _, ok := args[1].(func() interface{}); !ok {
+		t.Error("expected the caller's arguments to be left untouched")
+	}
+
+	output := captureOutput(func() {
+		stdLog := new(StdLog)
+		stdLog.SetLevel("Warning")
+		stdLog.Info(expensive)
+		stdLog.Error("got", expensive)
+	})
+	testOutput(output, "Error [got expensive]\n", t)
+	if calls != 2 {
+		t.Error("expected the closure to run only for the written entry, ran", calls)
+	}
+}
+
+func TestLazyArgsStack(t *testing.T) {
+	calls := 0
+	expensive := func() interface{} {
+		calls++
+		return "expensive"
+	}
+	quiet := new(MemoryLog)
+	quiet.SetLevel("Error")
+	stack := new(Stack)
+	stack.Add(quiet, NewLevelLog(new(MemoryLog), "Error"))
+	stack.Info(expensive)
+	if calls != 0 {
+		t.Error("expected the closure not to run when no logger writes the entry, ran", calls)
+	}
+}
@@ -0,0 +1,104 @@
+package logger
+
+//levelFile is an extra file that receives the entries at threshold or more severe
+type levelFile struct {
+	threshold Severity
+	file      *FileLog
+}
+
+//SetLevelFile also writes entries at minLevel or more severe to the file at path, in addition to the main file
+//e.g. SetLevelFile("Error", "./error.log") keeps the errors in a file of their own
+//The file uses the main file's rotation, permission and buffer settings and rotates independently, it is opened by Init
+//or straight away if the logger is already open, setting the same path again changes its level
+func (s *FileLog) SetLevelFile(minLevel string, path string) error {
+	sev, err := ParseLevel(minLevel)
+	if err != nil {
+		return err
+	}
+	files := make([]levelFile, 0, len(s.levelFiles)+1)
+	var lf *levelFile
+	for _, f := range s.levelFiles {
+		files = append(files, f)
+		if f.file.logPath == path {
+			lf = &files[len(files)-1]
+		}
+	}
+	if lf != nil {
+		lf.threshold = sev
+		s.levelFiles = files
+		return nil
+	}
+	files = append(files, levelFile{threshold: sev, file: &FileLog{logPath: path}})
+	s.levelFiles = files
+	mu := s.lock()
+	mu.Lock()
+	open := s.f != nil
+	mu.Unlock()
+	if !open {
+		return nil
+	}
+	return s.openLevelFile(files[len(files)-1].file)
+}
+
+//openLevelFiles opens every level file that is not open yet
+func (s *FileLog) openLevelFiles() error {
+	for _, lf := range s.levelFiles {
+		if err := s.openLevelFile(lf.file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//openLevelFile copies the main file's settings to f and opens it
+func (s *FileLog) openLevelFile(f *FileLog) error {
+	mu := f.lock()
+	mu.Lock()
+	defer mu.Unlock()
+	s.inherit(f)
+	if f.f != nil {
+		return nil
+	}
+	return f.open()
+}
+
+//inherit copies the file settings of s to the level file f, f.mu must be held
+func (s *FileLog) inherit(f *FileLog) {
+	f.maxSize = s.maxSize
+	f.maxBackups = s.maxBackups
+	f.compress = s.compress
+	f.fileMode = s.fileMode
+	f.dirMode = s.dirMode
+	f.bufSize = s.bufSize
+	f.flushInterval = s.flushInterval
+}
+
+//writeLevelFiles writes a formatted entry to the level files whose threshold it meets
+//Custom levels that are not known are only written to the main file
+func (s *FileLog) writeLevelFiles(level string, line []byte) error {
+	if len(s.levelFiles) == 0 {
+		return nil
+	}
+	sev, err := ParseLevel(level)
+	if err != nil {
+		return nil
+	}
+	var werr error
+	for _, lf := range s.levelFiles {
+		if sev > lf.threshold {
+			continue
+		}
+		mu := lf.file.lock()
+		mu.Lock()
+		s.inherit(lf.file)
+		rotateErr, err := lf.file.writeLine(level, line)
+		mu.Unlock()
+		if rotateErr != nil {
+			s.handleError(rotateErr)
+		}
+		if err != nil && werr == nil {
+			werr = err
+		}
+	}
+	return werr
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestFileLogLevelFile(t *testing.T) {
+	fl := new(FileLog)
+	fl.OnInit(tlCallback)
+	var errorPath string
+	fl.OnInit(func(s *FileLog) {
+		errorPath = s.logPath + ".errors"
+		if err := s.SetLevelFile("Error", errorPath); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if err := fl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer os.Remove(fl.logPath)
+	defer os.Remove(errorPath)
+
+	fl.Info("started")
+	fl.Error("failed")
+	fl.Critical("down")
+	if err := fl.Close(); err != nil {
+		t.Fatal("Close failed", err)
+	}
+
+	b, _ := ioutil.ReadFile(fl.logPath)
+	testOutput(string(b), "Info started\nError failed\nCritical down\n", t)
+	b, _ = ioutil.ReadFile(errorPath)
+	testOutput(string(b), "Error failed\nCritical down\n", t)
+}
+
+func TestFileLogLevelFileRotation(t *testing.T) {
+	fl := new(FileLog)
+	fl.OnInit(tlCallback)
+	if err := fl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	//The level file is opened straight away as the logger is already open
+	errorPath := fl.logPath + ".errors"
+	if err := fl.SetLevelFile("Warning", errorPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := fl.SetLevelFile("loud", errorPath); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+	fl.SetMaxSize(30)
+	fl.SetMaxBackups(1)
+	defer func() {
+		for _, path := range []string{fl.logPath, errorPath} {
+			os.Remove(path)
+			os.Remove(backupPath(path, 1))
+		}
+	}()
+
+	//Info lines are 14 bytes and Error lines 15, so two lines fit in a file and each file rotates once it holds two
+	fl.Info("line", 0)
+	fl.Error("line", 1)
+	fl.Info("line", 2)
+	fl.Error("line", 3)
+	fl.Error("line", 4)
+	fl.Close()
+
+	expected := map[string]string{
+		fl.logPath:                "Error [line 4]\n",
+		backupPath(fl.logPath, 1): "Info [line 2]\nError [line 3]\n",
+		errorPath:                 "Error [line 4]\n",
+		backupPath(errorPath, 1):  "Error [line 1]\nError [line 3]\n",
+	}
+	for path, content := range expected {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		testOutput(string(b), content, t)
+	}
+}
@@ -0,0 +1,115 @@
+package logger
+
+import "fmt"
+
+//LevelLog wraps a Logger and only forwards entries at or above a minimum level
+//This lets one member of a Stack filter independently of the others
+type LevelLog struct {
+	base   LogBase
+	logger Logger
+}
+
+//NewLevelLog returns a LevelLog that forwards entries at minLevel or more severe to l
+func NewLevelLog(l Logger, minLevel string) *LevelLog {
+	s := &LevelLog{logger: l}
+	s.base.SetLevel(minLevel)
+	return s
+}
+
+//SetLevel changes the minimum level that is forwarded
+func (s *LevelLog) SetLevel(level string) {
+	s.base.SetLevel(level)
+}
+
+//Enabled reports whether level passes the minimum level and the wrapped logger would write it
+func (s *LevelLog) Enabled(level string) bool {
+	return s.base.shouldLog(level) && enabled(s.logger, level)
+}
+
+//Init initializes the wrapped logger
+func (s *LevelLog) Init() error {
+	return s.logger.Init()
+}
+
+//OnInit registers initializers on the wrapped logger
+func (s *LevelLog) OnInit(f ...interface{}) {
+	s.logger.OnInit(f...)
+}
+
+//OnInitLogger registers an initializer on the wrapped logger, it is called with the wrapped logger
+func (s *LevelLog) OnInitLogger(f func(s Logger)) {
+	s.OnInit(f)
+}
+
+func (s *LevelLog) Emergency(v ...interface{}) {
+	if s.base.shouldLog("Emergency") {
+		s.logger.Emergency(v...)
+	}
+}
+func (s *LevelLog) Alert(v ...interface{}) {
+	if s.base.shouldLog("Alert") {
+		s.logger.Alert(v...)
+	}
+}
+func (s *LevelLog) Critical(v ...interface{}) {
+	if s.base.shouldLog("Critical") {
+		s.logger.Critical(v...)
+	}
+}
+func (s *LevelLog) Error(v ...interface{}) {
+	if s.base.shouldLog("Error") {
+		s.logger.Error(v...)
+	}
+}
+func (s *LevelLog) Warning(v ...interface{}) {
+	if s.base.shouldLog("Warning") {
+		s.logger.Warning(v...)
+	}
+}
+func (s *LevelLog) Notice(v ...interface{}) {
+	if s.base.shouldLog("Notice") {
+		s.logger.Notice(v...)
+	}
+}
+func (s *LevelLog) Info(v ...interface{}) {
+	if s.base.shouldLog("Info") {
+		s.logger.Info(v...)
+	}
+}
+func (s *LevelLog) Debug(v ...interface{}) {
+	if s.base.shouldLog("Debug") {
+		s.logger.Debug(v...)
+	}
+}
+func (s *LevelLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *LevelLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *LevelLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *LevelLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *LevelLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *LevelLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *LevelLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *LevelLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *LevelLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+func (s *LevelLog) Log(level string, v ...interface{}) {
+	if s.base.shouldLog(level) {
+		s.logger.Log(level, v...)
+	}
+}
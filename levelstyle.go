@@ -0,0 +1,51 @@
+package logger
+
+import "strings"
+
+//LevelStyle controls how level names are written by the text, JSON and logfmt output
+type LevelStyle int
+
+const (
+	//TitleCase writes level names as they are logged, e.g. "Error", it is the default
+	TitleCase LevelStyle = iota
+	//Lower writes level names in lower case, e.g. "error"
+	Lower
+	//Upper writes level names in upper case, e.g. "ERROR"
+	Upper
+	//Short writes the upper case four letter abbreviation, e.g. "ERRO", custom levels are cut to four letters
+	Short
+)
+
+//shortNames holds the abbreviations used by Short, indexed by Severity
+var shortNames = []string{"EMER", "ALRT", "CRIT", "ERRO", "WARN", "NOTI", "INFO", "DEBU"}
+
+//format returns level written in the style
+func (st LevelStyle) format(level string) string {
+	switch st {
+	case Lower:
+		return strings.ToLower(level)
+	case Upper:
+		return strings.ToUpper(level)
+	case Short:
+		if sev, err := parseBuiltinLevel(level); err == nil {
+			return shortNames[sev]
+		}
+		name := []rune(strings.ToUpper(level))
+		if len(name) > 4 {
+			name = name[:4]
+		}
+		return string(name)
+	}
+	return level
+}
+
+//SetLevelStyle sets how level names are written, e.g. SetLevelStyle(Lower) writes "info" rather than "Info"
+//Only the written name changes, filtering and severities still use the level as it was logged
+func (l *LogBase) SetLevelStyle(style LevelStyle) {
+	l.levelStyle = style
+}
+
+//levelName returns level written in the logger's style
+func (l *LogBase) levelName(level string) string {
+	return l.levelStyle.format(level)
+}
@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLevelStyleFormat(t *testing.T) {
+	tests := []struct {
+		style    LevelStyle
+		level    string
+		expected string
+	}{
+		{TitleCase, "Error", "Error"},
+		{Lower, "Error", "error"},
+		{Upper, "Warning", "WARNING"},
+		{Short, "Emergency", "EMER"},
+		{Short, "Alert", "ALRT"},
+		{Short, "critical", "CRIT"},
+		{Short, "Error", "ERRO"},
+		{Short, "Warning", "WARN"},
+		{Short, "Notice", "NOTI"},
+		{Short, "Info", "INFO"},
+		{Short, "Debug", "DEBU"},
+		{Short, "custom level", "CUST"},
+		{Short, "ok", "OK"},
+	}
+	for _, tt := range tests {
+		if name := tt.style.format(tt.level); name != tt.expected {
+			t.Errorf("style %d: expected %q for %q, got %q", tt.style, tt.expected, tt.level, name)
+		}
+	}
+}
+
+func TestLevelStyleText(t *testing.T) {
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+
+	expected := map[LevelStyle]string{
+		TitleCase: "Error a k=v\n",
+		Lower:     "error a k=v\n",
+		Upper:     "ERROR a k=v\n",
+		Short:     "ERRO a k=v\n",
+	}
+	for style, line := range expected {
+		buf.Reset()
+		wl.SetLevelStyle(style)
+		wl.WithFields(map[string]interface{}{"k": "v"}).Error("a")
+		testOutput(buf.String(), line, t)
+	}
+
+	buf.Reset()
+	wl.SetLevelStyle(Short)
+	if err := wl.SetTemplate("{level}|{LEVEL} {msg}"); err != nil {
+		t.Fatal(err)
+	}
+	wl.Warning("b")
+	testOutput(buf.String(), "WARN|WARN b\n", t)
+}
+
+func TestLevelStyleJSON(t *testing.T) {
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+
+	expected := map[LevelStyle]string{TitleCase: "Info", Lower: "info", Upper: "INFO", Short: "INFO"}
+	for style, level := range expected {
+		buf.Reset()
+		jl.SetLevelStyle(style)
+		jl.Info("a")
+		if m := decodeJSONLine(buf.Bytes(), t); m["level"] != level {
+			t.Errorf("style %d: unexpected level %v", style, m["level"])
+		}
+	}
+
+	//The style also applies to the JSON formatter used with other loggers
+	buf.Reset()
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	wl.SetFormatter(JSONFormatter{})
+	wl.SetLevelStyle(Lower)
+	wl.Error("a")
+	if m := decodeJSONLine(buf.Bytes(), t); m["level"] != "error" {
+		t.Error("unexpected level", m["level"])
+	}
+}
+
+func TestLevelStyleLogfmt(t *testing.T) {
+	var buf bytes.Buffer
+	ll := new(LogfmtLog)
+	ll.SetOutput(&buf)
+	ll.SetLevelStyle(Upper)
+	ll.Notice("a")
+	testOutput(buf.String(), "level=NOTICE msg=\"a\"\n", t)
+}
+
+func TestLevelStyleFilter(t *testing.T) {
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	wl.SetLevelStyle(Short)
+	wl.SetLevel("Warning")
+	wl.Info("hidden")
+	wl.Error("shown")
+	testOutput(buf.String(), "ERRO shown\n", t)
+}
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+)
+
+//SetLineEnding sets the sequence that ends each line written, "\n" (the default) or "\r\n"
+//It applies to the line based loggers, FmtLog, StdLog, JSONLog, LogfmtLog and the formatters, built in or custom,
+//used by WriterLog, FileLog and NetLog, only the final line ending of each record is changed
+func (l *LogBase) SetLineEnding(ending string) error {
+	if ending != "\n" && ending != "\r\n" {
+		return fmt.Errorf("unsupported line ending %q, expected \"\\n\" or \"\\r\\n\"", ending)
+	}
+	l.lineEnding = ending
+	return nil
+}
+
+//eol returns the sequence ending each line
+func (l *LogBase) eol() string {
+	if l.lineEnding == "" {
+		return "\n"
+	}
+	return l.lineEnding
+}
+
+//lineEndingFormatter replaces the "\n" ending each record from f with ending
+type lineEndingFormatter struct {
+	f      Formatter
+	ending string
+}
+
+//Format implements Formatter
+func (f lineEndingFormatter) Format(level string, fields map[string]interface{}, args []interface{}) ([]byte, error) {
+	b, err := f.f.Format(level, fields, args)
+	if err != nil || !bytes.HasSuffix(b, []byte("\n")) || bytes.HasSuffix(b, []byte(f.ending)) {
+		return b, err
+	}
+	return append(b[:len(b)-1], f.ending...), nil
+}
@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestLineEndingWriterLog(t *testing.T) {
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	if err := wl.SetLineEnding("\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	wl.Info("first")
+	wl.WithFields(map[string]interface{}{"user": "bob"}).Error("second")
+	testOutput(buf.String(), "Info first\r\nError second user=bob\r\n", t)
+
+	buf.Reset()
+	wl.SetFormatter(JSONFormatter{})
+	wl.Info("first")
+	wl.Info("second")
+	if lines := bytes.Split(buf.Bytes(), []byte("\r\n")); len(lines) != 3 || len(lines[2]) != 0 {
+		t.Errorf("expected two CRLF terminated records, got %q", buf.String())
+	}
+
+	//Custom formatters are covered too
+	buf.Reset()
+	wl.SetFormatter(upperFormatter{})
+	wl.Info("first")
+	if err := wl.SetLineEnding("\n"); err != nil {
+		t.Fatal(err)
+	}
+	wl.Info("second")
+	testOutput(buf.String(), "<Info|first|<nil>>\r\n<Info|second|<nil>>\n", t)
+}
+
+func TestLineEndingFileLog(t *testing.T) {
+	fl := new(FileLog)
+	fl.OnInit(tlCallback, func(s *FileLog) {
+		s.SetLineEnding("\r\n")
+	})
+	if err := fl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer os.Remove(fl.logPath)
+	fl.Info("first")
+	fl.Warning("second")
+	fl.Close()
+
+	b, err := ioutil.ReadFile(fl.logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testOutput(string(b), "Info first\r\nWarning second\r\n", t)
+}
+
+func TestLineEndingInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	for _, ending := range []string{"", "\r", "\n\r", ";"} {
+		if err := wl.SetLineEnding(ending); err == nil {
+			t.Errorf("expected %q to be rejected", ending)
+		}
+	}
+	wl.Info("unchanged")
+	testOutput(buf.String(), "Info unchanged\n", t)
+}
+
+func TestLineEndingLineLoggers(t *testing.T) {
+	var buf bytes.Buffer
+	fmtLog := new(FmtLog)
+	fmtLog.SetOutput(&buf)
+	fmtLog.SetLineEnding("\r\n")
+	fmtLog.Info("first")
+	fmtLog.Info("second")
+	testOutput(buf.String(), "Info first\r\nInfo second\r\n", t)
+
+	buf.Reset()
+	logfmtLog := new(LogfmtLog)
+	logfmtLog.SetOutput(&buf)
+	logfmtLog.SetLineEnding("\r\n")
+	logfmtLog.Info("first")
+	testOutput(buf.String(), "level=Info msg=\"first\"\r\n", t)
+
+	buf.Reset()
+	jsonLog := new(JSONLog)
+	jsonLog.SetOutput(&buf)
+	jsonLog.SetLineEnding("\r\n")
+	jsonLog.Info("first")
+	jsonLog.Info("second")
+	if lines := bytes.Split(buf.Bytes(), []byte("\r\n")); len(lines) != 3 || len(lines[2]) != 0 {
+		t.Errorf("expected two CRLF terminated records, got %q", buf.String())
+	}
+
+	stdLog := new(StdLog)
+	stdLog.SetLineEnding("\r\n")
+	output := captureOutput(func() {
+		stdLog.Info("first")
+		stdLog.Info("second")
+	})
+	testOutput(output, "Info first\r\nInfo second\r\n", t)
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sync"
+)
+
+//lineWriter is an io.Writer that logs each line written to it at a fixed level
+//Input is split on newlines and a trailing partial line is held until the rest of it is written
+type lineWriter struct {
+	mu     sync.Mutex
+	logger Logger
+	level  string
+	buf    []byte
+}
+
+//newLineWriter returns an io.Writer that logs each line to l at level
+func newLineWriter(l Logger, level string) io.Writer {
+	return &lineWriter{logger: l, level: level}
+}
+
+//Write logs every complete line in p, it always consumes all of p
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimSuffix(w.buf[:i], []byte("\r"))
+		w.logger.Log(w.level, string(line))
+		w.buf = w.buf[i+1:]
+	}
+	//Release the consumed prefix once nothing is pending
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+//maxPipeLine is the longest line PipeFrom logs as a single entry, longer lines are logged in pieces of this size
+const maxPipeLine = 64 * 1024
+
+//pipeFrom logs each line read from r to l at level until r is exhausted, a final line without a newline is logged too
+//Only maxPipeLine bytes are buffered however long the lines are
+func pipeFrom(l Logger, level string, r io.Reader) error {
+	br := bufio.NewReaderSize(r, maxPipeLine)
+	continued := false
+	for {
+		chunk, err := br.ReadSlice('\n')
+		switch err {
+		case bufio.ErrBufferFull:
+			l.Log(level, string(chunk))
+			continued = true
+			continue
+		case nil:
+			line := bytes.TrimSuffix(bytes.TrimSuffix(chunk, []byte("\n")), []byte("\r"))
+			//The newline ending a line that was logged in pieces is not a line of its own
+			if len(line) > 0 || !continued {
+				l.Log(level, string(line))
+			}
+			continued = false
+			continue
+		}
+		if len(chunk) > 0 {
+			l.Log(level, string(chunk))
+		}
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+}
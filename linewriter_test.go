@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestWriterAdapter(t *testing.T) {
+	ml := new(MemoryLog)
+	w := ml.Writer("Warning")
+
+	//Multiple lines in one write and a line split across writes
+	w.Write([]byte("first\nsecond\r\nthi"))
+	w.Write([]byte("rd\n"))
+	w.Write([]byte("partial"))
+
+	expected := []Entry{
+		{Level: "Warning", Args: []interface{}{"first"}},
+		{Level: "Warning", Args: []interface{}{"second"}},
+		{Level: "Warning", Args: []interface{}{"third"}},
+	}
+	if !reflect.DeepEqual(levelArgs(ml.Entries()...), expected) {
+		t.Error("unexpected entries", ml.Entries())
+	}
+}
+
+func TestWriterAdapterStdlib(t *testing.T) {
+	stdLog := new(StdLog)
+	lg := log.New(stdLog.Writer("Info"), "", 0)
+	output := captureOutput(func() {
+		lg.Println("from the standard library")
+		lg.Print("line one\nline two")
+	})
+	testOutput(output, "Info from the standard library\nInfo line one\nInfo line two\n", t)
+}
+
+func TestPipeFrom(t *testing.T) {
+	ml := new(MemoryLog)
+	if err := ml.PipeFrom("Error", strings.NewReader("first\nsecond\r\n\nlast without newline")); err != nil {
+		t.Fatal(err)
+	}
+	expected := []Entry{
+		{Level: "Error", Args: []interface{}{"first"}},
+		{Level: "Error", Args: []interface{}{"second"}},
+		{Level: "Error", Args: []interface{}{""}},
+		{Level: "Error", Args: []interface{}{"last without newline"}},
+	}
+	if !reflect.DeepEqual(levelArgs(ml.Entries()...), expected) {
+		t.Error("unexpected entries", ml.Entries())
+	}
+}
+
+func TestPipeFromLongLine(t *testing.T) {
+	ml := new(MemoryLog)
+	long := strings.Repeat("x", maxPipeLine*2+10)
+	if err := ml.PipeFrom("Info", strings.NewReader("short\n"+long+"\nafter\n")); err != nil {
+		t.Fatal(err)
+	}
+	entries := ml.Entries()
+	if len(entries) != 5 {
+		t.Fatal("expected the long line in 3 pieces between the others, got", len(entries))
+	}
+	pieces := entries[1].Message + entries[2].Message + entries[3].Message
+	if entries[0].Message != "short" || pieces != long || len(entries[1].Message) != maxPipeLine || entries[4].Message != "after" {
+		t.Error("unexpected entries")
+	}
+}
+
+func TestPipeFromReadError(t *testing.T) {
+	ml := new(MemoryLog)
+	readErr := errors.New("read failed")
+	r := io.MultiReader(strings.NewReader("first\npartial"), iotest.ErrReader(readErr))
+	if err := ml.PipeFrom("Info", r); err != readErr {
+		t.Error("expected the read error, got", err)
+	}
+	if len(ml.Entries()) != 2 || ml.Entries()[1].Message != "partial" {
+		t.Error("expected the lines read before the error to be logged, got", ml.Entries())
+	}
+}
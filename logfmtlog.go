@@ -0,0 +1,235 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//LogfmtLog writes lines in logfmt, e.g. level=Info msg="user logged in" user=bob
+//Fields from WithFields and map[string]interface{} arguments are written as sorted key=value pairs
+type LogfmtLog struct {
+	LogBase
+	out io.Writer
+}
+
+//Init expects input to be a list of func(s *LogfmtLog), typically used to call SetOutput
+func (s *LogfmtLog) Init() error {
+	for _, fn := range s.initializers {
+		switch funct := fn.(type) {
+		case func(s *LogfmtLog):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *LogfmtLog) or func(s Logger)")
+		}
+	}
+	return nil
+}
+
+//SetOutput sets the destination writer, os.Stdout is used when none is set
+func (s *LogfmtLog) SetOutput(w io.Writer) {
+	s.out = w
+}
+
+//WithFields returns a copy of the logger that adds fields as key=value pairs
+func (s *LogfmtLog) WithFields(fields map[string]interface{}) Logger {
+	s.tracker()
+	c := *s
+	c.fields = s.mergeFields(fields)
+	return &c
+}
+
+//WithContext returns a copy of the logger carrying the context values registered with RegisterContextField
+func (s *LogfmtLog) WithContext(ctx context.Context) Logger {
+	return s.WithFields(fieldsFromContext(ctx))
+}
+
+//WithError returns a copy of the logger carrying err as an error field, with a cause field when it wraps other errors
+//A nil err returns the logger itself
+func (s *LogfmtLog) WithError(err error) Logger {
+	if err == nil {
+		return s
+	}
+	return s.WithFields(errorFields([]interface{}{err}))
+}
+
+//Named returns a copy of the logger, sharing the same output, whose entries are tagged with name
+func (s *LogfmtLog) Named(name string) Logger {
+	s.tracker()
+	c := *s
+	c.name = s.childName(name)
+	return &c
+}
+
+//WithGroup returns a copy of the logger whose later fields are grouped under name, e.g. WithGroup("http")
+func (s *LogfmtLog) WithGroup(name string) Logger {
+	s.tracker()
+	c := *s
+	c.groups = s.childGroups(name)
+	return &c
+}
+
+//Clone returns a copy of the logger whose configuration can be changed independently, it writes to the same output as s
+func (s *LogfmtLog) Clone() Logger {
+	c := *s
+	c.detach()
+	return &c
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *LogfmtLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *LogfmtLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *LogfmtLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *LogfmtLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *LogfmtLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *LogfmtLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
+func (s *LogfmtLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *LogfmtLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *LogfmtLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *LogfmtLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *LogfmtLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *LogfmtLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *LogfmtLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *LogfmtLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *LogfmtLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *LogfmtLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *LogfmtLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *LogfmtLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *LogfmtLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *LogfmtLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *LogfmtLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *LogfmtLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *LogfmtLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+func (s *LogfmtLog) Log(level string, v ...interface{}) {
+	if !s.shouldLog(level) {
+		return
+	}
+	out := s.out
+	if out == nil {
+		out = os.Stdout
+	}
+	fields, args := s.prepare(level, v)
+	io.WriteString(out, logfmtLine(s.levelName(level), fields, args, s.fieldOrder)+s.eol())
+}
+
+//logfmtLine renders level and msg followed by the fields and any map arguments in the field order, see SetFieldOrder
+func logfmtLine(level string, fields map[string]interface{}, v []interface{}, order []string) string {
+	merged := make(map[string]interface{}, len(fields)+2)
+	for k, val := range flattenGroups(fields) {
+		merged[k] = val
+	}
+	for k, val := range errorFields(v) {
+		merged[k] = val
+	}
+	args := make([]interface{}, 0, len(v))
+	for _, arg := range v {
+		if m, ok := arg.(map[string]interface{}); ok {
+			for k, val := range m {
+				merged[k] = val
+			}
+			continue
+		}
+		args = append(args, arg)
+	}
+
+	var b strings.Builder
+	b.WriteString("level=" + logfmtValue(level))
+	b.WriteString(" msg=" + strconv.Quote(joinArgs(args)))
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		if k == "level" || k == "msg" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	orderKeys(keys, order)
+	for _, k := range keys {
+		b.WriteString(" " + k + "=" + logfmtValue(merged[k]))
+	}
+	return b.String()
+}
+
+//logfmtValue renders a single value, quoting it when it is empty, contains spaces, '=', quotes
+//or control characters, or is not a plain string, number or bool
+func logfmtValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		if val == "" || strings.IndexFunc(val, needsQuote) >= 0 {
+			return strconv.Quote(val)
+		}
+		return val
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(val)
+	default:
+		return strconv.Quote(fmt.Sprintf("%v", val))
+	}
+}
+
+func needsQuote(r rune) bool {
+	return r <= ' ' || r == '=' || r == '"' || r == 0x7f
+}
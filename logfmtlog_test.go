@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLogfmtLog(t *testing.T) {
+	var buf bytes.Buffer
+	ll := new(LogfmtLog)
+	ll.OnInit(func(s *LogfmtLog) {
+		s.SetOutput(&buf)
+	})
+	if err := ll.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+
+	child := ll.WithFields(map[string]interface{}{"user": "bob", "attempt": 3})
+	child.Info("user logged in")
+	testOutput(buf.String(), "level=Info msg=\"user logged in\" attempt=3 user=bob\n", t)
+}
+
+func TestLogfmtQuoting(t *testing.T) {
+	var buf bytes.Buffer
+	ll := new(LogfmtLog)
+	ll.SetOutput(&buf)
+
+	ll.Warning("This is a message", map[string]interface{}{
+		"spaces":  "two words",
+		"equals":  "a=b",
+		"quotes":  `say "hi"`,
+		"newline": "line1\nline2",
+		"empty":   "",
+		"plain":   "word",
+		"ok":      true,
+		"odd":     struct{ A int }{1},
+		"nil":     nil,
+	})
+	expected := `level=Warning msg="This is a message" empty="" equals="a=b" newline="line1\nline2" nil="<nil>" odd="{1}" ok=true plain=word quotes="say \"hi\"" spaces="two words"` + "\n"
+	testOutput(buf.String(), expected, t)
+}
@@ -1,15 +1,21 @@
 package logger
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
 )
 
 // Logger exposes eight methods to write logs to the eight RFC 5424 levels
 // (debug, info, notice, warning, error, critical, alert, emergency)
 // It additionally exposes a Log endpoint that takes the level as a string
+// Arguments of type func() interface{} are only called, and replaced by their result, if the entry is written
 type Logger interface {
 
 	//Init should be run before the logger is used.
@@ -19,6 +25,7 @@ type Logger interface {
 	Init() error
 
 	//Pass in functions that can be called on init
+	//Each must be a func(s Logger) or take the concrete logger type, e.g. func(s *FileLog)
 	OnInit(f ...interface{})
 
 	//Log - Generic logging endpoint that can take a string for level, and the data to output
@@ -45,32 +52,446 @@ type Logger interface {
 	Debug(v ...interface{})
 }
 
+//FieldLogger is implemented by loggers that can carry structured fields
+type FieldLogger interface {
+	Logger
+	//WithFields returns a child logger that adds fields to every line it writes
+	//Fields set by later calls override earlier ones with the same key
+	WithFields(fields map[string]interface{}) Logger
+}
+
+//FormatLogger is implemented by loggers that also offer Printf style methods
+//Each method formats its arguments with fmt.Sprintf and logs the result at the matching level
+type FormatLogger interface {
+	Logger
+	Logf(level string, format string, args ...interface{})
+	Emergencyf(format string, args ...interface{})
+	Alertf(format string, args ...interface{})
+	Criticalf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	Warningf(format string, args ...interface{})
+	Noticef(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Debugf(format string, args ...interface{})
+}
+
+//NamedLogger is implemented by loggers that can tag their entries with a component name
+type NamedLogger interface {
+	Logger
+	//Named returns a child logger whose entries are tagged with name
+	//Calling Named on a named logger joins the names with a dot, e.g. "app.auth"
+	Named(name string) Logger
+}
+
+//GroupLogger is implemented by loggers that can namespace their fields
+type GroupLogger interface {
+	Logger
+	//WithGroup returns a child logger whose later fields are grouped under name
+	//Text output writes grouped keys with dots, e.g. "http.method", and JSON output nests them in an object
+	WithGroup(name string) Logger
+}
+
+//ErrorLogger is implemented by loggers that can report whether a write failed
+type ErrorLogger interface {
+	Logger
+	//TryLog logs like Log and returns any error hit while writing
+	TryLog(level string, v ...interface{}) error
+}
+
+//EnabledLogger is implemented by loggers that can report whether a level would be written
+//It lets callers skip building expensive messages, e.g. if l.Enabled("Debug") { l.Debug(dump()) }
+type EnabledLogger interface {
+	Logger
+	//Enabled reports whether an entry at level would be written given the configured threshold
+	Enabled(level string) bool
+}
+
+//enabled reports whether l would write an entry at level, loggers that can not tell are assumed to
+func enabled(l Logger, level string) bool {
+	if el, ok := l.(EnabledLogger); ok {
+		return el.Enabled(level)
+	}
+	return true
+}
+
+//FlushCloser is implemented by loggers that hold resources such as files, connections or buffers
+type FlushCloser interface {
+	//Flush writes out anything that has been logged but not yet written
+	Flush() error
+	//Close flushes and then releases the logger's resources
+	Close() error
+}
+
 //LogBase is a generic base that can be used to ease registration of initializers via the generic OnInit function
 type LogBase struct {
 	initializers []interface{}
+	fields       map[string]interface{}
+	caller       bool
+	callerFunc   CallerFunc
+	name         string
+	hooks        []Hook
+	template     *lineTemplate
+
+	//level is the threshold and levelSet is 1 when there is one, both are read and written atomically
+	//level is stored before levelSet so a reader that sees levelSet also sees its threshold
+	level    int32
+	levelSet int32
+
+	stacktrace     bool
+	stackThreshold Severity
+	exit           func(int)
+	maxMessageLen  int
+	keyValues      bool
+	interpolate    bool
+	clock          Clock
+	levelStyle     LevelStyle
+	lineEnding     string
+	fieldOrder     []string
+	host           string
+	pid            int
+	//groups is the path of WithGroup names that new fields are added under
+	groups []string
+
+	redactKeys    map[string]bool
+	redactPattern *regexp.Regexp
+
+	//err holds an errorBox and onError an errorHandler, they are atomic because loggers report errors from many goroutines
+	err     atomic.Value
+	onError atomic.Value
+
+	//seen holds the *severityTracker recording the most severe level logged, it is shared with copies made by WithFields and Named
+	seen atomic.Value
+}
+
+//entryFields returns the fields to write with an entry at level, adding the logger name, host, pid, caller location and function
+//and stack trace when set
+func (l *LogBase) entryFields(level string) map[string]interface{} {
+	withStack := l.wantsStack(level)
+	if !l.caller && l.callerFunc == NoFunc && l.name == "" && l.host == "" && l.pid == 0 && !withStack {
+		return l.fields
+	}
+	extra := make(map[string]interface{}, 6)
+	if l.name != "" {
+		extra["logger"] = l.name
+	}
+	if l.host != "" {
+		extra["host"] = l.host
+	}
+	if l.pid != 0 {
+		extra["pid"] = l.pid
+	}
+	if l.caller || l.callerFunc != NoFunc {
+		l.callerFields(extra)
+	}
+	if withStack {
+		extra["stacktrace"] = stacktrace()
+	}
+	return addFields(l.fields, extra)
+}
+
+//SetPrefix tags every entry with prefix, written as "[prefix] " at the start of text lines
+//and as a "logger" field by the structured loggers
+func (l *LogBase) SetPrefix(prefix string) {
+	l.name = prefix
+}
+
+//childName returns the name for a logger created with Named
+func (l *LogBase) childName(name string) string {
+	if l.name == "" {
+		return name
+	}
+	return l.name + "." + name
+}
+
+//prepare returns the fields and arguments for an entry with lazy arguments evaluated, context maps interpolated and key/value arguments moved into the fields,
+//redaction and truncation applied, and runs the hooks
+//Every logger passes its entries through here before anything is formatted or written
+func (l *LogBase) prepare(level string, v []interface{}) (map[string]interface{}, []interface{}) {
+	l.tracker().record(level)
+	fields, args := l.prepareEntry(level, v)
+	l.runHooks(level, fields, args)
+	return fields, args
+}
+
+//prepareEntry is prepare without recording the level or running the hooks
+func (l *LogBase) prepareEntry(level string, v []interface{}) (map[string]interface{}, []interface{}) {
+	fields, args := l.entryFields(level), resolveLazy(v)
+	if l.interpolate {
+		//The context is redacted first so that the value of a redacted key never reaches the message
+		fields, args = interpolateFields(fields, l.redactMaps(args))
+	}
+	if l.keyValues {
+		fields, args = keyValueFields(fields, args)
+	}
+	return l.redactFields(fields), truncateArgs(l.redactArgs(args), l.maxMessageLen)
+}
+
+//text renders a line for the text loggers without its line ending
+func (l *LogBase) text(level string, v []interface{}) string {
+	fields, args := l.prepare(level, v)
+	return l.textEntry(level, fields, args)
+}
+
+//textEntry renders fields and args with the logger's TextFormatter without the line ending
+func (l *LogBase) textEntry(level string, fields map[string]interface{}, args []interface{}) string {
+	b, _ := l.textFormatter().Format(level, fields, args)
+	return strings.TrimSuffix(string(b), "\n")
+}
+
+//mergeFields returns a copy of the logger's fields with fields added on top, under the logger's group when it has one
+func (l *LogBase) mergeFields(fields map[string]interface{}) map[string]interface{} {
+	return addFields(l.fields, l.groupFields(fields))
+}
+
+//addFields returns a copy of base with fields added on top, groups present in both are merged
+func addFields(base, fields map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(fields))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		if g, ok := v.(fieldGroup); ok {
+			if existing, ok := merged[k].(fieldGroup); ok {
+				v = fieldGroup(addFields(existing, g))
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+//errorBox wraps the last error so that every value stored in LogBase.err has the same type
+type errorBox struct {
+	err error
+}
+
+//errorHandler wraps the OnError handler so that every value stored in LogBase.onError has the same type
+type errorHandler struct {
+	f func(error)
+}
+
+//OnError sets a handler that is called whenever the logger fails to write
+//The handler may be called from several goroutines at once when the logger is shared
+func (l *LogBase) OnError(f func(error)) {
+	l.onError.Store(errorHandler{f})
+}
+
+//Err returns the last error encountered while writing, or nil
+func (l *LogBase) Err() error {
+	box, _ := l.err.Load().(errorBox)
+	return box.err
+}
+
+//handleError records err and passes it to the OnError handler if one is set
+//It is safe to call from several goroutines at once
+func (l *LogBase) handleError(err error) {
+	l.err.Store(errorBox{err})
+	if h, _ := l.onError.Load().(errorHandler); h.f != nil {
+		h.f(err)
+	}
+}
+
+//SetLevel sets the minimum severity that will be written, e.g. "Warning" will suppress Notice, Info and Debug
+//Level names are case insensitive, an empty or unknown level removes the threshold
+//It is safe to call while other goroutines are logging, e.g. from an admin endpoint
+func (l *LogBase) SetLevel(level string) {
+	sev, err := ParseLevel(level)
+	if err != nil {
+		atomic.StoreInt32(&l.levelSet, 0)
+		return
+	}
+	l.SetLevelAtomic(sev)
+}
+
+//SetLevelAtomic sets the minimum severity that will be written without parsing a level name
+//The threshold is stored atomically so changing it while other goroutines log is race free and logging takes no lock to read it
+func (l *LogBase) SetLevelAtomic(sev Severity) {
+	atomic.StoreInt32(&l.level, int32(sev))
+	atomic.StoreInt32(&l.levelSet, 1)
+}
+
+//GetLevel returns the threshold set with SetLevel or SetLevelAtomic, ok is false when every level is written
+func (l *LogBase) GetLevel() (sev Severity, ok bool) {
+	if atomic.LoadInt32(&l.levelSet) == 0 {
+		return 0, false
+	}
+	return Severity(atomic.LoadInt32(&l.level)), true
+}
+
+//Enabled reports whether an entry at level passes the threshold set with SetLevel
+func (l *LogBase) Enabled(level string) bool {
+	return l.shouldLog(level)
+}
+
+//shouldLog reports whether a message at level passes the configured threshold
+//Levels that are not known RFC 5424 names are always emitted so custom levels are not dropped
+func (l *LogBase) shouldLog(level string) bool {
+	threshold, ok := l.GetLevel()
+	if !ok {
+		return true
+	}
+	sev, err := ParseLevel(level)
+	if err != nil {
+		return true
+	}
+	return sev <= threshold
 }
 
 //OnInit adds initializers to the initializers array
+//Repeated calls accumulate, the initializers run in the order they were added
 func (l *LogBase) OnInit(f ...interface{}) {
-	l.initializers = make([]interface{}, 0)
 	l.initializers = append(l.initializers, f...)
 }
 
+//OnInitLogger adds an initializer that every logger type accepts, unlike OnInit callbacks which take the concrete type
+//e.g. the same func(s Logger) can be registered on a FileLog, a Stack and an AsyncLog
+func (l *LogBase) OnInitLogger(f func(s Logger)) {
+	l.initializers = append(l.initializers, f)
+}
+
+//ClearInit removes every initializer added with OnInit
+func (l *LogBase) ClearInit() {
+	l.initializers = nil
+}
+
 //Log to fmt
 type FmtLog struct {
 	LogBase
+	timeFormat string
+	color      bool
+	isTerminal func() bool
+	out        io.Writer
+}
+
+//SetOutput sets where lines are written, os.Stdout is used when none is set
+//e.g. SetOutput(os.Stderr)
+func (s *FmtLog) SetOutput(w io.Writer) {
+	s.out = w
+}
+
+//output returns the writer lines are written to
+func (s *FmtLog) output() io.Writer {
+	if s.out == nil {
+		return os.Stdout
+	}
+	return s.out
+}
+
+//SetColor colours the level name by severity when the output is a terminal
+//Colours are left out when the output is piped, redirected to a file or is not an *os.File
+func (s *FmtLog) SetColor(enabled bool) {
+	s.color = enabled
+}
+
+//useColor reports whether the level name should be coloured
+func (s *FmtLog) useColor() bool {
+	if !s.color {
+		return false
+	}
+	if s.isTerminal != nil {
+		return s.isTerminal()
+	}
+	f, ok := s.output().(*os.File)
+	return ok && isTerminal(f)
+}
+
+//SetTimeFormat prefixes each line with the current time formatted with layout
+//An empty layout (the default) disables the timestamp
+func (s *FmtLog) SetTimeFormat(layout string) {
+	s.timeFormat = layout
 }
 
 func (s *FmtLog) Init() error {
 	for _, fn := range s.initializers {
-		funct, ok := fn.(func(s *FmtLog))
-		if !ok {
-			return errors.New("Init callbacks must have signature func(s Logger)")
+		switch funct := fn.(type) {
+		case func(s *FmtLog):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *FmtLog) or func(s Logger)")
 		}
-		funct(s)
 	}
 	return nil
 }
+
+//WithFields returns a copy of the logger that appends fields as key=value pairs
+func (s *FmtLog) WithFields(fields map[string]interface{}) Logger {
+	s.tracker()
+	c := *s
+	c.fields = s.mergeFields(fields)
+	return &c
+}
+
+//WithContext returns a copy of the logger carrying the context values registered with RegisterContextField
+func (s *FmtLog) WithContext(ctx context.Context) Logger {
+	return s.WithFields(fieldsFromContext(ctx))
+}
+
+//WithError returns a copy of the logger carrying err as an error field, with a cause field when it wraps other errors
+//A nil err returns the logger itself
+func (s *FmtLog) WithError(err error) Logger {
+	if err == nil {
+		return s
+	}
+	return s.WithFields(errorFields([]interface{}{err}))
+}
+
+//Named returns a copy of the logger, sharing the same output, whose entries are tagged with name
+func (s *FmtLog) Named(name string) Logger {
+	s.tracker()
+	c := *s
+	c.name = s.childName(name)
+	return &c
+}
+
+//WithGroup returns a copy of the logger whose later fields are grouped under name, e.g. WithGroup("http")
+func (s *FmtLog) WithGroup(name string) Logger {
+	s.tracker()
+	c := *s
+	c.groups = s.childGroups(name)
+	return &c
+}
+
+//Clone returns a copy of the logger whose configuration can be changed independently, it writes to the same output as s
+func (s *FmtLog) Clone() Logger {
+	c := *s
+	c.detach()
+	return &c
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *FmtLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *FmtLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *FmtLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *FmtLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *FmtLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *FmtLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
 func (s *FmtLog) Emergency(v ...interface{}) {
 	s.Log("Emergency", v...)
 }
@@ -95,25 +516,180 @@ func (s *FmtLog) Info(v ...interface{}) {
 func (s *FmtLog) Debug(v ...interface{}) {
 	s.Log("Debug", v...)
 }
+func (s *FmtLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *FmtLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *FmtLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *FmtLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *FmtLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *FmtLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *FmtLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *FmtLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *FmtLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
 func (s *FmtLog) Log(level string, v ...interface{}) {
-	fmt.Println(level, v)
+	if !s.shouldLog(level) {
+		return
+	}
+	fields, args := s.prepare(level, v)
+	formatter := s.textFormatter()
+	formatter.color = s.useColor()
+	b, _ := formatter.Format(level, fields, args)
+	line := strings.TrimSuffix(string(b), "\n")
+	if s.timeFormat != "" {
+		line = s.now().Format(s.timeFormat) + " " + line
+	}
+	if _, err := io.WriteString(s.output(), line+s.eol()); err != nil {
+		s.handleError(err)
+	}
 }
 
 //Log to Log
 type StdLog struct {
 	LogBase
+	l *log.Logger
+}
+
+//globalWriter writes to the current output of the log package, so StdLog follows log.SetOutput
+type globalWriter struct{}
+
+func (globalWriter) Write(p []byte) (int, error) {
+	return log.Writer().Write(p)
+}
+
+//defaultStdLogger is used by StdLogs that have not set their own flags or prefix
+var defaultStdLogger = log.New(globalWriter{}, "", 0)
+
+//SetFlags sets the log package flags, e.g. log.LstdFlags, for this logger only, no flags are set by default
+func (s *StdLog) SetFlags(flag int) {
+	s.logger().SetFlags(flag)
+}
+
+//SetLogPrefix sets the log package prefix for this logger only, written before the flags or after them with log.Lmsgprefix
+//It is separate from SetPrefix, which tags entries with a logger name
+func (s *StdLog) SetLogPrefix(prefix string) {
+	s.logger().SetPrefix(prefix)
+}
+
+//logger returns the logger owned by s, creating it if needed
+func (s *StdLog) logger() *log.Logger {
+	if s.l == nil {
+		s.l = log.New(globalWriter{}, "", 0)
+	}
+	return s.l
 }
 
 func (s *StdLog) Init() error {
 	for _, fn := range s.initializers {
-		funct, ok := fn.(func(s *StdLog))
-		if !ok {
-			return errors.New("Init callbacks must have signature func(s Logger)")
+		switch funct := fn.(type) {
+		case func(s *StdLog):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *StdLog) or func(s Logger)")
 		}
-		funct(s)
 	}
 	return nil
 }
+
+//WithFields returns a copy of the logger that appends fields as key=value pairs
+func (s *StdLog) WithFields(fields map[string]interface{}) Logger {
+	s.tracker()
+	c := *s
+	c.fields = s.mergeFields(fields)
+	return &c
+}
+
+//WithContext returns a copy of the logger carrying the context values registered with RegisterContextField
+func (s *StdLog) WithContext(ctx context.Context) Logger {
+	return s.WithFields(fieldsFromContext(ctx))
+}
+
+//WithError returns a copy of the logger carrying err as an error field, with a cause field when it wraps other errors
+//A nil err returns the logger itself
+func (s *StdLog) WithError(err error) Logger {
+	if err == nil {
+		return s
+	}
+	return s.WithFields(errorFields([]interface{}{err}))
+}
+
+//Named returns a copy of the logger, sharing the same output, whose entries are tagged with name
+func (s *StdLog) Named(name string) Logger {
+	s.tracker()
+	c := *s
+	c.name = s.childName(name)
+	return &c
+}
+
+//WithGroup returns a copy of the logger whose later fields are grouped under name, e.g. WithGroup("http")
+func (s *StdLog) WithGroup(name string) Logger {
+	s.tracker()
+	c := *s
+	c.groups = s.childGroups(name)
+	return &c
+}
+
+//Clone returns a copy of the logger whose configuration, including the flags and log prefix, can be changed independently
+func (s *StdLog) Clone() Logger {
+	c := *s
+	c.detach()
+	//The log.Logger holds the flags and prefix, so the clone needs its own
+	if s.l != nil {
+		c.l = log.New(s.l.Writer(), s.l.Prefix(), s.l.Flags())
+	}
+	return &c
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *StdLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *StdLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *StdLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *StdLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *StdLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *StdLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
 func (s *StdLog) Emergency(v ...interface{}) {
 	s.Log("Emergency", v...)
 }
@@ -138,63 +714,40 @@ func (s *StdLog) Info(v ...interface{}) {
 func (s *StdLog) Debug(v ...interface{}) {
 	s.Log("Debug", v...)
 }
-func (s *StdLog) Log(level string, v ...interface{}) {
-	log.Println(level, v)
+func (s *StdLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
 }
-
-//Log to File
-type FileLog struct {
-	LogBase
-	f       *os.File
-	logPath string
-}
-
-//Init expects the first item passed in to be the log file location.
-//If it does not exist ./owtorg-logger will be used
-func (s *FileLog) Init() error {
-	//Set arbitrary log path, which could be overridden by initializers
-	s.logPath = "./owtorg-logger"
-	for _, v := range s.initializers {
-		funct, ok := v.(func(s *FileLog))
-		if !ok {
-			return errors.New("Init callbacks must have signature func(s *FileLog)")
-		}
-		funct(s)
-	}
-	return nil
+func (s *StdLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
 }
-func (s *FileLog) Emergency(v ...interface{}) {
-	s.Log("Emergency", v...)
+func (s *StdLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
 }
-func (s *FileLog) Alert(v ...interface{}) {
-	s.Log("Alert", v...)
+func (s *StdLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
 }
-func (s *FileLog) Critical(v ...interface{}) {
-	s.Log("Critical", v...)
+func (s *StdLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
 }
-func (s *FileLog) Error(v ...interface{}) {
-	s.Log("Error", v...)
+func (s *StdLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
 }
-func (s *FileLog) Warning(v ...interface{}) {
-	s.Log("Warning", v...)
+func (s *StdLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
 }
-func (s *FileLog) Notice(v ...interface{}) {
-	s.Log("Notice", v...)
+func (s *StdLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
 }
-func (s *FileLog) Info(v ...interface{}) {
-	s.Log("Info", v...)
+func (s *StdLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
 }
-func (s *FileLog) Debug(v ...interface{}) {
-	s.Log("Debug", v...)
-}
-func (s *FileLog) Log(level string, v ...interface{}) {
-	f, err := os.OpenFile(s.logPath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
-	if err != nil {
-		panic(0)
+func (s *StdLog) Log(level string, v ...interface{}) {
+	if !s.shouldLog(level) {
+		return
 	}
-	s.f = f
-	defer s.f.Close()
-
-	log.SetOutput(s.f)
-	log.Println(level, v)
+	l := s.l
+	if l == nil {
+		l = defaultStdLogger
+	}
+	l.Print(s.text(level, v) + s.eol())
 }
@@ -2,34 +2,345 @@ package logger
 
 import (
 	"bytes"
+	"compress/gzip"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
 
-//func TestFmtLog(t *testing.T) {
-//	fmtLog := new(FmtLog)
-//	testLogLevels(fmtLog, t)
-//}
+func TestFmtLog(t *testing.T) {
+	fmtLog := new(FmtLog)
+	//Write through to whatever the log package writes to so that captureOutput sees it
+	fmtLog.SetOutput(globalWriter{})
+	testLogLevels(fmtLog, t)
+}
+
+func TestFmtLogSetOutput(t *testing.T) {
+	var buf bytes.Buffer
+	fmtLog := new(FmtLog)
+	fmtLog.SetOutput(&buf)
+	output := captureStdout(func() {
+		fmtLog.Info("This is a message")
+		fmtLog.Log("custom level", "This is a message")
+	})
+	testOutput(output, "", t)
+	testOutput(buf.String(), "Info This is a message\ncustom level This is a message\n", t)
+}
 
 func TestStdLog(t *testing.T) {
 	stdLog := new(StdLog)
 	output := captureOutput(func() {
 		stdLog.Log("Emergency", "This is a Log message")
 	})
-	testOutput(output, "Emergency [This is a Log message]\n", t)
+	testOutput(output, "Emergency This is a Log message\n", t)
 
 	output = captureOutput(func() {
 		stdLog.Log("Arbitrary", "This is a generic message")
 	})
-	testOutput(output, "Arbitrary [This is a generic message]\n", t)
+	testOutput(output, "Arbitrary This is a generic message\n", t)
 
 	testLogLevels(stdLog, t)
 }
 
+func TestStdLogFlags(t *testing.T) {
+	stamped, plain := new(StdLog), new(StdLog)
+	stamped.SetFlags(log.LstdFlags)
+	stamped.SetLogPrefix("app: ")
+
+	output := captureOutput(func() {
+		stamped.Info("This is a message")
+	})
+	//e.g. "app: 2009/11/10 23:00:00 Info This is a message"
+	if !strings.HasPrefix(output, "app: ") || !strings.HasSuffix(output, " Info This is a message\n") ||
+		len(output) != len("app: 2009/11/10 23:00:00 Info This is a message\n") {
+		t.Error("expected a prefixed and timestamped line, got", output)
+	}
+
+	//Neither the other logger nor the global logger are affected
+	output = captureOutput(func() {
+		plain.Info("This is a message")
+		plain.WithFields(map[string]interface{}{"k": "v"}).Info("This is a message")
+	})
+	testOutput(output, "Info This is a message\nInfo This is a message k=v\n", t)
+	if log.Flags() != log.LstdFlags || log.Prefix() != "" {
+		t.Error("the global logger configuration was changed")
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	stdLog := new(StdLog)
+	stdLog.SetLevel("Warning")
+
+	output := captureOutput(func() {
+		stdLog.Debug("This is a message")
+		stdLog.Info("This is a message")
+		stdLog.Notice("This is a message")
+		stdLog.Log("Debug", "This is a message")
+	})
+	testOutput(output, "", t)
+
+	output = captureOutput(func() {
+		stdLog.Error("This is a message")
+	})
+	testOutput(output, "Error This is a message\n", t)
+
+	output = captureOutput(func() {
+		stdLog.Warning("This is a message")
+	})
+	testOutput(output, "Warning This is a message\n", t)
+
+	//Unknown levels are never filtered
+	output = captureOutput(func() {
+		stdLog.Log("custom level", "This is a message")
+	})
+	testOutput(output, "custom level This is a message\n", t)
+}
+
+func TestOnInitAccumulates(t *testing.T) {
+	var calls []string
+	ml := new(MemoryLog)
+	ml.OnInit(func(s *MemoryLog) {
+		calls = append(calls, "f1")
+	})
+	ml.OnInit(func(s *MemoryLog) {
+		calls = append(calls, "f2")
+	})
+	if err := ml.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(calls, ",") != "f1,f2" {
+		t.Error("expected both initializers to run in order, got", calls)
+	}
+
+	calls = nil
+	ml.ClearInit()
+	if err := ml.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 0 {
+		t.Error("expected no initializers after ClearInit, got", calls)
+	}
+}
+
+func TestFmtLogTimeFormat(t *testing.T) {
+	fmtLog := new(FmtLog)
+	output := captureStdout(func() {
+		fmtLog.Info("This is a message")
+	})
+	testOutput(output, "Info This is a message\n", t)
+
+	fmtLog.SetTimeFormat(time.RFC3339)
+	fmtLog.SetClock(newFakeClock(time.Date(2017, 6, 29, 12, 30, 0, 0, time.UTC)))
+	output = captureStdout(func() {
+		fmtLog.Info("This is a message")
+	})
+	testOutput(output, "2017-06-29T12:30:00Z Info This is a message\n", t)
+}
+
+func TestFmtLogColor(t *testing.T) {
+	fmtLog := new(FmtLog)
+	fmtLog.SetColor(true)
+
+	//Forced on, regardless of the pipe used to capture stdout
+	fmtLog.isTerminal = func() bool { return true }
+	output := captureStdout(func() {
+		fmtLog.Error("This is a message")
+		fmtLog.Warning("This is a message")
+		fmtLog.Log("custom level", "This is a message")
+	})
+	testOutput(output, "\x1b[31mError\x1b[0m This is a message\n\x1b[33mWarning\x1b[0m This is a message\ncustom level This is a message\n", t)
+
+	//Forced off
+	fmtLog.isTerminal = func() bool { return false }
+	output = captureStdout(func() {
+		fmtLog.Error("This is a message")
+	})
+	testOutput(output, "Error This is a message\n", t)
+
+	//Detected, the capture pipe is not a terminal
+	fmtLog.isTerminal = nil
+	output = captureStdout(func() {
+		fmtLog.Error("This is a message")
+	})
+	if strings.Contains(output, "\x1b[") {
+		t.Error("unexpected escape codes when piped", output)
+	}
+
+	//Disabled
+	fmtLog.SetColor(false)
+	fmtLog.isTerminal = func() bool { return true }
+	output = captureStdout(func() {
+		fmtLog.Error("This is a message")
+	})
+	testOutput(output, "Error This is a message\n", t)
+}
+
+func TestWithFields(t *testing.T) {
+	parent := new(StdLog)
+	child := parent.WithFields(map[string]interface{}{"request_id": "abc", "user": "bob"})
+	grandchild := child.(FieldLogger).WithFields(map[string]interface{}{"user": "alice"})
+
+	output := captureOutput(func() {
+		child.Emergency("This is a message")
+		child.Alert("This is a message")
+		child.Critical("This is a message")
+		child.Error("This is a message")
+		child.Warning("This is a message")
+		child.Notice("This is a message")
+		child.Info("This is a message")
+		child.Debug("This is a message")
+	})
+	expected := ""
+	for _, level := range []string{"Emergency", "Alert", "Critical", "Error", "Warning", "Notice", "Info", "Debug"} {
+		expected += level + " This is a message request_id=abc user=bob\n"
+	}
+	testOutput(output, expected, t)
+
+	output = captureOutput(func() {
+		grandchild.Info("This is a message")
+	})
+	testOutput(output, "Info This is a message request_id=abc user=alice\n", t)
+
+	//The parent is unchanged
+	output = captureOutput(func() {
+		parent.Info("This is a message")
+	})
+	testOutput(output, "Info This is a message\n", t)
+}
+
+func TestSetCaller(t *testing.T) {
+	stdLog := new(StdLog)
+	stdLog.SetCaller(true)
+
+	var line int
+	output := captureOutput(func() {
+		_, _, line, _ = runtime.Caller(0)
+		stdLog.Info("This is a message")
+	})
+	testOutput(output, fmt.Sprintf("logger_test.go:%d Info This is a message\n", line+1), t)
+
+	//The generic Log endpoint and a stack in between must report the same call site
+	stack := new(Stack)
+	stack.Add(stdLog)
+	output = captureOutput(func() {
+		_, _, line, _ = runtime.Caller(0)
+		stack.Log("Error", "This is a message")
+	})
+	testOutput(output, fmt.Sprintf("logger_test.go:%d Error This is a message\n", line+1), t)
+}
+
+func TestSetCallerFunc(t *testing.T) {
+	pc, _, _, _ := runtime.Caller(0)
+	full := runtime.FuncForPC(pc).Name()
+	short := full[strings.LastIndex(full, "/")+1:]
+	if !strings.HasSuffix(short, ".TestSetCallerFunc") || strings.Contains(short, "/") {
+		t.Fatal("unexpected short name", short)
+	}
+
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	wl.SetCallerFunc(ShortFunc)
+	wl.Info("This is a message")
+	testOutput(buf.String(), "("+short+") Info This is a message\n", t)
+
+	buf.Reset()
+	wl.SetCaller(true)
+	_, _, line, _ := runtime.Caller(0)
+	wl.Info("This is a message")
+	testOutput(buf.String(), fmt.Sprintf("logger_test.go:%d (%s) Info This is a message\n", line+1, short), t)
+
+	buf.Reset()
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	jl.SetCallerFunc(FullFunc)
+	jl.Info("This is a message")
+	if entry := decodeJSONLine(buf.Bytes(), t); entry["func"] != full || entry["caller"] != nil {
+		t.Error("expected the full function name without the caller, got", entry)
+	}
+
+	ml := new(MemoryLog)
+	ml.SetCallerFunc(ShortFunc)
+	ml.Info("This is a message")
+	ml.SetCallerFunc(NoFunc)
+	ml.Info("This is a message")
+	entries := ml.Entries()
+	if entries[0].Fields["func"] != short || entries[1].Fields["func"] != nil {
+		t.Error("unexpected fields", entries[0].Fields, entries[1].Fields)
+	}
+}
+
+//All of the built in loggers offer the Printf style methods
+var (
+	_ FormatLogger = new(FmtLog)
+	_ FormatLogger = new(StdLog)
+	_ FormatLogger = new(FileLog)
+	_ FormatLogger = new(JSONLog)
+	_ FormatLogger = new(SyslogLog)
+	_ FormatLogger = new(SyncLog)
+	_ FormatLogger = new(Stack)
+	_ FormatLogger = new(NopLog)
+	_ FormatLogger = new(MemoryLog)
+	_ FormatLogger = new(LogfmtLog)
+	_ FormatLogger = new(WriterLog)
+	_ FormatLogger = new(NetLog)
+)
+
+func TestNopLog(t *testing.T) {
+	var nop Logger = new(NopLog)
+	if err := nop.Init(); err != nil {
+		t.Error("unexpected Init error", err)
+	}
+	output := captureOutput(func() {
+		testOutput(captureStdout(func() {
+			nop.Error("This is a message")
+			nop.Log("custom level", "This is a message")
+		}), "", t)
+	})
+	testOutput(output, "", t)
+}
+
+func TestFormatMethods(t *testing.T) {
+	stdLog := new(StdLog)
+	output := captureOutput(func() {
+		stdLog.Infof("%d-%s", 1, "x")
+	})
+	testOutput(output, "Info 1-x\n", t)
+
+	output = captureOutput(func() {
+		stdLog.Logf("custom level", "%d-%s", 1, "x")
+	})
+	testOutput(output, "custom level 1-x\n", t)
+
+	output = captureOutput(func() {
+		stdLog.Emergencyf("%s", "a")
+		stdLog.Alertf("%s", "b")
+		stdLog.Criticalf("%s", "c")
+		stdLog.Errorf("%s", "d")
+		stdLog.Warningf("%s", "e")
+		stdLog.Noticef("%s", "f")
+		stdLog.Infof("%s", "g")
+		stdLog.Debugf("%s", "h")
+	})
+	testOutput(output, "Emergency a\nAlert b\nCritical c\nError d\nWarning e\nNotice f\nInfo g\nDebug h\n", t)
+
+	stack := new(Stack)
+	stack.Add(stdLog)
+	output = captureOutput(func() {
+		stack.Errorf("failed %d times", 3)
+	})
+	testOutput(output, "Error failed 3 times\n", t)
+}
+
 func TestStack(t *testing.T) {
 
 	stack := new(Stack)
@@ -42,7 +353,7 @@ func TestStack(t *testing.T) {
 	output := captureOutput(func() {
 		stack.Log("custom level", "Log to a custom level")
 	})
-	testOutput(output, "custom level [Log to a custom level]\n", t)
+	testOutput(output, "custom level Log to a custom level\n", t)
 
 }
 
@@ -76,7 +387,7 @@ func TestFileLog(t *testing.T) {
 		fmt.Print(err)
 	}
 	str := string(b)
-	if str != "Emergency [This is an Emergency message]\nAlert [This is an Alert message]\nCritical [This is a Critical message]\nError [This is an Error message]\nWarning [This is a Warning message]\nNotice [This is a Notice]\nInfo [This is an Info message]\nDebug [This is a Debug message]\n" {
+	if str != "Emergency This is an Emergency message\nAlert This is an Alert message\nCritical This is a Critical message\nError This is an Error message\nWarning This is a Warning message\nNotice This is a Notice\nInfo This is an Info message\nDebug This is a Debug message\n" {
 		t.Error("match failed for str", str)
 	}
 
@@ -100,53 +411,534 @@ func TestFileLog(t *testing.T) {
 
 	str = string(c)
 
-	if str != "Emergency [This is an Emergency message]\nAlert [This is an Alert message]\nCritical [This is a Critical message]\nError [This is an Error message]\nWarning [This is a Warning message]\nNotice [This is a Notice]\nInfo [This is an Info message]\nDebug [This is a Debug message]\n" {
+	if str != "Emergency This is an Emergency message\nAlert This is an Alert message\nCritical This is a Critical message\nError This is an Error message\nWarning This is a Warning message\nNotice This is a Notice\nInfo This is an Info message\nDebug This is a Debug message\n" {
 		t.Error("match failed for str", str)
 	}
+	fl2.Close()
 	os.Remove(fl2.logPath)
 
 }
 
+func TestFileLogReusesHandle(t *testing.T) {
+	fl := new(FileLog)
+	fl.OnInit(tlCallback)
+	if err := fl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer os.Remove(fl.logPath)
+
+	//The global log output must not be redirected to the file
+	output := captureOutput(func() {
+		for i := 0; i < 1000; i++ {
+			fl.Info("line", i)
+		}
+	})
+	testOutput(output, "", t)
+
+	if err := fl.Close(); err != nil {
+		t.Error("Close failed", err)
+	}
+
+	b, err := ioutil.ReadFile(fl.logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	if len(lines) != 1000 {
+		t.Fatal("expected 1000 lines, got", len(lines))
+	}
+	for i, line := range lines {
+		if line != fmt.Sprintf("Info [line %d]", i) {
+			t.Error("unexpected line", i, line)
+		}
+	}
+}
+
+func TestFileLogInitAgain(t *testing.T) {
+	path := "./test/output/testfile-init-again"
+	fl := new(FileLog)
+	fl.OnInit(func(s *FileLog) {
+		s.logPath = path
+		s.SetBuffer(1024, time.Hour)
+	})
+	if err := fl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer os.Remove(path)
+	fl.Info("first")
+	old := fl.f
+
+	//Initializing again, as Stack.Add does, flushes and closes the handle already open
+	if err := fl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer fl.Close()
+	if _, err := old.Write([]byte("x")); err == nil {
+		t.Error("expected the previous handle to be closed")
+	}
+	fl.Info("second")
+	fl.Flush()
+	b, err := ioutil.ReadFile(fl.logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testOutput(string(b), "Info first\nInfo second\n", t)
+}
+
+func TestFileLogLeavesGlobalLog(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	log.SetFlags(log.Lshortfile)
+	log.SetPrefix("global ")
+	defer func() {
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+		log.SetPrefix("")
+	}()
+
+	//Opened lazily on the first write rather than by Init
+	fl := new(FileLog)
+	tlCallback(fl)
+	defer os.Remove(fl.logPath)
+	fl.Info("first")
+
+	//Writes that rotate the file
+	fl.SetMaxSize(10)
+	fl.SetMaxBackups(1)
+	fl.Info("second")
+	fl.Info("third")
+	fl.Close()
+	defer os.Remove(fl.logPath + ".1")
+
+	if buf.Len() != 0 {
+		t.Error("expected nothing on the global logger, got", buf.String())
+	}
+	if log.Flags() != log.Lshortfile || log.Prefix() != "global " || log.Writer() != &buf {
+		t.Error("the global logger configuration was changed")
+	}
+}
+
+func TestFileLogFlush(t *testing.T) {
+	fl := new(FileLog)
+	fl.OnInit(tlCallback)
+	if err := fl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer os.Remove(fl.logPath)
+
+	fl.Critical("This is a message")
+	if err := fl.Flush(); err != nil {
+		t.Fatal("Flush failed", err)
+	}
+	//Read back through a fresh handle while the logger still holds the file open
+	b, err := ioutil.ReadFile(fl.logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testOutput(string(b), "Critical This is a message\n", t)
+
+	//Fatal flushes before exiting
+	var status int
+	fl.SetExitFunc(func(code int) {
+		status = code
+	})
+	fl.Fatal("This is fatal")
+	b, _ = ioutil.ReadFile(fl.logPath)
+	if status != 1 || !strings.HasSuffix(string(b), "Emergency This is fatal\n") {
+		t.Error("unexpected status or content", status, string(b))
+	}
+
+	fl.Close()
+	if err := fl.Flush(); err != nil {
+		t.Error("expected Flush on a closed logger to do nothing, got", err)
+	}
+}
+
+func TestFileLogWriteError(t *testing.T) {
+	var handled []error
+	fl := new(FileLog)
+	//A path below a regular file can never be created, even as root
+	fl.OnInit(func(s *FileLog) {
+		s.logPath = "./test/output/.gitkeep/log"
+	})
+	fl.OnError(func(err error) {
+		handled = append(handled, err)
+	})
+	if err := fl.Init(); err == nil {
+		t.Error("expected Init to fail for an unwritable path")
+	}
+
+	fl.Error("This is an Error message")
+	if fl.Err() == nil {
+		t.Error("expected Err to be populated")
+	}
+	if len(handled) != 1 || handled[0] != fl.Err() {
+		t.Error("expected the error handler to receive the error", handled)
+	}
+}
+
+func TestFileLogNestedPath(t *testing.T) {
+	defer os.RemoveAll("./test/output/testfile-nested")
+	fl := new(FileLog)
+	fl.OnInit(func(s *FileLog) {
+		s.logPath = "./test/output/testfile-nested/a/b/log"
+	})
+	if err := fl.Init(); err != nil {
+		t.Fatal("expected Init to create the missing directories", err)
+	}
+	fl.Info("This is a message")
+	if err := fl.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile("./test/output/testfile-nested/a/b/log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testOutput(string(b), "Info This is a message\n", t)
+}
+
+func TestFileLogFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not supported on windows")
+	}
+	defer os.RemoveAll("./test/output/testfile-mode")
+	fl := new(FileLog)
+	fl.OnInit(func(s *FileLog) {
+		s.logPath = "./test/output/testfile-mode/dir/log"
+	})
+	//Modes without group or other bits are unaffected by the usual umask
+	fl.SetFileMode(0600)
+	fl.SetDirMode(0700)
+	if err := fl.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer fl.Close()
+
+	info, err := os.Stat("./test/output/testfile-mode/dir/log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected file mode 0600, got %o", info.Mode().Perm())
+	}
+	info, err = os.Stat("./test/output/testfile-mode/dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("expected directory mode 0700, got %o", info.Mode().Perm())
+	}
+}
+
+func TestFileLogRotation(t *testing.T) {
+	fl := new(FileLog)
+	fl.OnInit(tlCallback)
+	if err := fl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	//Each line is 14 bytes so two lines fit in a file
+	fl.SetMaxSize(30)
+	fl.SetMaxBackups(2)
+	for i := 0; i < 6; i++ {
+		fl.Info("line", i)
+	}
+	fl.Close()
+	defer os.Remove(fl.logPath)
+	defer os.Remove(fl.logPath + ".1")
+	defer os.Remove(fl.logPath + ".2")
+
+	expected := map[string]string{
+		fl.logPath:        "Info [line 4]\nInfo [line 5]\n",
+		fl.logPath + ".1": "Info [line 2]\nInfo [line 3]\n",
+		fl.logPath + ".2": "Info [line 0]\nInfo [line 1]\n",
+	}
+	for path, content := range expected {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		testOutput(string(b), content, t)
+	}
+
+	//A third rotation drops the oldest backup
+	fl.Info("line", 6)
+	fl.Close()
+	if _, err := os.Stat(fl.logPath + ".3"); !os.IsNotExist(err) {
+		t.Error("expected no more than two backups")
+	}
+	b, _ := ioutil.ReadFile(fl.logPath + ".2")
+	testOutput(string(b), "Info [line 2]\nInfo [line 3]\n", t)
+	b, _ = ioutil.ReadFile(fl.logPath)
+	testOutput(string(b), "Info [line 6]\n", t)
+}
+
+func TestFileLogConcurrent(t *testing.T) {
+	fl := new(FileLog)
+	fl.OnInit(tlCallback)
+	if err := fl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	const backups = 200
+	fl.SetMaxSize(512)
+	fl.SetMaxBackups(backups)
+	defer func() {
+		os.Remove(fl.logPath)
+		for i := 1; i <= backups; i++ {
+			os.Remove(backupPath(fl.logPath, i))
+		}
+	}()
+
+	//Copies made by WithFields share the file, and the lock, with fl
+	const writers, lines = 8, 100
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			lg := fl.WithFields(map[string]interface{}{"writer": w})
+			for i := 0; i < lines; i++ {
+				lg.Info(strings.Repeat("x", 20), i)
+			}
+		}(w)
+	}
+	wg.Wait()
+	if err := fl.Close(); err != nil {
+		t.Fatal("Close failed", err)
+	}
+
+	line := regexp.MustCompile(`^Info \[x{20} \d+\] writer=\d$`)
+	count := 0
+	for i := 0; i <= backups; i++ {
+		path := fl.logPath
+		if i > 0 {
+			path = backupPath(fl.logPath, i)
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			break
+		}
+		for _, l := range strings.Split(strings.TrimSuffix(string(b), "\n"), "\n") {
+			if !line.MatchString(l) {
+				t.Fatalf("interleaved line %q in %s", l, path)
+			}
+			count++
+		}
+	}
+	if count != writers*lines {
+		t.Errorf("expected %d lines, found %d", writers*lines, count)
+	}
+}
+
+func TestFileLogConcurrentErrors(t *testing.T) {
+	//A directory can not be opened for writing, so every write fails
+	fl := new(FileLog)
+	fl.logPath = "./test/output"
+	var handled int32
+	fl.OnError(func(err error) {
+		atomic.AddInt32(&handled, 1)
+	})
+
+	const writers, lines = 8, 20
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < lines; i++ {
+				fl.Info("This is a message")
+				fl.Err()
+			}
+		}()
+	}
+	wg.Wait()
+	if fl.Err() == nil || atomic.LoadInt32(&handled) != writers*lines {
+		t.Error("expected every failure to be reported, got", fl.Err(), handled)
+	}
+}
+
+func TestFileLogCompressBackups(t *testing.T) {
+	fl := new(FileLog)
+	fl.OnInit(tlCallback)
+	if err := fl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	fl.SetMaxSize(30)
+	fl.SetMaxBackups(2)
+	fl.SetCompressBackups(true)
+	for i := 0; i < 6; i++ {
+		fl.Info("line", i)
+	}
+	//Close waits for the background compression
+	if err := fl.Close(); err != nil {
+		t.Fatal("Close failed", err)
+	}
+	defer os.Remove(fl.logPath)
+	defer os.Remove(fl.logPath + ".1.gz")
+	defer os.Remove(fl.logPath + ".2.gz")
+
+	expected := map[string]string{
+		fl.logPath + ".1": "Info [line 2]\nInfo [line 3]\n",
+		fl.logPath + ".2": "Info [line 0]\nInfo [line 1]\n",
+	}
+	for path, content := range expected {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Error("expected the uncompressed backup to be removed", path)
+		}
+		f, err := os.Open(path + ".gz")
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Error(err)
+			f.Close()
+			continue
+		}
+		b, err := ioutil.ReadAll(zr)
+		f.Close()
+		if err != nil {
+			t.Error(err)
+		}
+		testOutput(string(b), content, t)
+	}
+}
+
+func TestFileLogCompressDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	compressFile = func(path string) error {
+		<-release
+		return gzipFile(path)
+	}
+	defer func() { compressFile = gzipFile }()
+
+	fl := new(FileLog)
+	fl.OnInit(tlCallback)
+	if err := fl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	fl.SetMaxSize(30)
+	fl.SetMaxBackups(2)
+	fl.SetCompressBackups(true)
+	//Three rotations while the first compression is held up
+	logged := make(chan struct{})
+	go func() {
+		for i := 0; i < 8; i++ {
+			fl.Info("line", i)
+		}
+		close(logged)
+	}()
+	select {
+	case <-logged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected writes to go on while the backups are compressed")
+	}
+	close(release)
+	if err := fl.Close(); err != nil {
+		t.Fatal("Close failed", err)
+	}
+	defer os.Remove(fl.logPath)
+	defer os.Remove(fl.logPath + ".1.gz")
+	defer os.Remove(fl.logPath + ".2.gz")
+
+	expected := map[string]string{
+		fl.logPath + ".1.gz": "Info [line 4]\nInfo [line 5]\n",
+		fl.logPath + ".2.gz": "Info [line 2]\nInfo [line 3]\n",
+	}
+	for path, content := range expected {
+		f, err := os.Open(path)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Error(err)
+			f.Close()
+			continue
+		}
+		b, _ := ioutil.ReadAll(zr)
+		f.Close()
+		testOutput(string(b), content, t)
+	}
+	b, _ := ioutil.ReadFile(fl.logPath)
+	testOutput(string(b), "Info [line 6]\nInfo [line 7]\n", t)
+	if matches, _ := filepath.Glob(fl.logPath + ".*"); len(matches) != 2 {
+		t.Error("expected only the two compressed backups, got", matches)
+	}
+}
+
+//BenchmarkFileLogOpenPerCall reproduces the previous behaviour of opening the file for every write
+func BenchmarkFileLogOpenPerCall(b *testing.B) {
+	path := "./test/output/testfile-bench-open"
+	defer os.Remove(path)
+	for i := 0; i < b.N; i++ {
+		f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+		if err != nil {
+			b.Fatal(err)
+		}
+		log.New(f, "", 0).Println("Info", []interface{}{"This is a message"})
+		f.Close()
+	}
+}
+
+func BenchmarkFileLogCachedHandle(b *testing.B) {
+	fl := new(FileLog)
+	fl.OnInit(func(s *FileLog) {
+		s.logPath = "./test/output/testfile-bench-cached"
+	})
+	if err := fl.Init(); err != nil {
+		b.Fatal(err)
+	}
+	defer os.Remove(fl.logPath)
+	defer fl.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fl.Info("This is a message")
+	}
+}
+
 func testLogLevels(stdLog Logger, t *testing.T) {
 	output := captureOutput(func() {
 		stdLog.Emergency("This is a message")
 	})
-	testOutput(output, "Emergency [This is a message]\n", t)
+	testOutput(output, "Emergency This is a message\n", t)
 
 	output = captureOutput(func() {
 		stdLog.Alert("This is a message")
 	})
-	testOutput(output, "Alert [This is a message]\n", t)
+	testOutput(output, "Alert This is a message\n", t)
 
 	output = captureOutput(func() {
 		stdLog.Critical("This is a message")
 	})
-	testOutput(output, "Critical [This is a message]\n", t)
+	testOutput(output, "Critical This is a message\n", t)
 
 	output = captureOutput(func() {
 		stdLog.Error("This is a message")
 	})
-	testOutput(output, "Error [This is a message]\n", t)
+	testOutput(output, "Error This is a message\n", t)
 
 	output = captureOutput(func() {
 		stdLog.Warning("This is a message")
 	})
-	testOutput(output, "Warning [This is a message]\n", t)
+	testOutput(output, "Warning This is a message\n", t)
 
 	output = captureOutput(func() {
 		stdLog.Notice("This is a message")
 	})
-	testOutput(output, "Notice [This is a message]\n", t)
+	testOutput(output, "Notice This is a message\n", t)
 
 	output = captureOutput(func() {
 		stdLog.Info("This is a message")
 	})
-	testOutput(output, "Info [This is a message]\n", t)
+	testOutput(output, "Info This is a message\n", t)
 
 	output = captureOutput(func() {
 		stdLog.Debug("This is a message")
 	})
-	testOutput(output, "Debug [This is a message]\n", t)
+	testOutput(output, "Debug This is a message\n", t)
 }
 
 func testOutput(output string, expected string, t *testing.T) {
@@ -159,8 +951,22 @@ func testOutput(output string, expected string, t *testing.T) {
 func captureOutput(f func()) string {
 	var buf bytes.Buffer
 	log.SetOutput(&buf)
-	log.SetFlags(0)
 	f()
 	log.SetOutput(os.Stderr)
 	return buf.String()
 }
+
+//captureStdout captures anything written to os.Stdout, such as the output of FmtLog
+func captureStdout(f func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		panic(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	f()
+	os.Stdout = stdout
+	w.Close()
+	b, _ := ioutil.ReadAll(r)
+	return string(b)
+}
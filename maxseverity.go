@@ -0,0 +1,66 @@
+package logger
+
+import "sync"
+
+//severityTracker records the most severe level logged
+type severityTracker struct {
+	mu   sync.Mutex
+	max  Severity
+	seen bool
+}
+
+//record notes an entry at level, levels that are not known are ignored
+func (t *severityTracker) record(level string) {
+	sev, err := ParseLevel(level)
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.seen || sev < t.max {
+		t.max = sev
+		t.seen = true
+	}
+}
+
+//get returns the most severe level recorded and whether anything has been recorded
+func (t *severityTracker) get() (Severity, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.max, t.seen
+}
+
+//tracker returns the logger's severity tracker, creating it if needed
+//Loggers call it before copying themselves so that the copy shares the tracker
+//Once created it is read with a single atomic load, so logging takes no lock shared with other loggers
+func (l *LogBase) tracker() *severityTracker {
+	if t, ok := l.seen.Load().(*severityTracker); ok {
+		return t
+	}
+	//Only the first of several racing callers stores its tracker, the others load it
+	l.seen.CompareAndSwap(nil, new(severityTracker))
+	return l.seen.Load().(*severityTracker)
+}
+
+//MaxSeverity returns the most severe level logged so far, including through copies made with WithFields and Named
+//Entries dropped by SetLevel are not counted, LevelDebug is returned when nothing has been logged
+func (l *LogBase) MaxSeverity() Severity {
+	if sev, ok := l.tracker().get(); ok {
+		return sev
+	}
+	return LevelDebug
+}
+
+//HadErrors reports whether anything at Error or more severe has been logged
+func (l *LogBase) HadErrors() bool {
+	sev, ok := l.tracker().get()
+	return ok && sev <= LevelError
+}
+
+//ExitCode returns 1 if HadErrors and 0 otherwise, e.g. os.Exit(l.ExitCode()) at the end of a command line tool
+func (l *LogBase) ExitCode() int {
+	if l.HadErrors() {
+		return 1
+	}
+	return 0
+}
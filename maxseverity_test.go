@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+)
+
+func TestMaxSeverity(t *testing.T) {
+	ml := new(MemoryLog)
+	if ml.MaxSeverity() != LevelDebug || ml.HadErrors() || ml.ExitCode() != 0 {
+		t.Error("expected nothing to be recorded yet")
+	}
+	ml.Info("x")
+	ml.Debug("x")
+	ml.Log("custom level", "x")
+	if ml.MaxSeverity() != LevelInfo || ml.HadErrors() {
+		t.Error("unexpected max severity", ml.MaxSeverity())
+	}
+	ml.Warning("x")
+	ml.Info("x")
+	if ml.MaxSeverity() != LevelWarning || ml.ExitCode() != 0 {
+		t.Error("unexpected max severity", ml.MaxSeverity())
+	}
+	ml.Critical("x")
+	ml.Error("x")
+	if ml.MaxSeverity() != LevelCritical || !ml.HadErrors() || ml.ExitCode() != 1 {
+		t.Error("unexpected max severity", ml.MaxSeverity())
+	}
+
+	//Entries filtered out by the level are not counted
+	filtered := new(MemoryLog)
+	filtered.SetLevel("Warning")
+	filtered.Error("x")
+	filtered.Info("x")
+	if filtered.MaxSeverity() != LevelError {
+		t.Error("unexpected max severity", filtered.MaxSeverity())
+	}
+	filtered.SetLevel("Critical")
+	filtered.Log("Emergency", "x")
+	if filtered.MaxSeverity() != LevelEmergency {
+		t.Error("unexpected max severity", filtered.MaxSeverity())
+	}
+}
+
+func TestMaxSeverityCopies(t *testing.T) {
+	jl := new(JSONLog)
+	jl.SetOutput(ioutil.Discard)
+	child := jl.WithFields(map[string]interface{}{"k": "v"})
+	child.(NamedLogger).Named("db").Error("x")
+	if !jl.HadErrors() {
+		t.Error("expected entries logged through copies to be counted")
+	}
+}
+
+func TestMaxSeverityStack(t *testing.T) {
+	stack := new(Stack)
+	stack.Add(new(MemoryLog), new(MemoryLog))
+	stack.SetParallel(true)
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			stack.Info("x")
+			if g == 7 {
+				stack.Alert("x")
+			}
+			stack.Log("Debug", "x")
+		}(g)
+	}
+	wg.Wait()
+	if stack.MaxSeverity() != LevelAlert || stack.ExitCode() != 1 {
+		t.Error("unexpected max severity", stack.MaxSeverity())
+	}
+}
+
+func TestMaxSeverityConcurrentFirstUse(t *testing.T) {
+	ml := new(MemoryLog)
+	levels := []string{"Debug", "Info", "Critical", "Warning"}
+	var wg sync.WaitGroup
+	for _, level := range levels {
+		wg.Add(1)
+		go func(level string) {
+			defer wg.Done()
+			ml.Log(level, "message")
+		}(level)
+	}
+	wg.Wait()
+	if ml.MaxSeverity() != LevelCritical {
+		t.Error("expected every goroutine to record into the same tracker, got", ml.MaxSeverity())
+	}
+}
@@ -0,0 +1,238 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+//MemoryLog records every entry in memory, it is intended for asserting on log output in tests
+type MemoryLog struct {
+	LogBase
+	mu      sync.Mutex
+	entries []Entry
+	//root is the logger a WithFields or Named copy records into
+	root *MemoryLog
+}
+
+//Init expects input to be a list of func(s *MemoryLog) which will be called on initialization
+func (s *MemoryLog) Init() error {
+	for _, fn := range s.initializers {
+		switch funct := fn.(type) {
+		case func(s *MemoryLog):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *MemoryLog) or func(s Logger)")
+		}
+	}
+	return nil
+}
+
+//WithFields returns a copy of the logger, recording into the same entries, that adds fields to every entry
+func (s *MemoryLog) WithFields(fields map[string]interface{}) Logger {
+	c := s.clone()
+	c.fields = s.mergeFields(fields)
+	return c
+}
+
+//WithContext returns a copy of the logger carrying the context values registered with RegisterContextField
+func (s *MemoryLog) WithContext(ctx context.Context) Logger {
+	return s.WithFields(fieldsFromContext(ctx))
+}
+
+//WithError returns a copy of the logger carrying err as an error field, with a cause field when it wraps other errors
+//A nil err returns the logger itself
+func (s *MemoryLog) WithError(err error) Logger {
+	if err == nil {
+		return s
+	}
+	return s.WithFields(errorFields([]interface{}{err}))
+}
+
+//Named returns a copy of the logger, recording into the same entries, whose entries are tagged with name
+func (s *MemoryLog) Named(name string) Logger {
+	c := s.clone()
+	c.name = s.childName(name)
+	return c
+}
+
+//WithGroup returns a copy of the logger whose later fields are grouped under name, e.g. WithGroup("http")
+func (s *MemoryLog) WithGroup(name string) Logger {
+	c := s.clone()
+	c.groups = s.childGroups(name)
+	return c
+}
+
+//Clone returns a copy of the logger whose fields and level can be changed independently, it records into the same entries as s
+func (s *MemoryLog) Clone() Logger {
+	c := s.clone()
+	c.detach()
+	return c
+}
+
+//clone copies the logger for WithFields and Named, the copy records into the logger it was made from
+func (s *MemoryLog) clone() *MemoryLog {
+	s.tracker()
+	return &MemoryLog{LogBase: s.LogBase, root: s.sink()}
+}
+
+//sink returns the logger holding the entries
+func (s *MemoryLog) sink() *MemoryLog {
+	if s.root != nil {
+		return s.root
+	}
+	return s
+}
+
+//Entries returns a copy of the recorded entries, oldest first
+func (s *MemoryLog) Entries() []Entry {
+	s = s.sink()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := make([]Entry, len(s.entries))
+	copy(entries, s.entries)
+	return entries
+}
+
+//LastEntry returns the most recent entry, ok is false if nothing has been recorded
+func (s *MemoryLog) LastEntry() (e Entry, ok bool) {
+	s = s.sink()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.entries) == 0 {
+		return Entry{}, false
+	}
+	return s.entries[len(s.entries)-1], true
+}
+
+//Reset discards the recorded entries
+func (s *MemoryLog) Reset() {
+	s = s.sink()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = nil
+}
+
+//Drain returns the recorded entries, oldest first, and discards them in one step so no entry is lost or returned twice
+func (s *MemoryLog) Drain() []Entry {
+	s = s.sink()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := s.entries
+	s.entries = nil
+	return entries
+}
+
+//Replay logs the recorded entries to dst in order, keeping their levels and fields
+//The entries are kept, see ReplayDrain to take them, e.g. to flush buffered entries to a FileLog once an error occurs
+func (s *MemoryLog) Replay(dst Logger) {
+	for _, e := range s.Entries() {
+		ReplayEntry(dst, e)
+	}
+}
+
+//ReplayDrain is Replay for the entries taken by Drain, entries recorded while it runs are kept for the next call
+func (s *MemoryLog) ReplayDrain(dst Logger) {
+	for _, e := range s.Drain() {
+		ReplayEntry(dst, e)
+	}
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *MemoryLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *MemoryLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *MemoryLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *MemoryLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *MemoryLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *MemoryLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
+func (s *MemoryLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *MemoryLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *MemoryLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *MemoryLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *MemoryLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *MemoryLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *MemoryLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *MemoryLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *MemoryLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *MemoryLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *MemoryLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *MemoryLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *MemoryLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *MemoryLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *MemoryLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *MemoryLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *MemoryLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+func (s *MemoryLog) Log(level string, v ...interface{}) {
+	if !s.shouldLog(level) {
+		return
+	}
+	fields, args := s.prepare(level, v)
+	e := s.newEntry(level, fields, args)
+	root := s.sink()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	root.entries = append(root.entries, e)
+}
@@ -0,0 +1,160 @@
+package logger
+
+import (
+	"bytes"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMemoryLog(t *testing.T) {
+	ml := new(MemoryLog)
+	if err := ml.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	if _, ok := ml.LastEntry(); ok {
+		t.Error("expected no entries")
+	}
+
+	ml.Emergency("a")
+	ml.Alert("b")
+	ml.Critical("c")
+	ml.Error("d", 1)
+	ml.Warning("e")
+	ml.Notice("f")
+	ml.Info("g")
+	ml.Debug("h")
+	ml.Log("custom level", "i")
+
+	expected := []Entry{
+		{Level: "Emergency", Args: []interface{}{"a"}},
+		{Level: "Alert", Args: []interface{}{"b"}},
+		{Level: "Critical", Args: []interface{}{"c"}},
+		{Level: "Error", Args: []interface{}{"d", 1}},
+		{Level: "Warning", Args: []interface{}{"e"}},
+		{Level: "Notice", Args: []interface{}{"f"}},
+		{Level: "Info", Args: []interface{}{"g"}},
+		{Level: "Debug", Args: []interface{}{"h"}},
+		{Level: "custom level", Args: []interface{}{"i"}},
+	}
+	if !reflect.DeepEqual(levelArgs(ml.Entries()...), expected) {
+		t.Error("unexpected entries", ml.Entries())
+	}
+	last, ok := ml.LastEntry()
+	if !ok || !reflect.DeepEqual(levelArgs(last)[0], expected[len(expected)-1]) {
+		t.Error("unexpected last entry", last)
+	}
+
+	ml.Reset()
+	if len(ml.Entries()) != 0 {
+		t.Error("expected Reset to discard the entries")
+	}
+}
+
+//levelArgs returns the entries with only Level and Args set, for comparing against expected entries
+func levelArgs(entries ...Entry) []Entry {
+	stripped := make([]Entry, len(entries))
+	for i, e := range entries {
+		stripped[i] = Entry{Level: e.Level, Args: e.Args}
+	}
+	return stripped
+}
+
+func TestMemoryLogReplay(t *testing.T) {
+	buffered := new(MemoryLog)
+	buffered.Info("starting")
+	buffered.Warning("slow response", 250)
+	buffered.Log("audit", "login")
+
+	dst := new(MemoryLog)
+	buffered.Replay(dst)
+	expected := []Entry{
+		{Level: "Info", Args: []interface{}{"starting"}},
+		{Level: "Warning", Args: []interface{}{"slow response", 250}},
+		{Level: "audit", Args: []interface{}{"login"}},
+	}
+	if !reflect.DeepEqual(levelArgs(dst.Entries()...), expected) {
+		t.Error("unexpected replayed entries", dst.Entries())
+	}
+	if len(buffered.Entries()) != 3 {
+		t.Error("expected Replay to keep the entries")
+	}
+}
+
+func TestMemoryLogReplayFields(t *testing.T) {
+	buffered := new(MemoryLog)
+	buffered.SetPrefix("db")
+
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	buffered.Error("query failed")
+	buffered.Replay(jl)
+	m := decodeJSONLine(buf.Bytes(), t)
+	if m["level"] != "Error" || m["message"] != "query failed" || m["logger"] != "db" {
+		t.Error("unexpected replayed entry", m)
+	}
+
+	//Fields stay structured when replaying into another MemoryLog
+	dst := new(MemoryLog)
+	buffered.Replay(dst)
+	e, _ := dst.LastEntry()
+	if !reflect.DeepEqual(e.Args, []interface{}{"query failed"}) || e.Fields["logger"] != "db" {
+		t.Error("unexpected replayed entry", e.Args, e.Fields)
+	}
+}
+
+func TestMemoryLogWithFields(t *testing.T) {
+	ml := new(MemoryLog)
+	child := ml.WithFields(map[string]interface{}{"user": "bob"})
+	child.(NamedLogger).Named("auth").Info("login")
+	ml.Info("plain")
+
+	entries := ml.Entries()
+	if len(entries) != 2 {
+		t.Fatal("expected copies to record into the parent, got", entries)
+	}
+	if entries[0].Fields["user"] != "bob" || entries[0].Fields["logger"] != "auth" {
+		t.Error("unexpected fields", entries[0].Fields)
+	}
+	if len(entries[1].Fields) != 0 {
+		t.Error("expected the parent to be unchanged", entries[1].Fields)
+	}
+	if e, _ := child.(*MemoryLog).LastEntry(); e.Args[0] != "plain" {
+		t.Error("expected the copy to read the shared entries", e)
+	}
+}
+
+func TestMemoryLogDrain(t *testing.T) {
+	ml := new(MemoryLog)
+	var wg sync.WaitGroup
+	var drained int64
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ml.Info("entry")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				atomic.AddInt64(&drained, int64(len(ml.Drain())))
+			}
+		}()
+	}
+	wg.Wait()
+	//Every entry is drained exactly once
+	if total := drained + int64(len(ml.Drain())); total != 400 {
+		t.Error("expected 400 entries drained, got", total)
+	}
+
+	ml.Warning("buffered")
+	dst := new(MemoryLog)
+	ml.ReplayDrain(dst)
+	if len(ml.Entries()) != 0 || len(dst.Entries()) != 1 {
+		t.Error("expected ReplayDrain to move the entry", ml.Entries(), dst.Entries())
+	}
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+//MemberError is the error returned by one logger in a Stack, Index is the logger's position in the stack
+type MemberError struct {
+	Index int
+	Err   error
+}
+
+//Error implements error
+func (e *MemberError) Error() string {
+	return fmt.Sprintf("logger %d: %v", e.Index, e.Err)
+}
+
+//Unwrap returns the logger's error
+func (e *MemberError) Unwrap() error {
+	return e.Err
+}
+
+//MultiError holds the errors of the loggers in a Stack that failed, in stack order
+//errors.Is and errors.As look through it to each logger's error
+type MultiError struct {
+	Errors []*MemberError
+}
+
+//Error implements error, listing every failed logger
+func (e *MultiError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d loggers failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+//Unwrap returns the error of each failed logger as a *MemberError
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+//add records err for the logger at index, nil errors are ignored
+func (e *MultiError) add(index int, err error) {
+	if err != nil {
+		e.Errors = append(e.Errors, &MemberError{Index: index, Err: err})
+	}
+}
+
+//err returns e, or nil when no logger failed
+func (e *MultiError) err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
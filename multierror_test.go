@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+)
+
+//closeError is the error returned by typedCloseFailLog
+type closeError struct {
+	name string
+}
+
+func (e *closeError) Error() string {
+	return e.name + " close failed"
+}
+
+//typedCloseFailLog fails to close with a *closeError
+type typedCloseFailLog struct {
+	MemoryLog
+	name string
+}
+
+func (c *typedCloseFailLog) Close() error {
+	return &closeError{name: c.name}
+}
+
+func TestMultiErrorClose(t *testing.T) {
+	stack := new(Stack)
+	stack.Add(new(typedCloseFailLog), new(MemoryLog), new(typedCloseFailLog))
+	stack.loggers[0].(*typedCloseFailLog).name = "first"
+	stack.loggers[2].(*typedCloseFailLog).name = "third"
+
+	err := stack.Close()
+	var me *MultiError
+	if !errors.As(err, &me) {
+		t.Fatal("expected a *MultiError, got", err)
+	}
+	if len(me.Errors) != 2 || me.Errors[0].Index != 0 || me.Errors[1].Index != 2 {
+		t.Fatal("unexpected member errors", me.Errors)
+	}
+	expected := "2 loggers failed: logger 0: first close failed; logger 2: third close failed"
+	if err.Error() != expected {
+		t.Error("unexpected message", err)
+	}
+
+	var ce *closeError
+	if !errors.As(err, &ce) || ce.name != "first" {
+		t.Error("expected errors.As to find the first member's error, got", ce)
+	}
+	if !errors.Is(err, me.Errors[1].Err) {
+		t.Error("expected errors.Is to find the second member's error")
+	}
+	var member *MemberError
+	if !errors.As(err, &member) || member.Index != 0 {
+		t.Error("expected errors.As to find the first *MemberError, got", member)
+	}
+}
+
+func TestMultiErrorTryLog(t *testing.T) {
+	stack := new(Stack)
+	stack.Add(new(failLog), new(MemoryLog), new(failLog))
+	err := stack.TryLog("Error", "This is a message")
+	var me *MultiError
+	if !errors.As(err, &me) || len(me.Errors) != 2 {
+		t.Fatal("expected both failures, got", err)
+	}
+	if me.Errors[0].Index != 0 || me.Errors[1].Index != 2 {
+		t.Error("unexpected indexes", me.Errors[0].Index, me.Errors[1].Index)
+	}
+
+	stack = new(Stack)
+	stack.Add(new(MemoryLog))
+	if err := stack.TryLog("Error", "This is a message"); err != nil {
+		t.Error("expected a nil error when every logger succeeds, got", err)
+	}
+}
+
+func TestMultiErrorInit(t *testing.T) {
+	bad := new(RingLog)
+	bad.SetCapacity(-1)
+	stack := new(Stack)
+	err := stack.Add(new(MemoryLog), bad)
+	var me *MultiError
+	if !errors.As(err, &me) || len(me.Errors) != 1 || me.Errors[0].Index != 1 {
+		t.Fatal("expected the second logger's init error, got", err)
+	}
+	if err.Error() != "logger 1: ring capacity must not be negative, got -1" {
+		t.Error("unexpected message", err)
+	}
+}
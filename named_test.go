@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+var (
+	_ NamedLogger = new(FmtLog)
+	_ NamedLogger = new(StdLog)
+	_ NamedLogger = new(FileLog)
+	_ NamedLogger = new(JSONLog)
+	_ NamedLogger = new(LogfmtLog)
+	_ NamedLogger = new(WriterLog)
+	_ NamedLogger = new(NetLog)
+)
+
+func TestNamed(t *testing.T) {
+	stdLog := new(StdLog)
+	auth := stdLog.Named("auth")
+	methods := map[string]func(v ...interface{}){
+		"Emergency": auth.Emergency,
+		"Alert":     auth.Alert,
+		"Critical":  auth.Critical,
+		"Error":     auth.Error,
+		"Warning":   auth.Warning,
+		"Notice":    auth.Notice,
+		"Info":      auth.Info,
+		"Debug":     auth.Debug,
+	}
+	for level, method := range methods {
+		output := captureOutput(func() {
+			method("This is a message")
+		})
+		testOutput(output, "[auth] "+level+" This is a message\n", t)
+	}
+	output := captureOutput(func() {
+		auth.Log("custom level", "This is a message")
+	})
+	testOutput(output, "[auth] custom level This is a message\n", t)
+
+	//The parent is left untouched
+	output = captureOutput(func() {
+		stdLog.Info("This is a message")
+	})
+	testOutput(output, "Info This is a message\n", t)
+}
+
+func TestNamedNested(t *testing.T) {
+	stdLog := new(StdLog)
+	stdLog.SetPrefix("app")
+	child := stdLog.Named("auth").(NamedLogger).Named("session").(FieldLogger)
+	output := captureOutput(func() {
+		stdLog.Info("This is a message")
+		child.WithFields(map[string]interface{}{"user": "bob"}).Error("This is a message")
+	})
+	testOutput(output, "[app] Info This is a message\n[app.auth.session] Error This is a message user=bob\n", t)
+}
+
+func TestNamedJSON(t *testing.T) {
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	jl.Named("auth").(NamedLogger).Named("session").Error("This is a message")
+	entry := decodeJSONLine(buf.Bytes(), t)
+	if entry["logger"] != "auth.session" {
+		t.Error("expected a logger field, got", entry)
+	}
+}
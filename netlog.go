@@ -0,0 +1,517 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+//ErrClosed is reported for entries logged to a NetLog after Close
+var ErrClosed = errors.New("logger is closed")
+
+//NetLog writes each entry to a TCP or UDP connection, rendered by a pluggable Formatter
+//When a write fails the connection is re-dialled with exponential backoff, and entries logged
+//in the meantime are held in a bounded buffer and sent once the connection is back
+//Once initialized it is safe for concurrent use
+type NetLog struct {
+	LogBase
+	network   string
+	addr      string
+	formatter Formatter
+	conn      net.Conn
+	//root is the logger a WithFields or Named copy writes through
+	root *NetLog
+	//mu holds the *sync.Mutex guarding the connection, batch and pending entries, see mutex
+	mu     atomic.Value
+	closed bool
+
+	batchSize     int
+	flushInterval time.Duration
+	batch         []byte
+	done          chan struct{}
+
+	maxDatagram  int
+	maxPending   int
+	pending      [][]byte
+	pendingBytes int
+
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	backoff    time.Duration
+	nextDial   time.Time
+}
+
+//Defaults for NetLog, a datagram that fits in a typical ethernet frame and 64KiB of buffered entries
+const (
+	defaultMaxDatagram = 1472
+	defaultMaxPending  = 64 * 1024
+	defaultMinBackoff  = 100 * time.Millisecond
+	defaultMaxBackoff  = 30 * time.Second
+)
+
+//SetAddress sets where entries are sent, network is "tcp" or "udp", e.g. SetAddress("tcp", "logs:5000")
+func (s *NetLog) SetAddress(network, addr string) {
+	s.network = network
+	s.addr = addr
+}
+
+//SetFormatter sets how entries are rendered, TextFormatter is used when none is set
+func (s *NetLog) SetFormatter(f Formatter) {
+	s.formatter = f
+}
+
+//SetMaxDatagramSize sets the largest UDP datagram sent, longer entries are truncated
+func (s *NetLog) SetMaxDatagramSize(n int) {
+	s.maxDatagram = n
+}
+
+//SetMaxPending sets how many bytes of entries are held while reconnecting, the oldest are dropped beyond it
+func (s *NetLog) SetMaxPending(n int) {
+	s.maxPending = n
+}
+
+//SetBackoff sets the first and the longest delay between reconnection attempts
+func (s *NetLog) SetBackoff(min, max time.Duration) {
+	s.minBackoff = min
+	s.maxBackoff = max
+}
+
+//SetBatchSize batches TCP entries, sending them together once at least bytes are waiting
+//Batches are also sent by Flush, Close and, when set, every SetFlushInterval
+//0 (the default) sends each entry as it is logged, UDP entries are always sent one per datagram
+func (s *NetLog) SetBatchSize(bytes int) {
+	s.batchSize = bytes
+}
+
+//SetFlushInterval sends any batched entries every d, starting from Init
+//0 (the default) only sends batches when they are full or on Flush and Close
+func (s *NetLog) SetFlushInterval(d time.Duration) {
+	s.flushInterval = d
+}
+
+//Init expects input to be a list of func(s *NetLog), typically used to call SetAddress, and then dials the address
+func (s *NetLog) Init() error {
+	for _, fn := range s.initializers {
+		switch funct := fn.(type) {
+		case func(s *NetLog):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *NetLog) or func(s Logger)")
+		}
+	}
+	if s.network != "tcp" && s.network != "udp" {
+		return fmt.Errorf("unsupported network %q, expected tcp or udp", s.network)
+	}
+	defer s.lock().Unlock()
+	s.closed = false
+	if err := s.dial(); err != nil {
+		return err
+	}
+	if s.flushInterval > 0 && s.done == nil {
+		s.done = make(chan struct{})
+		go s.flushEvery(s.flushInterval, s.done)
+	}
+	return nil
+}
+
+//mutex returns the mutex guarding the logger, creating it if needed
+//It is called before the logger copies itself so that the copy is not made while the mutex is being stored
+func (s *NetLog) mutex() *sync.Mutex {
+	if mu, ok := s.mu.Load().(*sync.Mutex); ok {
+		return mu
+	}
+	//Only the first of several racing callers stores its mutex, the others load it
+	s.mu.CompareAndSwap(nil, new(sync.Mutex))
+	return s.mu.Load().(*sync.Mutex)
+}
+
+//lock locks the mutex guarding the logger and returns it
+func (s *NetLog) lock() *sync.Mutex {
+	mu := s.mutex()
+	mu.Lock()
+	return mu
+}
+
+//flushEvery sends batched entries every d until done is closed
+//Entries that can not be sent stay pending and any error is reported by the next Log or Flush
+func (s *NetLog) flushEvery(d time.Duration, done chan struct{}) {
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			mu := s.lock()
+			s.flushBatch()
+			s.send()
+			mu.Unlock()
+		case <-done:
+			return
+		}
+	}
+}
+
+//flushBatch moves the current batch onto the pending entries, it must be called with mu held
+func (s *NetLog) flushBatch() int {
+	if len(s.batch) == 0 {
+		return 0
+	}
+	b := s.batch
+	s.batch = nil
+	return s.queue(b)
+}
+
+//Flush sends any batched or pending entries
+func (s *NetLog) Flush() error {
+	s = s.sink()
+	defer s.lock().Unlock()
+	if s.closed {
+		return nil
+	}
+	dropped := s.flushBatch()
+	if err := s.send(); err != nil {
+		return err
+	}
+	if dropped > 0 {
+		return fmt.Errorf("dropped %d entries while reconnecting to %s", dropped, s.addr)
+	}
+	return nil
+}
+
+//dial connects to the configured address
+func (s *NetLog) dial() error {
+	conn, err := net.Dial(s.network, s.addr)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	s.backoff = 0
+	return nil
+}
+
+//disconnect drops the connection and schedules the next reconnection attempt
+func (s *NetLog) disconnect() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+	min, max := s.minBackoff, s.maxBackoff
+	if min <= 0 {
+		min = defaultMinBackoff
+	}
+	if max <= 0 {
+		max = defaultMaxBackoff
+	}
+	switch {
+	case s.backoff < min:
+		s.backoff = min
+	case s.backoff*2 > max:
+		s.backoff = max
+	default:
+		s.backoff *= 2
+	}
+	s.nextDial = s.now().Add(s.backoff)
+}
+
+//Close sends any batched entries and closes the connection, entries still waiting for a reconnection are discarded
+//Closing a copy made by WithFields or Named closes the connection it shares, entries logged afterwards report ErrClosed
+func (s *NetLog) Close() error {
+	s = s.sink()
+	defer s.lock().Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.done != nil {
+		close(s.done)
+		s.done = nil
+	}
+	s.flushBatch()
+	err := s.send()
+	s.pending = nil
+	s.pendingBytes = 0
+	if s.conn == nil {
+		return err
+	}
+	if cerr := s.conn.Close(); err == nil {
+		err = cerr
+	}
+	s.conn = nil
+	return err
+}
+
+//WithFields returns a copy of the logger that adds fields to every entry
+//The copy writes through the same connection
+func (s *NetLog) WithFields(fields map[string]interface{}) Logger {
+	c := s.clone()
+	c.fields = s.mergeFields(fields)
+	return c
+}
+
+//clone copies the logger for WithFields and Named
+//The copy writes through the logger it was made from so that they share the batch, pending entries and connection
+func (s *NetLog) clone() *NetLog {
+	s.tracker()
+	s.mutex()
+	c := *s
+	c.root = s.sink()
+	return &c
+}
+
+//sink returns the logger holding the connection, the logger itself unless it was made by WithFields or Named
+func (s *NetLog) sink() *NetLog {
+	if s.root != nil {
+		return s.root
+	}
+	return s
+}
+
+//WithContext returns a copy of the logger carrying the context values registered with RegisterContextField
+func (s *NetLog) WithContext(ctx context.Context) Logger {
+	return s.WithFields(fieldsFromContext(ctx))
+}
+
+//WithError returns a copy of the logger carrying err as an error field, with a cause field when it wraps other errors
+//A nil err returns the logger itself
+func (s *NetLog) WithError(err error) Logger {
+	if err == nil {
+		return s
+	}
+	return s.WithFields(errorFields([]interface{}{err}))
+}
+
+//Named returns a copy of the logger, sharing the same connection, whose entries are tagged with name
+func (s *NetLog) Named(name string) Logger {
+	c := s.clone()
+	c.name = s.childName(name)
+	return c
+}
+
+//WithGroup returns a copy of the logger whose later fields are grouped under name, e.g. WithGroup("http")
+func (s *NetLog) WithGroup(name string) Logger {
+	c := s.clone()
+	c.groups = s.childGroups(name)
+	return c
+}
+
+//Clone returns a copy of the logger whose fields and level can be changed independently, it writes through the connection of s
+func (s *NetLog) Clone() Logger {
+	c := s.clone()
+	c.detach()
+	return c
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *NetLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *NetLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *NetLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *NetLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *NetLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *NetLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
+func (s *NetLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *NetLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *NetLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *NetLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *NetLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *NetLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *NetLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *NetLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *NetLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *NetLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *NetLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *NetLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *NetLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *NetLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *NetLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *NetLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *NetLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+func (s *NetLog) Log(level string, v ...interface{}) {
+	if err := s.write(level, v); err != nil {
+		s.handleError(err)
+	}
+}
+
+//TryLog is Log that also returns any error hit while writing
+func (s *NetLog) TryLog(level string, v ...interface{}) error {
+	err := s.write(level, v)
+	if err != nil {
+		s.handleError(err)
+	}
+	return err
+}
+
+//write formats the entry, queues it behind anything still pending and sends what it can
+//With batching the entry is added to the batch, which is only queued once it is full
+func (s *NetLog) write(level string, v []interface{}) error {
+	if !s.shouldLog(level) {
+		return nil
+	}
+	formatter := s.formatter
+	if formatter == nil {
+		formatter = s.textFormatter()
+	}
+	formatter = s.configureFormatter(formatter)
+	fields, args := s.prepare(level, v)
+	b, err := formatter.Format(level, fields, args)
+	if err != nil {
+		return err
+	}
+	if s.network == "udp" {
+		b = truncateDatagram(b, s.maxDatagram)
+	}
+	r := s.sink()
+	defer r.lock().Unlock()
+	if r.closed {
+		return ErrClosed
+	}
+	if r.batchSize > 0 && r.network != "udp" {
+		r.batch = append(r.batch, b...)
+		if len(r.batch) < r.batchSize {
+			return nil
+		}
+		b = r.batch
+		r.batch = nil
+	}
+	dropped := r.queue(b)
+	if err := r.send(); err != nil {
+		return err
+	}
+	if dropped > 0 {
+		return fmt.Errorf("dropped %d entries while reconnecting to %s", dropped, r.addr)
+	}
+	return nil
+}
+
+//queue adds b to the pending entries, dropping the oldest beyond the limit, and returns how many were dropped
+func (s *NetLog) queue(b []byte) int {
+	max := s.maxPending
+	if max <= 0 {
+		max = defaultMaxPending
+	}
+	s.pending = append(s.pending, b)
+	s.pendingBytes += len(b)
+	dropped := 0
+	for s.pendingBytes > max && len(s.pending) > 1 {
+		s.pendingBytes -= len(s.pending[0])
+		s.pending = s.pending[1:]
+		dropped++
+	}
+	return dropped
+}
+
+//send writes pending entries in order, reconnecting first if the backoff delay has passed
+//Entries that could not be written stay pending for the next attempt
+func (s *NetLog) send() error {
+	if len(s.pending) == 0 {
+		return nil
+	}
+	if s.conn == nil {
+		if s.now().Before(s.nextDial) {
+			return nil
+		}
+		if err := s.dial(); err != nil {
+			s.disconnect()
+			return err
+		}
+	}
+	for len(s.pending) > 0 {
+		b := s.pending[0]
+		n, err := s.conn.Write(b)
+		s.pendingBytes -= n
+		if err != nil {
+			//Only the part not written is sent after reconnecting, so nothing is sent twice
+			s.pending[0] = b[n:]
+			s.disconnect()
+			return err
+		}
+		s.pending = s.pending[1:]
+	}
+	return nil
+}
+
+//truncateDatagram shortens b to fit in max bytes without splitting a UTF-8 character,
+//marking the cut with "..." and keeping the trailing newline
+func truncateDatagram(b []byte, max int) []byte {
+	if max <= 0 {
+		max = defaultMaxDatagram
+	}
+	if len(b) <= max {
+		return b
+	}
+	const marker = "...\n"
+	cut := max - len(marker)
+	if cut < 0 {
+		cut = 0
+	}
+	for cut > 0 && !utf8.RuneStart(b[cut]) {
+		cut--
+	}
+	return []byte(strings.TrimRight(string(b[:cut]), "\n") + marker)
+}
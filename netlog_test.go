@@ -0,0 +1,348 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+//acceptLines accepts a single connection on ln and sends each line it reads on the returned channel
+func acceptLines(t *testing.T, ln net.Listener) <-chan string {
+	lines := make(chan string, 100)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			close(lines)
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	return lines
+}
+
+func expectLine(t *testing.T, lines <-chan string, expected string) {
+	t.Helper()
+	select {
+	case line := <-lines:
+		if line != expected {
+			t.Error("expected", expected, "got", line)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for", expected)
+	}
+}
+
+func TestNetLogTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	lines := acceptLines(t, ln)
+
+	nl := new(NetLog)
+	nl.OnInit(func(s *NetLog) {
+		s.SetAddress("tcp", ln.Addr().String())
+	})
+	if err := nl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer nl.Close()
+
+	nl.Info("This is a message")
+	nl.WithFields(map[string]interface{}{"user": "bob"}).Error("This is a message")
+	expectLine(t, lines, "Info This is a message")
+	expectLine(t, lines, "Error This is a message user=bob")
+}
+
+func TestNetLogReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	lines := acceptLines(t, ln)
+
+	nl := new(NetLog)
+	nl.SetAddress("tcp", ln.Addr().String())
+	nl.SetBackoff(time.Millisecond, 10*time.Millisecond)
+	if err := nl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer nl.Close()
+	nl.Info("before")
+	expectLine(t, lines, "Info before")
+
+	//Break the connection from our side and check entries are held until the reconnect
+	nl.conn.Close()
+	second := acceptLines(t, ln)
+	nl.Info("during")
+	if len(nl.pending) != 1 {
+		t.Fatal("expected the entry to be held while disconnected, pending", len(nl.pending))
+	}
+	time.Sleep(5 * time.Millisecond)
+	nl.Info("after")
+	expectLine(t, second, "Info during")
+	expectLine(t, second, "Info after")
+}
+
+func TestNetLogUDPTruncate(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	nl := new(NetLog)
+	nl.SetAddress("udp", pc.LocalAddr().String())
+	nl.SetMaxDatagramSize(32)
+	if err := nl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer nl.Close()
+
+	nl.Info(strings.Repeat("é", 40))
+	buf := make([]byte, 2048)
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(buf[:n])
+	if n > 32 || !strings.HasSuffix(got, "...\n") || !strings.HasPrefix(got, "Info é") {
+		t.Error("unexpected datagram", n, got)
+	}
+	if strings.ContainsRune(got, '�') {
+		t.Error("a character was split", got)
+	}
+}
+
+func TestNetLogUnsupportedNetwork(t *testing.T) {
+	nl := new(NetLog)
+	nl.SetAddress("unix", "/tmp/socket")
+	if err := nl.Init(); err == nil {
+		t.Error("expected an error for an unsupported network")
+	}
+}
+
+//countConn counts the writes made to a connection
+type countConn struct {
+	net.Conn
+	writes int
+}
+
+func (c *countConn) Write(b []byte) (int, error) {
+	c.writes++
+	return c.Conn.Write(b)
+}
+
+func TestNetLogBatch(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	lines := acceptLines(t, ln)
+
+	nl := new(NetLog)
+	nl.SetAddress("tcp", ln.Addr().String())
+	nl.SetBatchSize(1024)
+	if err := nl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	conn := &countConn{Conn: nl.conn}
+	nl.conn = conn
+
+	child := nl.WithFields(map[string]interface{}{"n": 1})
+	for i := 0; i < 100; i++ {
+		nl.Info("This is a message", i)
+		child.Info("This is a message", i)
+	}
+	if err := nl.Close(); err != nil {
+		t.Fatal("Close failed", err)
+	}
+	for i := 0; i < 100; i++ {
+		expectLine(t, lines, fmt.Sprintf("Info [This is a message %d]", i))
+		expectLine(t, lines, fmt.Sprintf("Info [This is a message %d] n=1", i))
+	}
+	//Around 6KB of entries in 1KB batches, with the remainder sent by Close
+	if conn.writes > 7 {
+		t.Error("expected the entries to be sent in a few batches, got writes", conn.writes)
+	}
+}
+
+func TestNetLogFlushInterval(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	lines := acceptLines(t, ln)
+
+	nl := new(NetLog)
+	nl.SetAddress("tcp", ln.Addr().String())
+	nl.SetBatchSize(64 * 1024)
+	nl.SetFlushInterval(10 * time.Millisecond)
+	if err := nl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer nl.Close()
+
+	//Far below the batch size, so only the interval sends it
+	nl.Info("This is a message")
+	expectLine(t, lines, "Info This is a message")
+}
+
+func TestNetLogBatchFlush(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	lines := acceptLines(t, ln)
+
+	nl := new(NetLog)
+	nl.SetAddress("tcp", ln.Addr().String())
+	nl.SetBatchSize(64 * 1024)
+	if err := nl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer nl.Close()
+	nl.Info("first")
+	nl.Info("second")
+	if err := nl.Flush(); err != nil {
+		t.Fatal("Flush failed", err)
+	}
+	expectLine(t, lines, "Info first")
+	expectLine(t, lines, "Info second")
+}
+
+func TestNetLogBackoffClock(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	lines := acceptLines(t, ln)
+
+	clock := newFakeClock(time.Date(2017, 6, 29, 12, 0, 0, 0, time.UTC))
+	nl := new(NetLog)
+	nl.SetAddress("tcp", ln.Addr().String())
+	nl.SetClock(clock)
+	nl.SetBackoff(time.Second, time.Minute)
+	if err := nl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer nl.Close()
+	nl.Info("before")
+	expectLine(t, lines, "Info before")
+
+	//The backoff is measured by the logger's clock, no reconnection is attempted until it moves
+	nl.conn.Close()
+	second := acceptLines(t, ln)
+	nl.Info("during")
+	time.Sleep(10 * time.Millisecond)
+	nl.Info("still")
+	if len(nl.pending) != 2 {
+		t.Fatal("expected the entries to be held until the clock moved, pending", len(nl.pending))
+	}
+	clock.Advance(time.Second)
+	nl.Info("after")
+	expectLine(t, second, "Info during")
+	expectLine(t, second, "Info still")
+	expectLine(t, second, "Info after")
+}
+
+func TestNetLogClosed(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	lines := acceptLines(t, ln)
+
+	nl := new(NetLog)
+	nl.SetAddress("tcp", ln.Addr().String())
+	if err := nl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	var handled error
+	nl.OnError(func(err error) {
+		handled = err
+	})
+	nl.Info("before")
+	if err := nl.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expectLine(t, lines, "Info before")
+
+	//Nothing reconnects after Close, the entries are reported instead
+	second := acceptLines(t, ln)
+	nl.Info("after")
+	if handled != ErrClosed {
+		t.Error("expected ErrClosed to be passed to OnError, got", handled)
+	}
+	if err := nl.WithFields(map[string]interface{}{"k": "v"}).(ErrorLogger).TryLog("Info", "after"); err != ErrClosed {
+		t.Error("expected ErrClosed from a copy, got", err)
+	}
+	if err := nl.Close(); err != nil {
+		t.Error("expected a second Close to succeed, got", err)
+	}
+	select {
+	case line, ok := <-second:
+		if ok {
+			t.Error("unexpected line after Close", line)
+		}
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+//partialConn writes at most limit bytes of the first write and then fails
+type partialConn struct {
+	net.Conn
+	limit int
+}
+
+func (c *partialConn) Write(b []byte) (int, error) {
+	if len(b) > c.limit {
+		n, _ := c.Conn.Write(b[:c.limit])
+		return n, fmt.Errorf("short write")
+	}
+	return c.Conn.Write(b)
+}
+
+func TestNetLogPartialWrite(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	lines := acceptLines(t, ln)
+
+	nl := new(NetLog)
+	nl.SetAddress("tcp", ln.Addr().String())
+	nl.SetBackoff(time.Millisecond, 10*time.Millisecond)
+	if err := nl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer nl.Close()
+	nl.OnError(func(error) {})
+	nl.conn = &partialConn{Conn: nl.conn, limit: len("Info ")}
+	nl.Info("partial write")
+	expectLine(t, lines, "Info ")
+
+	//Only the remainder is sent once reconnected
+	second := acceptLines(t, ln)
+	time.Sleep(5 * time.Millisecond)
+	nl.Info("after")
+	expectLine(t, second, "partial write")
+	expectLine(t, second, "Info after")
+}
@@ -0,0 +1,39 @@
+package logger
+
+//NopLog discards everything, it is a safe default for code that takes a Logger
+type NopLog struct{}
+
+//Init does nothing
+func (s *NopLog) Init() error {
+	return nil
+}
+
+//OnInit does nothing
+func (s *NopLog) OnInit(f ...interface{}) {}
+
+//OnInitLogger does nothing
+func (s *NopLog) OnInitLogger(f func(s Logger)) {}
+
+//Enabled is always false as nothing is ever written
+func (s *NopLog) Enabled(level string) bool {
+	return false
+}
+
+func (s *NopLog) Log(level string, v ...interface{})                    {}
+func (s *NopLog) Emergency(v ...interface{})                            {}
+func (s *NopLog) Alert(v ...interface{})                                {}
+func (s *NopLog) Critical(v ...interface{})                             {}
+func (s *NopLog) Error(v ...interface{})                                {}
+func (s *NopLog) Warning(v ...interface{})                              {}
+func (s *NopLog) Notice(v ...interface{})                               {}
+func (s *NopLog) Info(v ...interface{})                                 {}
+func (s *NopLog) Debug(v ...interface{})                                {}
+func (s *NopLog) Logf(level string, format string, args ...interface{}) {}
+func (s *NopLog) Emergencyf(format string, args ...interface{})         {}
+func (s *NopLog) Alertf(format string, args ...interface{})             {}
+func (s *NopLog) Criticalf(format string, args ...interface{})          {}
+func (s *NopLog) Errorf(format string, args ...interface{})             {}
+func (s *NopLog) Warningf(format string, args ...interface{})           {}
+func (s *NopLog) Noticef(format string, args ...interface{})            {}
+func (s *NopLog) Infof(format string, args ...interface{})              {}
+func (s *NopLog) Debugf(format string, args ...interface{})             {}
@@ -0,0 +1,505 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//OTelLog exports entries as OpenTelemetry log records to an OTLP/HTTP collector, using the JSON encoding
+//Each record has the RFC 5424 level mapped to an OpenTelemetry severity number, the message as its body
+//and the fields and map[string]interface{} arguments as attributes
+//Records are batched like ESLog, sent once SetBatchSize are buffered, every SetFlushInterval and on Flush or Close
+type OTelLog struct {
+	LogBase
+	endpoint      string
+	headers       http.Header
+	service       string
+	client        *http.Client
+	batchSize     int
+	flushInterval time.Duration
+	retries       int
+	minBackoff    time.Duration
+	maxBackoff    time.Duration
+
+	mu      sync.Mutex
+	records []otlpRecord
+	//sendMu serializes requests so that batches are exported in order
+	sendMu sync.Mutex
+	done   chan struct{}
+	//full wakes the periodic flush when a batch is full so that Log does not wait for the export
+	full chan struct{}
+	wg   sync.WaitGroup
+}
+
+//Defaults for OTelLog, matching the OpenTelemetry batch log record processor
+const (
+	defaultOTelBatchSize     = 512
+	defaultOTelFlushInterval = time.Second
+	defaultOTelRetries       = 3
+)
+
+//otelScope is the instrumentation scope name sent with every batch
+const otelScope = "github.com/owtorg/logger"
+
+//SetEndpoint sets the base URL of the collector's OTLP/HTTP receiver, e.g. "http://localhost:4318"
+//Records are posted to the /v1/logs path below it
+func (s *OTelLog) SetEndpoint(url string) {
+	s.endpoint = strings.TrimSuffix(url, "/")
+}
+
+//SetHeader adds a header sent with every request, e.g. an API key required by the collector
+func (s *OTelLog) SetHeader(key, value string) {
+	if s.headers == nil {
+		s.headers = make(http.Header)
+	}
+	s.headers.Set(key, value)
+}
+
+//SetServiceName sets the service.name resource attribute, the program name by default
+func (s *OTelLog) SetServiceName(name string) {
+	s.service = name
+}
+
+//SetClient sets the HTTP client used for requests, a client with a 10 second timeout is used when none is set
+func (s *OTelLog) SetClient(c *http.Client) {
+	s.client = c
+}
+
+//SetBatchSize sets how many records are buffered before they are sent, 512 by default
+func (s *OTelLog) SetBatchSize(n int) {
+	s.batchSize = n
+}
+
+//SetFlushInterval sets how often buffered records are sent, 1 second by default
+func (s *OTelLog) SetFlushInterval(d time.Duration) {
+	s.flushInterval = d
+}
+
+//SetRetries sets how many times a failed request is retried before the batch is dropped, 3 by default,
+//a negative value disables retries
+func (s *OTelLog) SetRetries(n int) {
+	s.retries = n
+}
+
+//SetBackoff sets the first and the longest delay between retries
+func (s *OTelLog) SetBackoff(min, max time.Duration) {
+	s.minBackoff = min
+	s.maxBackoff = max
+}
+
+//Init expects input to be a list of func(s *OTelLog), typically used to call SetEndpoint, and then starts the periodic flush
+func (s *OTelLog) Init() error {
+	for _, fn := range s.initializers {
+		switch funct := fn.(type) {
+		case func(s *OTelLog):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *OTelLog) or func(s Logger)")
+		}
+	}
+	if s.endpoint == "" {
+		return errors.New("OTLP endpoint is not set, SetEndpoint must be called")
+	}
+	if s.service == "" {
+		s.service = filepath.Base(os.Args[0])
+	}
+	interval := s.flushInterval
+	if interval <= 0 {
+		interval = defaultOTelFlushInterval
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done == nil {
+		s.done = make(chan struct{})
+		s.full = make(chan struct{}, 1)
+		s.wg.Add(1)
+		go s.flushEvery(interval, s.done, s.full)
+	}
+	return nil
+}
+
+//flushEvery sends buffered records every d and whenever a batch fills up until done is closed
+func (s *OTelLog) flushEvery(d time.Duration, done, full chan struct{}) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.Flush()
+		case <-full:
+			s.Flush()
+		case <-done:
+			return
+		}
+	}
+}
+
+//Flush sends any buffered records, returning the error if they could not be exported
+func (s *OTelLog) Flush() error {
+	s.mu.Lock()
+	records := s.take()
+	s.mu.Unlock()
+	return s.post(records)
+}
+
+//Close stops the periodic flush and sends any buffered records
+func (s *OTelLog) Close() error {
+	s.mu.Lock()
+	done := s.done
+	s.done = nil
+	s.mu.Unlock()
+	if done != nil {
+		close(done)
+		s.wg.Wait()
+	}
+	return s.Flush()
+}
+
+//take returns the buffered records and empties the buffer, it must be called with mu held
+func (s *OTelLog) take() []otlpRecord {
+	records := s.records
+	s.records = nil
+	return records
+}
+
+//post exports records, retrying with backoff, and reports a persistent failure to the OnError handler
+func (s *OTelLog) post(records []otlpRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	body, err := json.Marshal(otlpRequest(s.service, records))
+	if err != nil {
+		s.handleError(err)
+		return err
+	}
+	retries := s.retries
+	if retries < 0 {
+		retries = 0
+	} else if retries == 0 {
+		retries = defaultOTelRetries
+	}
+	err = retry(retries, s.minBackoff, s.maxBackoff, func() error {
+		return s.export(body)
+	})
+	if err == nil {
+		return nil
+	}
+	err = fmt.Errorf("exporting %d log records failed after %d attempts: %w", len(records), retries+1, err)
+	s.handleError(err)
+	return err
+}
+
+//export makes a single OTLP/HTTP request
+func (s *OTelLog) export(body []byte) error {
+	client := s.client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	req, err := http.NewRequest(http.MethodPost, s.endpoint+"/v1/logs", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range s.headers {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	ioutil.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("OTLP export returned %s", resp.Status)
+	}
+	return nil
+}
+
+//otlpRecord is a LogRecord in the OTLP JSON encoding
+type otlpRecord struct {
+	TimeUnixNano         string          `json:"timeUnixNano"`
+	ObservedTimeUnixNano string          `json:"observedTimeUnixNano"`
+	SeverityNumber       int             `json:"severityNumber"`
+	SeverityText         string          `json:"severityText"`
+	Body                 otlpAnyValue    `json:"body"`
+	Attributes           []otlpAttribute `json:"attributes,omitempty"`
+}
+
+//otlpAttribute is a KeyValue in the OTLP JSON encoding
+type otlpAttribute struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+//otlpAnyValue is an AnyValue in the OTLP JSON encoding, it holds exactly one of its keys
+type otlpAnyValue map[string]interface{}
+
+//otlpRequest wraps records in an ExportLogsServiceRequest for service
+func otlpRequest(service string, records []otlpRecord) map[string]interface{} {
+	return map[string]interface{}{
+		"resourceLogs": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{
+				"attributes": []otlpAttribute{{Key: "service.name", Value: otlpValue(service)}},
+			},
+			"scopeLogs": []interface{}{map[string]interface{}{
+				"scope":      map[string]interface{}{"name": otelScope},
+				"logRecords": records,
+			}},
+		}},
+	}
+}
+
+//otelSeverities maps the RFC 5424 severities to OpenTelemetry severity numbers
+//Notice, Critical and Emergency use the second step of the INFO, ERROR and FATAL ranges
+var otelSeverities = [...]int{
+	LevelEmergency: 22,
+	LevelAlert:     21,
+	LevelCritical:  18,
+	LevelError:     17,
+	LevelWarning:   13,
+	LevelNotice:    10,
+	LevelInfo:      9,
+	LevelDebug:     5,
+}
+
+//otelSeverity returns the OpenTelemetry severity number for level, custom levels use the nearest RFC 5424 severity
+//and unknown levels are sent as INFO
+func otelSeverity(level string) int {
+	sev, err := ParseLevel(level)
+	if err != nil {
+		return otelSeverities[LevelInfo]
+	}
+	return otelSeverities[sev.standard()]
+}
+
+//otlpEntry builds the record for an entry
+//Map arguments override the logger's fields, the remaining arguments are joined into the body
+func otlpEntry(level string, t time.Time, fields map[string]interface{}, v []interface{}) otlpRecord {
+	attrs := make(map[string]interface{}, len(fields))
+	for k, val := range fields {
+		attrs[k] = val
+	}
+	for k, val := range errorFields(v) {
+		attrs[k] = val
+	}
+	msg := make([]interface{}, 0, len(v))
+	for _, arg := range v {
+		if m, ok := arg.(map[string]interface{}); ok {
+			for k, val := range m {
+				attrs[k] = val
+			}
+			continue
+		}
+		msg = append(msg, arg)
+	}
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	r := otlpRecord{
+		TimeUnixNano:         strconv.FormatInt(t.UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(t.UnixNano(), 10),
+		SeverityNumber:       otelSeverity(level),
+		SeverityText:         level,
+		Body:                 otlpValue(joinArgs(msg)),
+	}
+	for _, k := range keys {
+		r.Attributes = append(r.Attributes, otlpAttribute{Key: k, Value: otlpValue(attrs[k])})
+	}
+	return r
+}
+
+//otlpValue converts a value to an AnyValue, values without an OTLP equivalent are sent as their fmt.Sprint text
+func otlpValue(v interface{}) otlpAnyValue {
+	switch val := v.(type) {
+	case string:
+		return otlpAnyValue{"stringValue": val}
+	case bool:
+		return otlpAnyValue{"boolValue": val}
+	case int:
+		return otlpAnyValue{"intValue": strconv.FormatInt(int64(val), 10)}
+	case int8:
+		return otlpAnyValue{"intValue": strconv.FormatInt(int64(val), 10)}
+	case int16:
+		return otlpAnyValue{"intValue": strconv.FormatInt(int64(val), 10)}
+	case int32:
+		return otlpAnyValue{"intValue": strconv.FormatInt(int64(val), 10)}
+	case int64:
+		return otlpAnyValue{"intValue": strconv.FormatInt(val, 10)}
+	case uint8:
+		return otlpAnyValue{"intValue": strconv.FormatUint(uint64(val), 10)}
+	case uint16:
+		return otlpAnyValue{"intValue": strconv.FormatUint(uint64(val), 10)}
+	case uint32:
+		return otlpAnyValue{"intValue": strconv.FormatUint(uint64(val), 10)}
+	case float32:
+		return otlpAnyValue{"doubleValue": float64(val)}
+	case float64:
+		return otlpAnyValue{"doubleValue": val}
+	case []string:
+		values := make([]otlpAnyValue, len(val))
+		for i, item := range val {
+			values[i] = otlpValue(item)
+		}
+		return otlpAnyValue{"arrayValue": map[string]interface{}{"values": values}}
+	case []interface{}:
+		values := make([]otlpAnyValue, len(val))
+		for i, item := range val {
+			values[i] = otlpValue(item)
+		}
+		return otlpAnyValue{"arrayValue": map[string]interface{}{"values": values}}
+	case fieldGroup:
+		return otlpValue(map[string]interface{}(val))
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values := make([]otlpAttribute, len(keys))
+		for i, k := range keys {
+			values[i] = otlpAttribute{Key: k, Value: otlpValue(val[k])}
+		}
+		return otlpAnyValue{"kvlistValue": map[string]interface{}{"values": values}}
+	case time.Time:
+		return otlpAnyValue{"stringValue": val.Format(time.RFC3339Nano)}
+	case error:
+		return otlpAnyValue{"stringValue": errorText(val)}
+	}
+	return otlpAnyValue{"stringValue": fmt.Sprint(v)}
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *OTelLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *OTelLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *OTelLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *OTelLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *OTelLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *OTelLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
+func (s *OTelLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *OTelLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *OTelLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *OTelLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *OTelLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *OTelLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *OTelLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *OTelLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *OTelLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *OTelLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *OTelLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *OTelLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *OTelLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *OTelLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *OTelLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *OTelLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *OTelLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+
+//Log buffers the record, the batch is sent in the background once it is full
+func (s *OTelLog) Log(level string, v ...interface{}) {
+	if !s.shouldLog(level) {
+		return
+	}
+	fields, args := s.prepare(level, v)
+	r := otlpEntry(level, s.now(), fields, args)
+
+	size := s.batchSize
+	if size <= 0 {
+		size = defaultOTelBatchSize
+	}
+	s.mu.Lock()
+	s.records = append(s.records, r)
+	if len(s.records) < size {
+		s.mu.Unlock()
+		return
+	}
+	if s.done != nil {
+		//The periodic flush exports the batch so the caller does not wait for the request and its retries
+		select {
+		case s.full <- struct{}{}:
+		default:
+		}
+		s.mu.Unlock()
+		return
+	}
+	//Without Init, or after Close, there is nothing to hand the batch to
+	records := s.take()
+	s.mu.Unlock()
+	s.post(records)
+}
@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+//otlpCollector records the log records posted to /v1/logs
+type otlpCollector struct {
+	mu       sync.Mutex
+	requests []otlpExport
+	apiKeys  []string
+	failures int
+}
+
+//otlpExport is the part of an ExportLogsServiceRequest the tests look at
+type otlpExport struct {
+	ResourceLogs []struct {
+		Resource struct {
+			Attributes []otlpAttribute `json:"attributes"`
+		} `json:"resource"`
+		ScopeLogs []struct {
+			LogRecords []otlpRecord `json:"logRecords"`
+		} `json:"scopeLogs"`
+	} `json:"resourceLogs"`
+}
+
+func (c *otlpCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if r.Method != http.MethodPost || r.URL.Path != "/v1/logs" || r.Header.Get("Content-Type") != "application/json" {
+		http.NotFound(w, r)
+		return
+	}
+	if c.failures > 0 {
+		c.failures--
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	var req otlpExport
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.requests = append(c.requests, req)
+	c.apiKeys = append(c.apiKeys, r.Header.Get("X-Api-Key"))
+	w.Write([]byte(`{}`))
+}
+
+//records returns the records of every request in order
+func (c *otlpCollector) records() []otlpRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var records []otlpRecord
+	for _, req := range c.requests {
+		for _, rl := range req.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				records = append(records, sl.LogRecords...)
+			}
+		}
+	}
+	return records
+}
+
+func TestOTelLog(t *testing.T) {
+	oc := &otlpCollector{failures: 1}
+	srv := httptest.NewServer(oc)
+	defer srv.Close()
+
+	ol := new(OTelLog)
+	ol.OnInit(func(s *OTelLog) {
+		s.SetEndpoint(srv.URL + "/")
+		s.SetServiceName("checkout")
+		s.SetHeader("X-Api-Key", "secret")
+		s.SetBatchSize(2)
+		s.SetBackoff(time.Millisecond, time.Millisecond)
+	})
+	if err := ol.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	at := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	ol.SetClock(newFakeClock(at))
+
+	ol.Error("payment declined", map[string]interface{}{"order": 42, "tags": []string{"a", "b"}})
+	if len(oc.records()) != 0 {
+		t.Error("expected the record to be buffered")
+	}
+	//The second record fills the batch, which is exported in the background, the first attempt fails and is retried
+	ol.Debug("retrying", errors.New("timeout"))
+	deadline := time.Now().Add(5 * time.Second)
+	for len(oc.records()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	ol.Log("custom level", "custom")
+	if err := ol.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records := oc.records()
+	oc.mu.Lock()
+	defer oc.mu.Unlock()
+	if len(records) != 3 || len(oc.requests) != 2 {
+		t.Fatal("expected 3 records in 2 requests, got", len(records), len(oc.requests))
+	}
+	if oc.apiKeys[0] != "secret" {
+		t.Error("expected the header to be sent")
+	}
+	attrs := oc.requests[0].ResourceLogs[0].Resource.Attributes
+	if len(attrs) != 1 || attrs[0].Key != "service.name" || attrs[0].Value["stringValue"] != "checkout" {
+		t.Error("unexpected resource attributes", attrs)
+	}
+
+	first := records[0]
+	if first.SeverityNumber != 17 || first.SeverityText != "Error" || first.Body["stringValue"] != "payment declined" {
+		t.Error("unexpected record", first)
+	}
+	if first.TimeUnixNano != "1709296200000000000" {
+		t.Error("unexpected time", first.TimeUnixNano)
+	}
+	if len(first.Attributes) != 2 || first.Attributes[0].Key != "order" || first.Attributes[0].Value["intValue"] != "42" ||
+		first.Attributes[1].Key != "tags" || first.Attributes[1].Value["arrayValue"] == nil {
+		t.Error("unexpected attributes", first.Attributes)
+	}
+	if records[1].SeverityNumber != 5 || records[1].Body["stringValue"] != "retrying timeout" || records[1].Attributes[0].Key != "error" {
+		t.Error("unexpected record", records[1])
+	}
+	if records[2].SeverityNumber != 9 || records[2].SeverityText != "custom level" {
+		t.Error("unexpected record", records[2])
+	}
+}
+
+func TestOTelLogRetriesDisabled(t *testing.T) {
+	oc := &otlpCollector{failures: 1}
+	srv := httptest.NewServer(oc)
+	defer srv.Close()
+
+	ol := new(OTelLog)
+	ol.SetEndpoint(srv.URL)
+	ol.SetRetries(-1)
+	ol.OnError(func(error) {})
+	if err := ol.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer ol.Close()
+	ol.Info("This is a message")
+	if err := ol.Flush(); err == nil {
+		t.Error("expected the flush to fail without retries")
+	}
+	if records := oc.records(); len(records) != 0 {
+		t.Error("expected no retry, got", records)
+	}
+}
+
+func TestOTelLogFullBatchDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	oc := new(otlpCollector)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		oc.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	ol := new(OTelLog)
+	ol.SetEndpoint(srv.URL)
+	ol.SetBatchSize(1)
+	if err := ol.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	logged := make(chan struct{})
+	go func() {
+		ol.Info("first")
+		ol.Info("second")
+		close(logged)
+	}()
+	select {
+	case <-logged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Log to return while the export is in flight")
+	}
+	close(release)
+	if err := ol.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if records := oc.records(); len(records) != 2 {
+		t.Error("expected both records to be exported, got", records)
+	}
+}
+
+func TestOTelSeverity(t *testing.T) {
+	expected := map[string]int{
+		"Emergency": 22, "Alert": 21, "Critical": 18, "Error": 17,
+		"Warning": 13, "Notice": 10, "Info": 9, "Debug": 5, "custom level": 9,
+	}
+	for level, want := range expected {
+		if got := otelSeverity(level); got != want {
+			t.Error("unexpected severity number for", level, got)
+		}
+	}
+}
+
+func TestOTelLogInitWithoutEndpoint(t *testing.T) {
+	if err := new(OTelLog).Init(); err == nil {
+		t.Error("expected an error without an endpoint")
+	}
+}
@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+//RateLimitLog wraps a Logger and drops entries beyond a rate, using a token bucket
+//The number of dropped entries is reported as a Warning before the next entry that gets through, and on Flush or Close
+type RateLimitLog struct {
+	mu         sync.Mutex
+	logger     Logger
+	rate       float64
+	burst      float64
+	tokens     float64
+	last       time.Time
+	suppressed int
+	quiet      bool
+	clock      Clock
+}
+
+//NewRateLimitLog returns a RateLimitLog allowing up to perInterval entries every interval through to wrapped
+func NewRateLimitLog(wrapped Logger, perInterval int, interval time.Duration) *RateLimitLog {
+	return &RateLimitLog{
+		logger: wrapped,
+		rate:   float64(perInterval) / interval.Seconds(),
+		burst:  float64(perInterval),
+		tokens: float64(perInterval),
+	}
+}
+
+//SetClock sets the clock tokens are refilled by
+func (s *RateLimitLog) SetClock(c Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = c
+}
+
+//SetSummary enables or disables the "N messages suppressed" warning, it is enabled by default
+func (s *RateLimitLog) SetSummary(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.quiet = !enabled
+}
+
+//allow takes a token if one is available, returning the number of entries suppressed since the last one allowed
+func (s *RateLimitLog) allow() (ok bool, suppressed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := clockNow(s.clock)
+	if !s.last.IsZero() {
+		s.tokens += now.Sub(s.last).Seconds() * s.rate
+		if s.tokens > s.burst {
+			s.tokens = s.burst
+		}
+	}
+	s.last = now
+	if s.tokens < 1 {
+		s.suppressed++
+		return false, 0
+	}
+	s.tokens--
+	return true, s.takeSuppressed()
+}
+
+//takeSuppressed returns and resets the suppressed count, it must be called with mu held
+func (s *RateLimitLog) takeSuppressed() int {
+	n := s.suppressed
+	s.suppressed = 0
+	if s.quiet {
+		return 0
+	}
+	return n
+}
+
+//summarize reports n suppressed entries to the wrapped logger
+func (s *RateLimitLog) summarize(n int) {
+	if n > 0 {
+		s.logger.Log("Warning", fmt.Sprintf("... %d messages suppressed", n))
+	}
+}
+
+//Flush reports any suppressed entries and flushes the wrapped logger
+func (s *RateLimitLog) Flush() error {
+	s.mu.Lock()
+	n := s.takeSuppressed()
+	s.mu.Unlock()
+	s.summarize(n)
+	if fc, ok := s.logger.(FlushCloser); ok {
+		return fc.Flush()
+	}
+	return nil
+}
+
+//Close reports any suppressed entries and closes the wrapped logger
+func (s *RateLimitLog) Close() error {
+	s.mu.Lock()
+	n := s.takeSuppressed()
+	s.mu.Unlock()
+	s.summarize(n)
+	if c, ok := s.logger.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+//Enabled reports whether the wrapped logger would write an entry at level
+//Entries that would be dropped by the rate limit are still reported as enabled
+func (s *RateLimitLog) Enabled(level string) bool {
+	return enabled(s.logger, level)
+}
+
+//Init initializes the wrapped logger
+func (s *RateLimitLog) Init() error {
+	return s.logger.Init()
+}
+
+//OnInit registers initializers on the wrapped logger
+func (s *RateLimitLog) OnInit(f ...interface{}) {
+	s.logger.OnInit(f...)
+}
+
+//OnInitLogger registers an initializer on the wrapped logger, it is called with the wrapped logger
+func (s *RateLimitLog) OnInitLogger(f func(s Logger)) {
+	s.OnInit(f)
+}
+
+func (s *RateLimitLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *RateLimitLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *RateLimitLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *RateLimitLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *RateLimitLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *RateLimitLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *RateLimitLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *RateLimitLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *RateLimitLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *RateLimitLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *RateLimitLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *RateLimitLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *RateLimitLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *RateLimitLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *RateLimitLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *RateLimitLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *RateLimitLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+func (s *RateLimitLog) Log(level string, v ...interface{}) {
+	ok, suppressed := s.allow()
+	if !ok {
+		return
+	}
+	s.summarize(suppressed)
+	s.logger.Log(level, v...)
+}
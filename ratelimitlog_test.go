@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimitLog(t *testing.T) {
+	ml := new(MemoryLog)
+	rl := NewRateLimitLog(ml, 10, time.Second)
+	clock := newFakeClock(time.Date(2017, 6, 29, 12, 0, 0, 0, time.UTC))
+	rl.SetClock(clock)
+
+	for i := 0; i < 1000; i++ {
+		rl.Error("flood", i)
+	}
+	if n := len(ml.Entries()); n != 10 {
+		t.Fatal("expected 10 entries to pass, got", n)
+	}
+
+	//After a tenth of a second one more token is available and the suppressed count is reported first
+	clock.Advance(100 * time.Millisecond)
+	rl.Error("flood", 1000)
+	entries := ml.Entries()
+	if len(entries) != 12 {
+		t.Fatal("expected a suppression notice and one more entry, got", len(entries))
+	}
+	if entries[10].Level != "Warning" || entries[10].Args[0] != "... 990 messages suppressed" {
+		t.Error("unexpected notice", entries[10])
+	}
+	if entries[11].Args[1] != 1000 {
+		t.Error("unexpected entry", entries[11])
+	}
+
+	//Close reports anything suppressed since
+	rl.Error("flood")
+	rl.Close()
+	if last, _ := ml.LastEntry(); last.Args[0] != "... 1 messages suppressed" {
+		t.Error("expected Close to report the suppressed entry", last)
+	}
+}
+
+func TestRateLimitLogConcurrent(t *testing.T) {
+	ml := new(MemoryLog)
+	rl := NewRateLimitLog(ml, 10, time.Hour)
+	rl.SetSummary(false)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				rl.Info(i)
+			}
+		}()
+	}
+	wg.Wait()
+	rl.Flush()
+	if n := len(ml.Entries()); n != 10 {
+		t.Error("expected exactly 10 entries and no notice, got", n)
+	}
+}
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+)
+
+//redacted replaces the value of any redacted field and any match of the redact pattern
+const redacted = "[REDACTED]"
+
+//SetRedactKeys replaces the value of any field whose key matches one of keys, ignoring case, with [REDACTED]
+//This covers fields added with WithFields and map[string]interface{} arguments
+func (l *LogBase) SetRedactKeys(keys ...string) {
+	l.redactKeys = make(map[string]bool, len(keys))
+	for _, k := range keys {
+		l.redactKeys[strings.ToLower(k)] = true
+	}
+}
+
+//SetRedactPattern masks every match of re in the message with [REDACTED], nil disables it
+func (l *LogBase) SetRedactPattern(re *regexp.Regexp) {
+	l.redactPattern = re
+}
+
+//redactFields returns fields with the values of redacted keys replaced, including keys inside groups
+//fields itself is never modified, a copy is returned when anything is redacted
+func (l *LogBase) redactFields(fields map[string]interface{}) map[string]interface{} {
+	if len(l.redactKeys) == 0 {
+		return fields
+	}
+	if out, ok := l.redactMap(fields); ok {
+		return out
+	}
+	return fields
+}
+
+//redactMap returns a redacted copy of fields, ok is false when nothing needed redacting
+func (l *LogBase) redactMap(fields map[string]interface{}) (out map[string]interface{}, ok bool) {
+	for k, v := range fields {
+		var value interface{} = redacted
+		if !l.redactKeys[strings.ToLower(k)] {
+			g, isGroup := v.(fieldGroup)
+			if !isGroup {
+				continue
+			}
+			rg, changed := l.redactMap(g)
+			if !changed {
+				continue
+			}
+			value = fieldGroup(rg)
+		}
+		if out == nil {
+			out = make(map[string]interface{}, len(fields))
+			for k, v := range fields {
+				out[k] = v
+			}
+		}
+		out[k] = value
+	}
+	return out, out != nil
+}
+
+//redactMaps returns v with its map arguments redacted by key, the other arguments are kept as they are
+func (l *LogBase) redactMaps(v []interface{}) []interface{} {
+	if len(l.redactKeys) == 0 {
+		return v
+	}
+	args := make([]interface{}, len(v))
+	for i, arg := range v {
+		if m, ok := arg.(map[string]interface{}); ok {
+			arg = l.redactFields(m)
+		}
+		args[i] = arg
+	}
+	return args
+}
+
+//redactArgs redacts map arguments by key and, when a pattern is set, joins the remaining
+//arguments into a single masked message placed ahead of the maps
+func (l *LogBase) redactArgs(v []interface{}) []interface{} {
+	if len(l.redactKeys) == 0 && l.redactPattern == nil {
+		return v
+	}
+	args := make([]interface{}, 0, len(v))
+	var msg []interface{}
+	for _, arg := range v {
+		if m, ok := arg.(map[string]interface{}); ok {
+			args = append(args, l.redactFields(m))
+			continue
+		}
+		if l.redactPattern != nil {
+			msg = append(msg, arg)
+			continue
+		}
+		args = append(args, arg)
+	}
+	if len(msg) > 0 {
+		masked := l.redactPattern.ReplaceAllString(joinArgs(msg), redacted)
+		args = append([]interface{}{masked}, args...)
+	}
+	return args
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestRedactKeys(t *testing.T) {
+	stdLog := new(StdLog)
+	stdLog.SetRedactKeys("password", "Token")
+	child := stdLog.WithFields(map[string]interface{}{"TOKEN": "abc123", "user": "bob"})
+
+	output := captureOutput(func() {
+		child.Info("login", map[string]interface{}{"Password": "hunter2", "attempt": 1})
+	})
+	testOutput(output, "Info [login map[Password:[REDACTED] attempt:1]] TOKEN=[REDACTED] user=bob\n", t)
+
+	//JSON and logfmt output see the redacted values too
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	jl.SetRedactKeys("password")
+	secret := map[string]interface{}{"password": "hunter2"}
+	jl.Info("login", secret)
+	m := decodeJSONLine(buf.Bytes(), t)
+	if m["password"] != "[REDACTED]" {
+		t.Error("expected the password to be redacted", m)
+	}
+	if secret["password"] != "hunter2" {
+		t.Error("the caller's map must not be modified")
+	}
+}
+
+func TestRedactPattern(t *testing.T) {
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	wl.SetRedactPattern(regexp.MustCompile(`\b\d{4}-\d{4}-\d{4}-\d{4}\b`))
+
+	wl.Warning("card", "1234-5678-9012-3456", "declined")
+	testOutput(buf.String(), "Warning card [REDACTED] declined\n", t)
+
+	ml := new(MemoryLog)
+	ml.SetRedactPattern(regexp.MustCompile(`secret`))
+	ml.Error("the secret is out")
+	if e, _ := ml.LastEntry(); e.Args[0] != "the [REDACTED] is out" {
+		t.Error("expected the message to be masked before it is recorded", e.Args)
+	}
+}
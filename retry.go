@@ -0,0 +1,24 @@
+package logger
+
+import "time"
+
+//retry calls f until it succeeds or has been retried retries times, sleeping between attempts
+//The delay starts at min and doubles up to max, the last error is returned
+func retry(retries int, min, max time.Duration, f func() error) error {
+	if min <= 0 {
+		min = defaultMinBackoff
+	}
+	if max <= 0 {
+		max = defaultMaxBackoff
+	}
+	backoff := min
+	err := f()
+	for attempt := 0; err != nil && attempt < retries; attempt++ {
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > max {
+			backoff = max
+		}
+		err = f()
+	}
+	return err
+}
@@ -0,0 +1,216 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+//defaultRingCapacity is the number of entries a RingLog keeps when no capacity is set
+const defaultRingCapacity = 1000
+
+//RingLog keeps the most recent entries in memory, overwriting the oldest once it is full
+//e.g. keep the last lines to dump from a debugging endpoint or after a crash
+type RingLog struct {
+	LogBase
+	mu       sync.Mutex
+	capacity int
+	entries  []Entry
+	next     int
+	full     bool
+}
+
+//SetCapacity sets the number of entries kept, 1000 by default
+//Changing the capacity discards the entries recorded so far
+func (s *RingLog) SetCapacity(capacity int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.capacity = capacity
+	s.entries = nil
+	s.next = 0
+	s.full = false
+}
+
+//Init expects input to be a list of func(s *RingLog) which will be called on initialization
+func (s *RingLog) Init() error {
+	for _, fn := range s.initializers {
+		switch funct := fn.(type) {
+		case func(s *RingLog):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *RingLog) or func(s Logger)")
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.capacity < 0 {
+		return fmt.Errorf("ring capacity must not be negative, got %d", s.capacity)
+	}
+	return nil
+}
+
+//Dump returns a copy of the retained entries, oldest first
+func (s *RingLog) Dump() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dump()
+}
+
+//Drain returns the retained entries, oldest first, and discards them in one step so no entry is lost or returned twice
+func (s *RingLog) Drain() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := s.dump()
+	s.entries = nil
+	s.next = 0
+	s.full = false
+	return entries
+}
+
+//dump copies the retained entries, it must be called with mu held
+func (s *RingLog) dump() []Entry {
+	if !s.full {
+		entries := make([]Entry, s.next)
+		copy(entries, s.entries[:s.next])
+		return entries
+	}
+	entries := make([]Entry, 0, len(s.entries))
+	entries = append(entries, s.entries[s.next:]...)
+	return append(entries, s.entries[:s.next]...)
+}
+
+//Reset discards the retained entries
+func (s *RingLog) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = nil
+	s.next = 0
+	s.full = false
+}
+
+//Replay logs the retained entries to dst oldest first, keeping their levels and fields
+//The entries are kept, see ReplayDrain to take them
+func (s *RingLog) Replay(dst Logger) {
+	for _, e := range s.Dump() {
+		ReplayEntry(dst, e)
+	}
+}
+
+//ReplayDrain is Replay for the entries taken by Drain, entries recorded while it runs are kept for the next call
+func (s *RingLog) ReplayDrain(dst Logger) {
+	for _, e := range s.Drain() {
+		ReplayEntry(dst, e)
+	}
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *RingLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *RingLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *RingLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *RingLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *RingLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *RingLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
+func (s *RingLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *RingLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *RingLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *RingLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *RingLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *RingLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *RingLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *RingLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *RingLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *RingLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *RingLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *RingLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *RingLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *RingLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *RingLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *RingLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *RingLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+func (s *RingLog) Log(level string, v ...interface{}) {
+	if !s.shouldLog(level) {
+		return
+	}
+	fields, args := s.prepare(level, v)
+	e := s.newEntry(level, fields, args)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	capacity := s.capacity
+	if capacity == 0 {
+		capacity = defaultRingCapacity
+	}
+	if capacity < 0 {
+		return
+	}
+	if s.entries == nil {
+		s.entries = make([]Entry, capacity)
+	}
+	s.entries[s.next] = e
+	s.next++
+	if s.next == len(s.entries) {
+		s.next = 0
+		s.full = true
+	}
+}
@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestRingLogWraparound(t *testing.T) {
+	rl := new(RingLog)
+	rl.SetCapacity(3)
+	if err := rl.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if entries := rl.Dump(); len(entries) != 0 {
+		t.Error("expected no entries, got", entries)
+	}
+
+	rl.Info(0)
+	rl.Info(1)
+	if entries := rl.Dump(); len(entries) != 2 || entries[0].Args[0] != 0 || entries[1].Args[0] != 1 {
+		t.Error("unexpected entries before wrapping", entries)
+	}
+
+	for i := 2; i < 8; i++ {
+		rl.Warning(i)
+	}
+	entries := rl.Dump()
+	if len(entries) != 3 {
+		t.Fatal("expected 3 entries, got", len(entries))
+	}
+	for i, e := range entries {
+		if e.Level != "Warning" || e.Args[0] != i+5 {
+			t.Error("unexpected entry", i, e)
+		}
+	}
+
+	rl.Reset()
+	rl.Debug("after reset")
+	if entries := rl.Dump(); len(entries) != 1 || entries[0].Args[0] != "after reset" {
+		t.Error("unexpected entries after reset", entries)
+	}
+}
+
+func TestRingLogDefaultCapacity(t *testing.T) {
+	rl := new(RingLog)
+	for i := 0; i < defaultRingCapacity+10; i++ {
+		rl.Info(i)
+	}
+	entries := rl.Dump()
+	if len(entries) != defaultRingCapacity || entries[0].Args[0] != 10 {
+		t.Error("expected the oldest entries to be overwritten", len(entries), entries[0])
+	}
+
+	rl.SetCapacity(-1)
+	if err := rl.Init(); err == nil {
+		t.Error("expected an error for a negative capacity")
+	}
+}
+
+func TestRingLogConcurrent(t *testing.T) {
+	rl := new(RingLog)
+	rl.SetCapacity(50)
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				rl.Info(i)
+				rl.Dump()
+			}
+		}()
+	}
+	wg.Wait()
+	if entries := rl.Dump(); len(entries) != 50 {
+		t.Error("expected 50 entries, got", len(entries))
+	}
+}
+
+func TestRingLogReplay(t *testing.T) {
+	rl := new(RingLog)
+	rl.SetCapacity(2)
+	rl.Info("a")
+	rl.Error("b")
+	rl.Debug("c")
+
+	dst := new(MemoryLog)
+	rl.Replay(dst)
+	expected := []Entry{
+		{Level: "Error", Args: []interface{}{"b"}},
+		{Level: "Debug", Args: []interface{}{"c"}},
+	}
+	if !reflect.DeepEqual(levelArgs(dst.Entries()...), expected) {
+		t.Error("unexpected replayed entries", dst.Entries())
+	}
+}
+
+func TestRingLogDrain(t *testing.T) {
+	rl := new(RingLog)
+	rl.SetCapacity(2)
+	rl.Info("a")
+	rl.Error("b")
+	rl.Debug("c")
+
+	dst := new(MemoryLog)
+	rl.ReplayDrain(dst)
+	if len(rl.Dump()) != 0 || len(dst.Entries()) != 2 {
+		t.Error("expected ReplayDrain to move the retained entries", rl.Dump(), dst.Entries())
+	}
+	rl.Info("d")
+	if entries := rl.Drain(); len(entries) != 1 || entries[0].Args[0] != "d" {
+		t.Error("unexpected entries after draining", entries)
+	}
+}
@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"sync"
+)
+
+//SampleLog wraps a Logger and keeps only a sample of the entries at each level
+//A rate of n keeps one entry in n, levels without a rate (or with a rate of 1 or less) are never sampled
+type SampleLog struct {
+	mu     sync.Mutex
+	logger Logger
+	rates  map[string]int
+	counts map[string]int
+	rand   *rand.Rand
+}
+
+//NewSampleLog returns a SampleLog passing one in rates[level] entries through to wrapped
+//e.g. map[string]int{"Debug": 100} keeps every 100th Debug entry and everything else
+//Level names are matched case insensitively, so "debug" and "Debug" share a rate
+func NewSampleLog(wrapped Logger, rates map[string]int) *SampleLog {
+	r := make(map[string]int, len(rates))
+	for level, n := range rates {
+		r[strings.ToLower(level)] = n
+	}
+	return &SampleLog{
+		logger: wrapped,
+		rates:  r,
+		counts: make(map[string]int),
+	}
+}
+
+//SetRandom keeps each entry with a probability of 1/rate drawn from r instead of keeping every nth entry
+//Pass rand.New(rand.NewSource(seed)) for a repeatable sample, or nil to go back to counting
+func (s *SampleLog) SetRandom(r *rand.Rand) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rand = r
+}
+
+//keep reports whether the next entry at level should be passed on
+func (s *SampleLog) keep(level string) bool {
+	level = strings.ToLower(level)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := s.rates[level]
+	if n <= 1 {
+		return true
+	}
+	if s.rand != nil {
+		return s.rand.Intn(n) == 0
+	}
+	s.counts[level]++
+	if s.counts[level] < n {
+		return false
+	}
+	s.counts[level] = 0
+	return true
+}
+
+//Flush flushes the wrapped logger
+func (s *SampleLog) Flush() error {
+	if fc, ok := s.logger.(FlushCloser); ok {
+		return fc.Flush()
+	}
+	return nil
+}
+
+//Close closes the wrapped logger
+func (s *SampleLog) Close() error {
+	if c, ok := s.logger.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+//Enabled reports whether the wrapped logger would write an entry at level
+//Entries that would be sampled out are still reported as enabled
+func (s *SampleLog) Enabled(level string) bool {
+	return enabled(s.logger, level)
+}
+
+//Init initializes the wrapped logger
+func (s *SampleLog) Init() error {
+	return s.logger.Init()
+}
+
+//OnInit registers initializers on the wrapped logger
+func (s *SampleLog) OnInit(f ...interface{}) {
+	s.logger.OnInit(f...)
+}
+
+//OnInitLogger registers an initializer on the wrapped logger, it is called with the wrapped logger
+func (s *SampleLog) OnInitLogger(f func(s Logger)) {
+	s.OnInit(f)
+}
+
+func (s *SampleLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *SampleLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *SampleLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *SampleLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *SampleLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *SampleLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *SampleLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *SampleLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *SampleLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *SampleLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *SampleLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *SampleLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *SampleLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *SampleLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *SampleLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *SampleLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *SampleLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+func (s *SampleLog) Log(level string, v ...interface{}) {
+	if s.keep(level) {
+		s.logger.Log(level, v...)
+	}
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSampleLog(t *testing.T) {
+	ml := new(MemoryLog)
+	sl := NewSampleLog(ml, map[string]int{"Debug": 10})
+
+	for i := 1; i <= 100; i++ {
+		sl.Debug(i)
+		sl.Error(i)
+	}
+	var debug, errs []interface{}
+	for _, e := range ml.Entries() {
+		switch e.Level {
+		case "Debug":
+			debug = append(debug, e.Args[0])
+		case "Error":
+			errs = append(errs, e.Args[0])
+		}
+	}
+	if len(debug) != 10 {
+		t.Fatal("expected 10 Debug entries, got", len(debug))
+	}
+	for i, v := range debug {
+		if v != (i+1)*10 {
+			t.Errorf("expected Debug entry %d to be call %d, got %v", i, (i+1)*10, v)
+		}
+	}
+	if len(errs) != 100 {
+		t.Error("expected every Error entry, got", len(errs))
+	}
+}
+
+func TestSampleLogRandom(t *testing.T) {
+	run := func() []interface{} {
+		ml := new(MemoryLog)
+		sl := NewSampleLog(ml, map[string]int{"Debug": 4})
+		sl.SetRandom(rand.New(rand.NewSource(1)))
+		for i := 0; i < 1000; i++ {
+			sl.Debug(i)
+			sl.Error(i)
+		}
+		var debug []interface{}
+		errs := 0
+		for _, e := range ml.Entries() {
+			if e.Level == "Debug" {
+				debug = append(debug, e.Args[0])
+			} else {
+				errs++
+			}
+		}
+		if errs != 1000 {
+			t.Error("expected every Error entry, got", errs)
+		}
+		return debug
+	}
+	first, second := run(), run()
+	if len(first) < 150 || len(first) > 350 {
+		t.Error("expected roughly a quarter of Debug entries, got", len(first))
+	}
+	if len(first) != len(second) {
+		t.Fatal("expected the same seed to give the same sample")
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatal("expected the same seed to give the same sample")
+		}
+	}
+}
+
+func TestSampleLogLevelCase(t *testing.T) {
+	ml := new(MemoryLog)
+	sl := NewSampleLog(ml, map[string]int{"debug": 4})
+	for i := 0; i < 4; i++ {
+		sl.Debug("typed")
+		sl.Log("DEBUG", "named")
+	}
+	//Both spellings share the one rate and count
+	if n := len(ml.Entries()); n != 2 {
+		t.Error("expected every 4th Debug entry whatever its case, got", n)
+	}
+}
@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+//Severity is the numeric RFC 5424 severity of a level, lower values are more severe
+//Levels added with RegisterLevel may use values outside of the RFC 5424 range
+type Severity int
+
+//The eight RFC 5424 severities
+const (
+	LevelEmergency Severity = iota
+	LevelAlert
+	LevelCritical
+	LevelError
+	LevelWarning
+	LevelNotice
+	LevelInfo
+	LevelDebug
+)
+
+//severityNames holds the level names in severity order
+var severityNames = []string{"Emergency", "Alert", "Critical", "Error", "Warning", "Notice", "Info", "Debug"}
+
+var (
+	customMu sync.RWMutex
+	//customLevels holds the levels added with RegisterLevel, keyed by lower case name
+	customLevels = map[string]customLevel{}
+)
+
+//customLevel is a level added with RegisterLevel
+type customLevel struct {
+	name     string
+	severity Severity
+}
+
+//RegisterLevel adds a custom level so that SetLevel, Enabled and Log order it against the built in levels
+//e.g. RegisterLevel("Trace", 8) sorts below Debug and RegisterLevel("Fatal", -1) above Emergency
+//Names are case insensitive, registering a name that is already a level is an error, see OverrideLevel
+func RegisterLevel(name string, severity int) error {
+	customMu.Lock()
+	defer customMu.Unlock()
+	if _, err := parseBuiltinLevel(name); err == nil {
+		return fmt.Errorf("level %q is already defined", name)
+	}
+	if _, ok := customLevels[strings.ToLower(name)]; ok {
+		return fmt.Errorf("level %q is already registered", name)
+	}
+	customLevels[strings.ToLower(name)] = customLevel{name, Severity(severity)}
+	return nil
+}
+
+//OverrideLevel registers a level like RegisterLevel, replacing the severity of any level already using the name
+//Built in levels can be overridden too, e.g. OverrideLevel("Notice", int(LevelInfo))
+func OverrideLevel(name string, severity int) {
+	customMu.Lock()
+	defer customMu.Unlock()
+	customLevels[strings.ToLower(name)] = customLevel{name, Severity(severity)}
+}
+
+//String returns the level name, e.g. "Warning"
+//Severities of registered levels return the registered name
+func (s Severity) String() string {
+	if s >= 0 && int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+	customMu.RLock()
+	defer customMu.RUnlock()
+	for _, l := range customLevels {
+		if l.severity == s {
+			return l.name
+		}
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+//standard returns the nearest RFC 5424 severity, for outputs that only understand those eight
+func (s Severity) standard() Severity {
+	if s < LevelEmergency {
+		return LevelEmergency
+	}
+	if s > LevelDebug {
+		return LevelDebug
+	}
+	return s
+}
+
+//ParseLevel returns the severity for a level name, ignoring case
+//Levels added with RegisterLevel or OverrideLevel are recognised too
+func ParseLevel(level string) (Severity, error) {
+	customMu.RLock()
+	l, ok := customLevels[strings.ToLower(level)]
+	customMu.RUnlock()
+	if ok {
+		return l.severity, nil
+	}
+	return parseBuiltinLevel(level)
+}
+
+//parseBuiltinLevel returns the severity for one of the eight RFC 5424 level names
+func parseBuiltinLevel(level string) (Severity, error) {
+	for i, name := range severityNames {
+		if strings.EqualFold(name, level) {
+			return Severity(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown level %q", level)
+}
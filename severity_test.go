@@ -0,0 +1,208 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	for _, level := range []string{"warning", "WARNING", "Warning", "wArNiNg"} {
+		sev, err := ParseLevel(level)
+		if err != nil || sev != LevelWarning {
+			t.Error("expected LevelWarning for", level, "got", sev, err)
+		}
+	}
+
+	if _, err := ParseLevel("custom level"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+	if _, err := ParseLevel(""); err == nil {
+		t.Error("expected an error for an empty level")
+	}
+}
+
+func TestSeverityString(t *testing.T) {
+	for sev := LevelEmergency; sev <= LevelDebug; sev++ {
+		parsed, err := ParseLevel(sev.String())
+		if err != nil || parsed != sev {
+			t.Error("round trip failed for", sev, parsed, err)
+		}
+	}
+	if LevelError.String() != "Error" {
+		t.Error("unexpected name", LevelError.String())
+	}
+	if Severity(42).String() != "Severity(42)" {
+		t.Error("unexpected name for an unknown severity", Severity(42).String())
+	}
+}
+
+func TestSetLevelCaseInsensitive(t *testing.T) {
+	stdLog := new(StdLog)
+	stdLog.SetLevel("ERROR")
+	output := captureOutput(func() {
+		stdLog.Log("warning", "This is a message")
+		stdLog.Log("error", "This is a message")
+	})
+	testOutput(output, "error This is a message\n", t)
+}
+
+func TestGetLevel(t *testing.T) {
+	ml := new(MemoryLog)
+	if _, ok := ml.GetLevel(); ok {
+		t.Error("expected no threshold by default")
+	}
+	ml.SetLevel("notice")
+	if sev, ok := ml.GetLevel(); !ok || sev != LevelNotice {
+		t.Error("unexpected level", sev, ok)
+	}
+	ml.SetLevelAtomic(LevelEmergency)
+	if sev, ok := ml.GetLevel(); !ok || sev != LevelEmergency {
+		t.Error("unexpected level", sev, ok)
+	}
+	ml.Alert("hidden")
+	ml.Emergency("shown")
+	if len(ml.Entries()) != 1 {
+		t.Error("unexpected entries", ml.Entries())
+	}
+	ml.SetLevel("")
+	if _, ok := ml.GetLevel(); ok {
+		t.Error("expected an empty level to remove the threshold")
+	}
+}
+
+func TestGetLevelNegativeSeverity(t *testing.T) {
+	defer unregisterLevel("Fatal")
+	if err := RegisterLevel("Fatal", int(LevelEmergency)-1); err != nil {
+		t.Fatal(err)
+	}
+	ml := new(MemoryLog)
+	ml.SetLevel("Fatal")
+	if sev, ok := ml.GetLevel(); !ok || sev != LevelEmergency-1 {
+		t.Fatal("unexpected level", sev, ok)
+	}
+	if ml.Enabled("Info") || ml.Enabled("Emergency") || !ml.Enabled("Fatal") {
+		t.Error("expected only Fatal to pass the threshold")
+	}
+	ml.Info("hidden")
+	ml.Emergency("hidden")
+	ml.Log("Fatal", "shown")
+	if len(ml.Entries()) != 1 || ml.Entries()[0].Level != "Fatal" {
+		t.Error("unexpected entries", ml.Entries())
+	}
+}
+
+func TestSetLevelConcurrent(t *testing.T) {
+	ml := new(MemoryLog)
+	ml.SetLevelAtomic(LevelError)
+	done := make(chan struct{})
+	flipped := make(chan struct{})
+	go func() {
+		defer close(flipped)
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if i%2 == 0 {
+				ml.SetLevel("Debug")
+			} else {
+				ml.SetLevelAtomic(LevelError)
+			}
+		}
+	}()
+	for i := 0; i < 2000; i++ {
+		ml.Error("always written")
+		ml.Info("sometimes written")
+		ml.Enabled("Warning")
+	}
+	close(done)
+	<-flipped
+
+	errors := 0
+	for _, e := range ml.Entries() {
+		if e.Level == "Error" {
+			errors++
+		}
+	}
+	if errors != 2000 {
+		t.Error("expected every Error entry to be written, got", errors)
+	}
+}
+
+//unregisterLevel removes a level added by a test
+func unregisterLevel(name string) {
+	customMu.Lock()
+	defer customMu.Unlock()
+	delete(customLevels, strings.ToLower(name))
+}
+
+func TestRegisterLevel(t *testing.T) {
+	defer unregisterLevel("TRACE")
+	defer unregisterLevel("FATAL")
+	if err := RegisterLevel("TRACE", int(LevelDebug)+1); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterLevel("FATAL", int(LevelEmergency)-1); err != nil {
+		t.Fatal(err)
+	}
+	if sev, err := ParseLevel("trace"); err != nil || sev != LevelDebug+1 {
+		t.Error("expected trace to parse below Debug, got", sev, err)
+	}
+	if s := Severity(-1).String(); s != "FATAL" {
+		t.Error("expected the registered name, got", s)
+	}
+
+	ml := new(MemoryLog)
+	ml.SetLevel("Debug")
+	ml.Log("TRACE", "filtered")
+	ml.Log("FATAL", "kept")
+	ml.Debug("kept")
+	if ml.Enabled("TRACE") || !ml.Enabled("FATAL") {
+		t.Error("expected TRACE to be disabled and FATAL enabled at Debug")
+	}
+	entries := ml.Entries()
+	if len(entries) != 2 || entries[0].Level != "FATAL" || entries[1].Level != "Debug" {
+		t.Error("unexpected entries", entries)
+	}
+
+	ml.Reset()
+	ml.SetLevel("trace")
+	ml.Log("TRACE", "kept")
+	if len(ml.Entries()) != 1 {
+		t.Error("expected TRACE to be written at a TRACE threshold")
+	}
+
+	//Custom severities outside of RFC 5424 are clamped for colours
+	if colorize("TRACE") != colorGray+"TRACE"+colorReset {
+		t.Error("unexpected colour for TRACE", colorize("TRACE"))
+	}
+}
+
+func TestRegisterLevelCollision(t *testing.T) {
+	defer unregisterLevel("Trace")
+	defer unregisterLevel("Notice")
+	if err := RegisterLevel("debug", 9); err == nil {
+		t.Error("expected an error registering a built in level")
+	}
+	if err := RegisterLevel("Trace", 8); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterLevel("TRACE", 9); err == nil {
+		t.Error("expected an error registering a level twice")
+	}
+
+	OverrideLevel("Trace", 9)
+	if sev, _ := ParseLevel("Trace"); sev != 9 {
+		t.Error("expected the override to replace the severity, got", sev)
+	}
+	OverrideLevel("Notice", int(LevelInfo))
+	ml := new(MemoryLog)
+	ml.SetLevel("Warning")
+	ml.Notice("filtered")
+	ml.SetLevel("Info")
+	ml.Notice("kept")
+	if len(ml.Entries()) != 1 {
+		t.Error("expected Notice to be treated as Info", ml.Entries())
+	}
+}
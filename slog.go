@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+//SlogHandler is a log/slog Handler that forwards records to a Logger
+//slog levels are mapped to the nearest RFC 5424 level and attributes become structured fields,
+//passed through WithFields when the Logger is a FieldLogger or as a map argument otherwise
+type SlogHandler struct {
+	logger Logger
+	fields map[string]interface{}
+	prefix string
+}
+
+//NewSlogHandler returns a handler writing to l, use it with slog.New
+func NewSlogHandler(l Logger) *SlogHandler {
+	return &SlogHandler{logger: l}
+}
+
+//Enabled reports whether the wrapped Logger would write an entry at the RFC 5424 level level maps to
+//so slog skips building records that would be dropped, loggers that do not implement EnabledLogger are assumed to
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return enabled(h.logger, slogLevel(level))
+}
+
+//Handle forwards the record to the wrapped Logger
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := make(map[string]interface{}, len(h.fields)+r.NumAttrs())
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(fields, h.prefix, a)
+		return true
+	})
+
+	level := slogLevel(r.Level)
+	if len(fields) == 0 {
+		h.logger.Log(level, r.Message)
+		return nil
+	}
+	if fl, ok := h.logger.(FieldLogger); ok {
+		fl.WithFields(fields).Log(level, r.Message)
+		return nil
+	}
+	h.logger.Log(level, r.Message, fields)
+	return nil
+}
+
+//WithAttrs returns a handler that adds attrs to every record
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := h.clone()
+	for _, a := range attrs {
+		addAttr(c.fields, c.prefix, a)
+	}
+	return c
+}
+
+//WithGroup returns a handler that prefixes the keys of later attributes with name and a dot
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := h.clone()
+	c.prefix += name + "."
+	return c
+}
+
+func (h *SlogHandler) clone() *SlogHandler {
+	c := &SlogHandler{logger: h.logger, prefix: h.prefix, fields: make(map[string]interface{}, len(h.fields))}
+	for k, v := range h.fields {
+		c.fields[k] = v
+	}
+	return c
+}
+
+//addAttr adds a to fields under prefix, flattening groups into dotted keys
+func addAttr(fields map[string]interface{}, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		//Inline groups with an empty key have their attributes added at the current level
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			addAttr(fields, prefix, ga)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+	fields[prefix+a.Key] = v.Any()
+}
+
+//slogLevel maps a slog level to the nearest RFC 5424 level name
+func slogLevel(l slog.Level) string {
+	switch {
+	case l < slog.LevelInfo:
+		return "Debug"
+	case l < slog.LevelWarn:
+		return "Info"
+	case l < slog.LevelError:
+		return "Warning"
+	case l < slog.LevelError+4:
+		return "Error"
+	default:
+		return "Critical"
+	}
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	ml := new(MemoryLog)
+	sl := slog.New(NewSlogHandler(ml))
+
+	sl.Warn("disk almost full", "free", 42, slog.Group("disk", "name", "sda"))
+	e, _ := ml.LastEntry()
+	if e.Level != "Warning" || e.Args[0] != "disk almost full" {
+		t.Error("unexpected entry", e)
+	}
+	expected := map[string]interface{}{"free": int64(42), "disk.name": "sda"}
+	if !reflect.DeepEqual(e.Fields, expected) {
+		t.Error("unexpected attributes", e.Fields)
+	}
+
+	//Attributes and groups accumulate
+	child := sl.With("request_id", "abc").WithGroup("http").With("method", "GET")
+	child.Error("request failed", "status", 500)
+	e, _ = ml.LastEntry()
+	expected = map[string]interface{}{"request_id": "abc", "http.method": "GET", "http.status": int64(500)}
+	if e.Level != "Error" || !reflect.DeepEqual(e.Fields, expected) {
+		t.Error("unexpected entry", e)
+	}
+
+	//No attributes, no fields
+	sl.Info("plain")
+	e, _ = ml.LastEntry()
+	if !reflect.DeepEqual(levelArgs(e)[0], Entry{Level: "Info", Args: []interface{}{"plain"}}) {
+		t.Error("unexpected entry", e)
+	}
+}
+
+func TestSlogHandlerFieldLogger(t *testing.T) {
+	sl := slog.New(NewSlogHandler(new(StdLog)))
+	output := captureOutput(func() {
+		sl.Debug("This is a message", "user", "bob")
+	})
+	testOutput(output, "Debug This is a message user=bob\n", t)
+}
+
+func TestSlogLevels(t *testing.T) {
+	cases := map[slog.Level]string{
+		slog.LevelDebug - 4: "Debug",
+		slog.LevelDebug:     "Debug",
+		slog.LevelInfo:      "Info",
+		slog.LevelInfo + 2:  "Info",
+		slog.LevelWarn:      "Warning",
+		slog.LevelError:     "Error",
+		slog.LevelError + 4: "Critical",
+	}
+	for l, expected := range cases {
+		if got := slogLevel(l); got != expected {
+			t.Error("unexpected level for", l, got)
+		}
+	}
+}
+
+func TestSlogHandlerEnabled(t *testing.T) {
+	ml := new(MemoryLog)
+	ml.SetLevel("warning")
+	h := NewSlogHandler(ml)
+	ctx := context.Background()
+	if h.Enabled(ctx, slog.LevelInfo) || h.Enabled(ctx, slog.LevelDebug) {
+		t.Error("expected levels below the threshold to be disabled")
+	}
+	if !h.Enabled(ctx, slog.LevelWarn) || !h.Enabled(ctx, slog.LevelError) {
+		t.Error("expected levels at the threshold to be enabled")
+	}
+
+	//The attributes of a disabled record are never resolved
+	resolved := false
+	sl := slog.New(h)
+	sl.Debug("hidden", "value", lazyValue(func() { resolved = true }))
+	if resolved || len(ml.Entries()) != 0 {
+		t.Error("expected the disabled record to be skipped")
+	}
+
+	//Loggers without a threshold receive every level
+	if !NewSlogHandler(new(StdLog)).Enabled(ctx, slog.LevelDebug-4) {
+		t.Error("expected a logger without a threshold to be enabled")
+	}
+}
+
+//lazyValue is a slog.LogValuer that records when it is resolved
+type lazyValue func()
+
+func (f lazyValue) LogValue() slog.Value {
+	f()
+	return slog.StringValue("resolved")
+}
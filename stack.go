@@ -1,98 +1,432 @@
 package logger
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+//ErrSinkTimeout is reported for a logger that did not return within the stack's sink timeout
+var ErrSinkTimeout = errors.New("sink timed out")
 
 //Stack - A stack is a group of loggers that also implements the logger interface
 //loggers will be called in the order they are added
 type Stack struct {
 	LogBase
-	loggers []interface{}
+	loggers  []Logger
+	failFast bool
+	parallel bool
+
+	sinkTimeout time.Duration
+}
+
+//SetParallel makes each log call run on every logger concurrently, returning once they have all finished
+//Calls made one after another still reach each logger in order, and a panic in one logger is recovered
+//and reported through OnError so the others are unaffected
+//LogAll always runs sequentially so that fail fast can stop it
+func (s *Stack) SetParallel(parallel bool) {
+	s.parallel = parallel
 }
 
-//Add a logger to the stack
-func (s *Stack) Add(l ...interface{}) {
-	//Init the loggers and then add them to the stack
-	for _, v := range l {
-		lg := v.(Logger)
-		lg.Init()
+//SetSinkTimeout limits how long a parallel stack waits for each logger on every call, 0 (the default) waits for all of them
+//A logger that has not returned in time is left to finish in the background and a *MemberError wrapping ErrSinkTimeout
+//is reported through OnError, so a hung sink can not hold up the rest of the stack
+//The abandoned call may still reach its logger after later ones, it has no effect in sequential mode
+func (s *Stack) SetSinkTimeout(d time.Duration) {
+	s.sinkTimeout = d
+}
+
+//each records an entry at level and calls f for every logger, concurrently when the stack is parallel
+func (s *Stack) each(level string, f func(lg Logger)) {
+	if !s.shouldLog(level) {
+		return
+	}
+	s.tracker().record(level)
+	if !s.parallel {
+		for _, lg := range s.loggers {
+			f(lg)
+		}
+		return
 	}
-	s.loggers = append(s.loggers, l...)
+	type result struct {
+		index     int
+		recovered interface{}
+	}
+	loggers := s.loggers
+	//Buffered so that a call finishing after the timeout does not block, it touches nothing else of the stack
+	done := make(chan result, len(loggers))
+	for i, lg := range loggers {
+		go func(i int, lg Logger) {
+			defer func() {
+				done <- result{index: i, recovered: recover()}
+			}()
+			f(lg)
+		}(i, lg)
+	}
+	var timeout <-chan time.Time
+	if s.sinkTimeout > 0 {
+		timer := time.NewTimer(s.sinkTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var errs []error
+	finished := make([]bool, len(loggers))
+wait:
+	for range loggers {
+		select {
+		case r := <-done:
+			finished[r.index] = true
+			if r.recovered != nil {
+				errs = append(errs, fmt.Errorf("logger %d panicked: %v", r.index, r.recovered))
+			}
+		case <-timeout:
+			for i, ok := range finished {
+				if !ok {
+					errs = append(errs, &MemberError{Index: i, Err: ErrSinkTimeout})
+				}
+			}
+			break wait
+		}
+	}
+	for _, err := range errs {
+		s.handleError(err)
+	}
+}
+
+//SetFailFast makes LogAll stop at the first logger that fails instead of calling every logger
+func (s *Stack) SetFailFast(failFast bool) {
+	s.failFast = failFast
+}
+
+//LogAll logs to every logger in the stack and returns the errors reported by loggers implementing ErrorLogger
+//Each error is a *MemberError holding the position of the logger that failed
+//Loggers that can not report errors are always treated as successful
+func (s *Stack) LogAll(level string, v ...interface{}) []error {
+	if !s.shouldLog(level) {
+		return nil
+	}
+	s.tracker().record(level)
+	var errs []error
+	for i, lg := range s.loggers {
+		el, ok := lg.(ErrorLogger)
+		if !ok {
+			lg.Log(level, v...)
+			continue
+		}
+		if err := el.TryLog(level, v...); err != nil {
+			errs = append(errs, &MemberError{Index: i, Err: err})
+			if s.failFast {
+				break
+			}
+		}
+	}
+	return errs
+}
+
+//TryLog logs like LogAll and returns the failures as a *MultiError, or nil when every logger succeeded
+func (s *Stack) TryLog(level string, v ...interface{}) error {
+	var errs MultiError
+	for _, err := range s.LogAll(level, v...) {
+		errs.Errors = append(errs.Errors, err.(*MemberError))
+	}
+	return errs.err()
+}
+
+//LogTo logs only to the loggers at the given positions in the stack, in the order given, e.g. to send an alert
+//at a higher level to the alerting loggers while the rest receive it through Log
+//Every position is checked first, an out of range one is an error and nothing is logged, repeated positions are logged to once
+func (s *Stack) LogTo(targets []int, level string, v ...interface{}) error {
+	for _, i := range targets {
+		if i < 0 || i >= len(s.loggers) {
+			return fmt.Errorf("logger index %d out of range [0,%d)", i, len(s.loggers))
+		}
+	}
+	if !s.shouldLog(level) {
+		return nil
+	}
+	s.tracker().record(level)
+	done := make(map[int]bool, len(targets))
+	for _, i := range targets {
+		if done[i] {
+			continue
+		}
+		done[i] = true
+		s.loggers[i].Log(level, v...)
+	}
+	return nil
+}
+
+//Add loggers to the stack
+//Each logger is initialized first, loggers that fail to initialize are not added and their errors are returned
+func (s *Stack) Add(l ...Logger) error {
+	loggers, err := initLoggers(l)
+	s.loggers = append(s.loggers, loggers...)
+	return err
+}
+
+//Insert adds l at index, moving the loggers from index onwards one place later so l is called before them
+//index may be Len() to add l at the end, the logger is initialized first and is not inserted if that fails
+func (s *Stack) Insert(index int, l Logger) error {
+	if index < 0 || index > len(s.loggers) {
+		return fmt.Errorf("logger index %d out of range [0,%d]", index, len(s.loggers))
+	}
+	if err := l.Init(); err != nil {
+		return err
+	}
+	s.loggers = append(s.loggers, nil)
+	copy(s.loggers[index+1:], s.loggers[index:])
+	s.loggers[index] = l
+	return nil
+}
+
+//AddAny adds loggers held as interface{} values to the stack
+//Values that do not implement Logger are skipped with an error naming their position and type
+//Deprecated: use Add, which checks the loggers at compile time
+func (s *Stack) AddAny(l ...interface{}) error {
+	loggers, err := toLoggers(l)
+	if aerr := s.Add(loggers...); aerr != nil {
+		err = errors.Join(err, aerr)
+	}
+	return err
+}
+
+//NewTee returns a stack that writes every entry to each of loggers, in order
+//e.g. NewTee(fileLog, new(FmtLog)) writes to a file and the console
+//Each logger is initialized, loggers that fail to initialize are left out and the error is available from Err
+func NewTee(loggers ...Logger) *Stack {
+	s := new(Stack)
+	if err := s.Add(loggers...); err != nil {
+		s.handleError(err)
+	}
+	return s
+}
+
+//AddFiltered adds a logger that only receives entries at minLevel or more severe
+//The logger is wrapped in a LevelLog so its own configuration is left untouched
+func (s *Stack) AddFiltered(l Logger, minLevel string) error {
+	return s.Add(NewLevelLog(l, minLevel))
 }
 
 //Set the loggers in the stack
-func (s *Stack) Set(Loggers []interface{}) {
-	s.loggers = Loggers
-	//Initialize all the loggers
-	for _, v := range s.loggers {
-		lg := v.(Logger)
-		lg.Init()
+//Each logger is initialized first, loggers that fail to initialize are left out and their errors are returned
+func (s *Stack) Set(l ...Logger) error {
+	loggers, err := initLoggers(l)
+	s.loggers = loggers
+	return err
+}
+
+//SetAny sets the loggers in the stack from interface{} values
+//Values that do not implement Logger are left out with an error naming their position and type
+//Deprecated: use Set, which checks the loggers at compile time
+func (s *Stack) SetAny(l []interface{}) error {
+	loggers, err := toLoggers(l)
+	if serr := s.Set(loggers...); serr != nil {
+		err = errors.Join(err, serr)
+	}
+	return err
+}
+
+//toLoggers returns the values in l that implement Logger, with an error for each one that does not
+func toLoggers(l []interface{}) ([]Logger, error) {
+	loggers := make([]Logger, 0, len(l))
+	var errs []error
+	for i, v := range l {
+		lg, ok := v.(Logger)
+		if !ok {
+			errs = append(errs, fmt.Errorf("argument %d (%T) does not implement Logger", i, v))
+			continue
+		}
+		loggers = append(loggers, lg)
+	}
+	return loggers, errors.Join(errs...)
+}
+
+//initLoggers initializes each logger, returning the ones that succeeded and a *MultiError indexed by position in l
+func initLoggers(l []Logger) ([]Logger, error) {
+	loggers := make([]Logger, 0, len(l))
+	var errs MultiError
+	for i, lg := range l {
+		if err := lg.Init(); err != nil {
+			errs.add(i, err)
+			continue
+		}
+		loggers = append(loggers, lg)
+	}
+	return loggers, errs.err()
+}
+
+//Flush flushes every logger in the stack that implements FlushCloser, the failures are returned as a *MultiError
+func (s *Stack) Flush() error {
+	var errs MultiError
+	for i, lg := range s.loggers {
+		if fc, ok := lg.(FlushCloser); ok {
+			errs.add(i, fc.Flush())
+		}
+	}
+	return errs.err()
+}
+
+//Close closes every logger in the stack that implements io.Closer, the failures are returned as a *MultiError
+//Every logger is closed even if an earlier one fails
+func (s *Stack) Close() error {
+	var errs MultiError
+	for i, lg := range s.loggers {
+		if c, ok := lg.(io.Closer); ok {
+			errs.add(i, c.Close())
+		}
+	}
+	return errs.err()
+}
+
+//Remove the logger at index from the stack, preserving the order of the remaining loggers
+func (s *Stack) Remove(index int) error {
+	if index < 0 || index >= len(s.loggers) {
+		return fmt.Errorf("logger index %d out of range [0,%d)", index, len(s.loggers))
 	}
+	s.loggers = append(s.loggers[:index], s.loggers[index+1:]...)
+	return nil
+}
+
+//RemoveAll removes every logger from the stack
+func (s *Stack) RemoveAll() {
+	s.loggers = nil
+}
+
+//Len returns the number of loggers in the stack
+func (s *Stack) Len() int {
+	return len(s.loggers)
+}
+
+//Enabled reports whether level passes the threshold of the stack and any logger in it would write an entry at level
+func (s *Stack) Enabled(level string) bool {
+	if !s.shouldLog(level) {
+		return false
+	}
+	for _, lg := range s.loggers {
+		if enabled(lg, level) {
+			return true
+		}
+	}
+	return false
 }
 
 //Init - expects input to be a list of func(s *Stack) which will be called on initialization
 func (s *Stack) Init() error {
-	s.loggers = make([]interface{}, 1)
 	for _, fn := range s.initializers {
-		funct, ok := fn.(func(s Logger))
-		if !ok {
-			return errors.New("Init callbacks must have signature func(s Logger)")
+		switch funct := fn.(type) {
+		case func(s *Stack):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *Stack) or func(s Logger)")
 		}
-		funct(s)
 	}
 	return nil
 }
 
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *Stack) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *Stack) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *Stack) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *Stack) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *Stack) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *Stack) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
 func (s *Stack) Emergency(v ...interface{}) {
-	for _, lg := range s.loggers {
-		lg := lg.(Logger)
+	s.each("Emergency", func(lg Logger) {
 		lg.Emergency(v...)
-	}
+	})
 }
 func (s *Stack) Alert(v ...interface{}) {
-	for _, lg := range s.loggers {
-		lg := lg.(Logger)
+	s.each("Alert", func(lg Logger) {
 		lg.Alert(v...)
-	}
+	})
 }
 func (s *Stack) Critical(v ...interface{}) {
-	for _, lg := range s.loggers {
-		lg := lg.(Logger)
+	s.each("Critical", func(lg Logger) {
 		lg.Critical(v...)
-	}
+	})
 }
 func (s *Stack) Error(v ...interface{}) {
-	for _, lg := range s.loggers {
-		lg := lg.(Logger)
+	s.each("Error", func(lg Logger) {
 		lg.Error(v...)
-	}
+	})
 }
 func (s *Stack) Warning(v ...interface{}) {
-	for _, lg := range s.loggers {
-		lg := lg.(Logger)
+	s.each("Warning", func(lg Logger) {
 		lg.Warning(v...)
-	}
+	})
 }
 func (s *Stack) Notice(v ...interface{}) {
-	for _, lg := range s.loggers {
-		lg := lg.(Logger)
+	s.each("Notice", func(lg Logger) {
 		lg.Notice(v...)
-	}
+	})
 }
 func (s *Stack) Info(v ...interface{}) {
-	for _, lg := range s.loggers {
-		lg := lg.(Logger)
+	s.each("Info", func(lg Logger) {
 		lg.Info(v...)
-	}
+	})
 }
 func (s *Stack) Debug(v ...interface{}) {
-	for _, lg := range s.loggers {
-		lg := lg.(Logger)
+	s.each("Debug", func(lg Logger) {
 		lg.Debug(v...)
-	}
+	})
+}
+func (s *Stack) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *Stack) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *Stack) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *Stack) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *Stack) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *Stack) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *Stack) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *Stack) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *Stack) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
 }
 func (s *Stack) Log(level string, v ...interface{}) {
-	for _, lg := range s.loggers {
-		lg := lg.(Logger)
+	s.each(level, func(lg Logger) {
 		lg.Log(level, v...)
-	}
+	})
 }
@@ -0,0 +1,534 @@
+package logger
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+//countLog counts the Info calls it receives
+type countLog struct {
+	StdLog
+	infos int
+}
+
+func (c *countLog) Info(v ...interface{}) {
+	c.infos++
+}
+
+func TestStackRemove(t *testing.T) {
+	first, middle, last := new(countLog), new(countLog), new(countLog)
+	stack := new(Stack)
+	stack.Add(first, middle, last)
+	if stack.Len() != 3 {
+		t.Fatal("expected 3 loggers, got", stack.Len())
+	}
+
+	if err := stack.Remove(1); err != nil {
+		t.Fatal("Remove failed", err)
+	}
+	if stack.Len() != 2 {
+		t.Fatal("expected 2 loggers, got", stack.Len())
+	}
+	stack.Info("This is a message")
+	if first.infos != 1 || middle.infos != 0 || last.infos != 1 {
+		t.Error("unexpected calls", first.infos, middle.infos, last.infos)
+	}
+	if stack.loggers[0] != first || stack.loggers[1] != last {
+		t.Error("order of the remaining loggers was not preserved")
+	}
+
+	if err := stack.Remove(2); err == nil {
+		t.Error("expected an error for an out of range index")
+	}
+	if err := stack.Remove(-1); err == nil {
+		t.Error("expected an error for a negative index")
+	}
+
+	stack.RemoveAll()
+	if stack.Len() != 0 {
+		t.Error("expected an empty stack, got", stack.Len())
+	}
+}
+
+//seqLog appends its name to a shared sequence for every Info call it receives
+type seqLog struct {
+	StdLog
+	name string
+	seq  *[]string
+}
+
+func (l *seqLog) Info(v ...interface{}) {
+	*l.seq = append(*l.seq, l.name)
+}
+
+func TestStackInsert(t *testing.T) {
+	var seq []string
+	named := func(name string) *seqLog {
+		return &seqLog{name: name, seq: &seq}
+	}
+	stack := new(Stack)
+	stack.Add(named("b"), named("d"))
+
+	if err := stack.Insert(0, named("a")); err != nil {
+		t.Fatal("Insert failed", err)
+	}
+	if err := stack.Insert(2, named("c")); err != nil {
+		t.Fatal("Insert failed", err)
+	}
+	if err := stack.Insert(stack.Len(), named("e")); err != nil {
+		t.Fatal("Insert failed", err)
+	}
+	stack.Info("This is a message")
+	if strings.Join(seq, "") != "abcde" {
+		t.Error("unexpected call order", seq)
+	}
+
+	for _, index := range []int{-1, stack.Len() + 1} {
+		if err := stack.Insert(index, named("x")); err == nil {
+			t.Error("expected an error for out of range index", index)
+		}
+	}
+	if err := stack.Insert(0, new(brokenLog)); !errors.Is(err, errBrokenInit) {
+		t.Error("expected the init error to be returned, got", err)
+	}
+	if stack.Len() != 5 {
+		t.Error("expected failed inserts to leave the stack alone, got", stack.Len())
+	}
+}
+
+func TestStackLogTo(t *testing.T) {
+	console, file, pager := new(MemoryLog), new(MemoryLog), new(MemoryLog)
+	stack := new(Stack)
+	stack.Add(console, file, pager)
+
+	stack.Log("Warning", "disk almost full")
+	if err := stack.LogTo([]int{2, 2}, "Alert", "disk almost full"); err != nil {
+		t.Fatal("LogTo failed", err)
+	}
+	if len(console.Entries()) != 1 || len(file.Entries()) != 1 {
+		t.Error("expected the loggers that were not targeted to receive nothing more", console.Entries(), file.Entries())
+	}
+	expected := []Entry{
+		{Level: "Warning", Args: []interface{}{"disk almost full"}},
+		{Level: "Alert", Args: []interface{}{"disk almost full"}},
+	}
+	if !reflect.DeepEqual(levelArgs(pager.Entries()...), expected) {
+		t.Error("unexpected entries", pager.Entries())
+	}
+	if sev := stack.MaxSeverity(); sev != LevelAlert {
+		t.Error("expected the entry to be recorded by the stack, got", sev)
+	}
+
+	for _, targets := range [][]int{{0, 3}, {-1}} {
+		if err := stack.LogTo(targets, "Info", "message"); err == nil {
+			t.Error("expected an error for out of range targets", targets)
+		}
+	}
+	if len(console.Entries()) != 1 {
+		t.Error("expected nothing to be logged when a target is out of range, got", console.Entries())
+	}
+}
+
+var errBrokenInit = errors.New("broken init")
+
+//brokenLog always fails to initialize
+type brokenLog struct {
+	StdLog
+}
+
+func (b *brokenLog) Init() error {
+	return errBrokenInit
+}
+
+func TestStackAddInitError(t *testing.T) {
+	stack := new(Stack)
+	good, broken := new(countLog), new(brokenLog)
+	err := stack.Add(good, broken)
+	if !errors.Is(err, errBrokenInit) {
+		t.Error("expected the init error to be returned, got", err)
+	}
+	if stack.Len() != 1 || stack.loggers[0] != good {
+		t.Error("the broken logger must not be added", stack.loggers)
+	}
+
+	err = stack.Set(broken, good)
+	if !errors.Is(err, errBrokenInit) {
+		t.Error("expected the init error to be returned, got", err)
+	}
+	if stack.Len() != 1 || stack.loggers[0] != good {
+		t.Error("the broken logger must not be set", stack.loggers)
+	}
+
+	if err := stack.Add(new(countLog)); err != nil {
+		t.Error("unexpected error", err)
+	}
+}
+
+func TestStackAddFiltered(t *testing.T) {
+	fl := new(FileLog)
+	fl.OnInit(tlCallback)
+	stack := new(Stack)
+	if err := stack.Add(fl); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(fl.logPath)
+	if err := stack.AddFiltered(new(StdLog), "Warning"); err != nil {
+		t.Fatal(err)
+	}
+
+	output := captureOutput(func() {
+		stack.Debug("This is a debug message")
+		stack.Log("Debug", "This is a debug message")
+		stack.Error("This is an error message")
+	})
+	testOutput(output, "Error This is an error message\n", t)
+
+	fl.Close()
+	b, err := ioutil.ReadFile(fl.logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testOutput(string(b), "Debug This is a debug message\nDebug This is a debug message\nError This is an error message\n", t)
+}
+
+//failLog always fails to write
+type failLog struct {
+	MemoryLog
+}
+
+func (f *failLog) TryLog(level string, v ...interface{}) error {
+	return errors.New("write failed")
+}
+
+func TestStackLogAll(t *testing.T) {
+	first, middle, last := new(MemoryLog), new(failLog), new(MemoryLog)
+	stack := new(Stack)
+	stack.Add(first, middle, last)
+
+	errs := stack.LogAll("Error", "This is a message")
+	if len(errs) != 1 {
+		t.Error("expected one error, got", errs)
+	}
+	if len(first.Entries()) != 1 || len(last.Entries()) != 1 {
+		t.Error("expected every logger to be called without fail fast")
+	}
+
+	first.Reset()
+	last.Reset()
+	stack.SetFailFast(true)
+	errs = stack.LogAll("Error", "This is a message")
+	if len(errs) != 1 {
+		t.Error("expected one error, got", errs)
+	}
+	if len(first.Entries()) != 1 {
+		t.Error("expected the logger before the failure to be called")
+	}
+	if len(last.Entries()) != 0 {
+		t.Error("expected fail fast to skip the logger after the failure")
+	}
+}
+
+func TestStackLogAllWriterLog(t *testing.T) {
+	wl := new(WriterLog)
+	wl.SetFormatter(failFormatter{})
+	stack := new(Stack)
+	stack.Add(wl)
+	if errs := stack.LogAll("Info", "This is a message"); len(errs) != 1 {
+		t.Error("expected the WriterLog error to be reported, got", errs)
+	}
+}
+
+//panicLog panics on every call
+type panicLog struct {
+	MemoryLog
+}
+
+func (p *panicLog) Info(v ...interface{}) {
+	panic("broken logger")
+}
+
+func TestStackParallel(t *testing.T) {
+	members := []*MemoryLog{new(MemoryLog), new(MemoryLog), new(MemoryLog)}
+	stack := new(Stack)
+	for _, m := range members {
+		stack.Add(m)
+	}
+	stack.SetParallel(true)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				stack.Info(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	//Sequential calls from one goroutine stay in order for each logger
+	stack.Debug("first")
+	stack.Debug("second")
+	for _, m := range members {
+		entries := m.Entries()
+		if len(entries) != 102 {
+			t.Error("expected 102 entries, got", len(entries))
+			continue
+		}
+		if entries[100].Args[0] != "first" || entries[101].Args[0] != "second" {
+			t.Error("entries out of order", entries[100:])
+		}
+	}
+}
+
+func TestStackParallelPanic(t *testing.T) {
+	good := new(MemoryLog)
+	stack := new(Stack)
+	stack.Add(new(panicLog), good)
+	stack.SetParallel(true)
+	var handled error
+	stack.OnError(func(err error) {
+		handled = err
+	})
+
+	stack.Info("This is a message")
+	if len(good.Entries()) != 1 {
+		t.Error("expected the healthy logger to receive the entry")
+	}
+	if handled == nil {
+		t.Error("expected the panic to be reported")
+	}
+}
+
+//hungLog blocks each Info call until release is closed
+type hungLog struct {
+	StdLog
+	release chan struct{}
+	calls   int32
+}
+
+func (l *hungLog) Info(v ...interface{}) {
+	atomic.AddInt32(&l.calls, 1)
+	<-l.release
+}
+
+func TestStackSinkTimeout(t *testing.T) {
+	hung := &hungLog{release: make(chan struct{})}
+	defer close(hung.release)
+	good := new(MemoryLog)
+	stack := new(Stack)
+	stack.Add(good, hung)
+	stack.SetParallel(true)
+	stack.SetSinkTimeout(20 * time.Millisecond)
+	var handled []error
+	stack.OnError(func(err error) {
+		handled = append(handled, err)
+	})
+
+	start := time.Now()
+	stack.Info("This is a message")
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatal("expected the stack to give up on the hung logger, took", elapsed)
+	}
+	if len(good.Entries()) != 1 {
+		t.Error("expected the healthy logger to receive the entry")
+	}
+	var me *MemberError
+	if len(handled) != 1 || !errors.As(handled[0], &me) || me.Index != 1 || !errors.Is(handled[0], ErrSinkTimeout) {
+		t.Fatal("expected a timeout for logger 1, got", handled)
+	}
+
+	//The stack keeps working while the abandoned call is still blocked
+	stack.Info("This is another message")
+	if len(good.Entries()) != 2 || len(handled) != 2 || atomic.LoadInt32(&hung.calls) != 2 {
+		t.Error("unexpected state after a second call", len(good.Entries()), handled, hung.calls)
+	}
+}
+
+func TestStackSinkTimeoutNotReached(t *testing.T) {
+	good := new(MemoryLog)
+	stack := new(Stack)
+	stack.Add(good)
+	stack.SetParallel(true)
+	stack.SetSinkTimeout(time.Minute)
+	stack.OnError(func(err error) {
+		t.Error("unexpected error", err)
+	})
+	stack.Info("This is a message")
+	if len(good.Entries()) != 1 {
+		t.Error("expected the logger to receive the entry")
+	}
+}
+
+var (
+	_ FlushCloser = new(FileLog)
+	_ FlushCloser = new(AsyncLog)
+	_ FlushCloser = new(SyncLog)
+	_ FlushCloser = new(Stack)
+)
+
+//closeFailLog fails to close
+type closeFailLog struct {
+	MemoryLog
+}
+
+func (c *closeFailLog) Close() error {
+	return errors.New("close failed")
+}
+
+func TestStackClose(t *testing.T) {
+	fl := new(FileLog)
+	fl.OnInit(tlCallback)
+	stack := new(Stack)
+	if err := stack.Add(fl, new(MemoryLog)); err != nil {
+		t.Fatal(err)
+	}
+	stack.Info("This is a message")
+	f := fl.f
+
+	if err := stack.Close(); err != nil {
+		t.Error("unexpected Close error", err)
+	}
+	//The handle has been released
+	if _, err := f.Write([]byte("x")); err == nil {
+		t.Error("expected writes to the closed handle to fail")
+	}
+	if err := os.Remove(fl.logPath); err != nil {
+		t.Error("could not remove the closed file", err)
+	}
+
+	stack = new(Stack)
+	stack.Add(new(closeFailLog), new(MemoryLog), new(closeFailLog))
+	err := stack.Close()
+	if err == nil || strings.Count(err.Error(), "close failed") != 2 {
+		t.Error("expected both close errors, got", err)
+	}
+}
+
+func TestStackInitEmpty(t *testing.T) {
+	stack := new(Stack)
+	if err := stack.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	if stack.Len() != 0 {
+		t.Error("expected no loggers after Init, got", stack.Len())
+	}
+	output := captureOutput(func() {
+		stack.Info("x")
+		stack.Log("Info", "x")
+	})
+	testOutput(output, "", t)
+
+	//A stack nested in another keeps its members when it is initialized
+	ml := new(MemoryLog)
+	inner := new(Stack)
+	inner.Add(ml)
+	outer := new(Stack)
+	if err := outer.Add(inner); err != nil {
+		t.Fatal(err)
+	}
+	outer.Info("x")
+	if len(ml.Entries()) != 1 {
+		t.Error("expected the nested stack to keep its loggers")
+	}
+}
+
+func TestStackAddAnyNonLogger(t *testing.T) {
+	stack := new(Stack)
+	ml := new(MemoryLog)
+	err := stack.AddAny(ml, struct{}{})
+	if err == nil {
+		t.Fatal("expected an error adding a non-Logger")
+	}
+	if !strings.Contains(err.Error(), "argument 1 (struct {}) does not implement Logger") {
+		t.Error("unexpected error", err)
+	}
+	if stack.Len() != 1 {
+		t.Error("expected only the valid logger to be added, got", stack.Len())
+	}
+	stack.Info("x")
+	if len(ml.Entries()) != 1 {
+		t.Error("expected the valid logger to still receive entries")
+	}
+	if err := stack.SetAny([]interface{}{"not a logger"}); err == nil {
+		t.Error("expected an error setting a non-Logger")
+	}
+	if stack.Len() != 0 {
+		t.Error("expected no loggers after Set, got", stack.Len())
+	}
+}
+
+func TestNewTee(t *testing.T) {
+	ml := new(MemoryLog)
+	fl := new(FileLog)
+	fl.OnInit(tlCallback)
+	tee := NewTee(ml, fl)
+	if err := tee.Err(); err != nil {
+		t.Fatal("unexpected init error", err)
+	}
+	tee.Warning("This is a message")
+	if err := tee.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	last, ok := ml.LastEntry()
+	if !ok || last.Level != "Warning" || last.Args[0] != "This is a message" {
+		t.Error("expected the memory logger to receive the entry, got", last)
+	}
+	b, err := ioutil.ReadFile(fl.logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testOutput(string(b), "Warning This is a message\n", t)
+}
+
+func TestNewTeeInitError(t *testing.T) {
+	bad := new(FileLog)
+	bad.OnInit(func(s *FileLog) {
+		s.logPath = "./test/output/.gitkeep/log"
+	})
+	ml := new(MemoryLog)
+	tee := NewTee(bad, ml)
+	if tee.Err() == nil {
+		t.Error("expected the init error to be reported")
+	}
+	if tee.Len() != 1 {
+		t.Error("expected the failing logger to be left out, got", tee.Len())
+	}
+}
+
+func TestStackTyped(t *testing.T) {
+	first, second := new(MemoryLog), new(MemoryLog)
+	loggers := []Logger{first, second}
+	stack := new(Stack)
+	if err := stack.Set(loggers...); err != nil {
+		t.Fatal(err)
+	}
+	if err := stack.Add(new(NopLog)); err != nil {
+		t.Fatal(err)
+	}
+	stack.Info("This is a message")
+	if len(first.Entries()) != 1 || len(second.Entries()) != 1 {
+		t.Error("expected both loggers to receive the entry")
+	}
+	if stack.Len() != 3 || stack.loggers[0] != first || stack.loggers[1] != second {
+		t.Error("unexpected loggers", stack.loggers)
+	}
+
+	//AddAny reports init errors as well as values that are not loggers
+	err := stack.AddAny(new(brokenLog), 42)
+	if !errors.Is(err, errBrokenInit) || !strings.Contains(err.Error(), "argument 1 (int) does not implement Logger") {
+		t.Error("expected both errors to be returned, got", err)
+	}
+	if stack.Len() != 3 {
+		t.Error("expected nothing to be added, got", stack.Len())
+	}
+}
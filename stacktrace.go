@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+//SetStacktraceLevel attaches the current goroutine's stack to entries at level or more severe
+//The stack is written as a stacktrace field by the structured loggers and as an indented block after text lines
+//Frames inside this package are trimmed so the trace starts at the log call
+//An empty or unknown level disables stack traces, which is the default
+func (l *LogBase) SetStacktraceLevel(level string) {
+	sev, err := ParseLevel(level)
+	l.stackThreshold = sev
+	l.stacktrace = err == nil
+}
+
+//wantsStack reports whether an entry at level should carry a stack trace
+func (l *LogBase) wantsStack(level string) bool {
+	if !l.stacktrace {
+		return false
+	}
+	sev, err := ParseLevel(level)
+	return err == nil && sev <= l.stackThreshold
+}
+
+//stacktrace returns debug.Stack without the frames of runtime/debug and this package
+func stacktrace() string {
+	lines := strings.Split(strings.TrimSuffix(string(debug.Stack()), "\n"), "\n")
+	//The first line is the goroutine header, followed by a function line and a file line per frame
+	trimmed := lines[:1]
+	for i := 1; i+1 < len(lines); i += 2 {
+		file := strings.TrimPrefix(lines[i+1], "\t")
+		if end := strings.LastIndexByte(file, ':'); end >= 0 {
+			file = file[:end]
+		}
+		if internalFrame(file) || strings.HasPrefix(lines[i], "runtime/debug.") {
+			continue
+		}
+		trimmed = append(trimmed, lines[i], lines[i+1])
+	}
+	return strings.Join(trimmed, "\n")
+}
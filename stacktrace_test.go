@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSetStacktraceLevel(t *testing.T) {
+	stdLog := new(StdLog)
+	stdLog.SetStacktraceLevel("Error")
+
+	output := captureOutput(func() {
+		stdLog.Warning("This is a message")
+	})
+	testOutput(output, "Warning This is a message\n", t)
+
+	output = captureOutput(func() {
+		stdLog.Error("This is a message")
+	})
+	lines := strings.Split(output, "\n")
+	if lines[0] != "Error This is a message" {
+		t.Error("unexpected first line", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "\tgoroutine ") {
+		t.Error("expected the trace to follow the line, got", lines[1])
+	}
+	//Frames inside the package are trimmed so the trace starts at the test
+	if !strings.Contains(lines[2], "TestSetStacktraceLevel") {
+		t.Error("expected the first frame to be the test function, got", lines[2])
+	}
+	if strings.Contains(output, "(*StdLog)") || strings.Contains(output, "runtime/debug") {
+		t.Error("expected internal frames to be trimmed", output)
+	}
+
+	stdLog.SetStacktraceLevel("")
+	output = captureOutput(func() {
+		stdLog.Emergency("This is a message")
+	})
+	testOutput(output, "Emergency This is a message\n", t)
+}
+
+func TestSetStacktraceLevelJSON(t *testing.T) {
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	jl.SetStacktraceLevel("Error")
+	jl.Critical("This is a message")
+	entry := decodeJSONLine(buf.Bytes(), t)
+	trace, ok := entry["stacktrace"].(string)
+	if !ok || !strings.Contains(trace, "TestSetStacktraceLevelJSON") {
+		t.Error("expected a stacktrace field naming the test, got", entry)
+	}
+}
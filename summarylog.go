@@ -0,0 +1,208 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//SummaryLog wraps a Logger and, instead of writing each entry, counts the entries at each level and writes a summary
+//e.g. "last 1m0s: 3 Error, 1200 Info" once every interval, on Flush and on Close
+//The summary is written at Info and intervals without any entries are skipped
+type SummaryLog struct {
+	mu       sync.Mutex
+	logger   Logger
+	interval time.Duration
+	clock    Clock
+	start    time.Time
+	counts   map[string]int
+	done     chan struct{}
+}
+
+//NewSummaryLog returns a SummaryLog that writes a summary of the entries logged to it to l every interval
+//Close stops the summaries, a summary is due once the logger's clock has moved interval past the last one
+func NewSummaryLog(l Logger, interval time.Duration) *SummaryLog {
+	s := &SummaryLog{logger: l, interval: interval, counts: make(map[string]int)}
+	s.start = s.now()
+	if interval > 0 {
+		s.done = make(chan struct{})
+		go s.summarizeEvery(interval, s.done)
+	}
+	return s
+}
+
+//SetClock sets the clock intervals are measured with and restarts the current interval
+//With a fixed clock a summary is only written when the clock is advanced past the interval
+func (s *SummaryLog) SetClock(c Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = c
+	s.start = s.now()
+}
+
+func (s *SummaryLog) now() time.Time {
+	return clockNow(s.clock)
+}
+
+//summarizeEvery checks for a due summary every d until Close is called
+func (s *SummaryLog) summarizeEvery(d time.Duration, done chan struct{}) {
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			s.summarizeDue()
+			s.mu.Unlock()
+		case <-done:
+			return
+		}
+	}
+}
+
+//summarizeDue writes the summary if the interval has passed, it must be called with mu held
+func (s *SummaryLog) summarizeDue() {
+	if s.interval > 0 && !s.now().Before(s.start.Add(s.interval)) {
+		s.summarize()
+	}
+}
+
+//summarize writes the counts since the last summary and starts a new interval, it must be called with mu held
+func (s *SummaryLog) summarize() {
+	now := s.now()
+	elapsed := now.Sub(s.start)
+	s.start = now
+	if len(s.counts) == 0 {
+		return
+	}
+	s.logger.Log("Info", summaryLine(elapsed, s.counts))
+	s.counts = make(map[string]int)
+}
+
+//summaryLine renders counts most severe level first, levels that can not be parsed follow in name order
+func summaryLine(elapsed time.Duration, counts map[string]int) string {
+	levels := make([]string, 0, len(counts))
+	for level := range counts {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		si, erri := ParseLevel(levels[i])
+		sj, errj := ParseLevel(levels[j])
+		switch {
+		case erri == nil && errj == nil && si != sj:
+			return si < sj
+		case (erri == nil) != (errj == nil):
+			return erri == nil
+		}
+		return levels[i] < levels[j]
+	})
+	parts := make([]string, len(levels))
+	for i, level := range levels {
+		parts[i] = fmt.Sprintf("%d %s", counts[level], level)
+	}
+	return fmt.Sprintf("last %v: %s", elapsed.Round(time.Second), strings.Join(parts, ", "))
+}
+
+//Flush writes the summary so far and flushes the wrapped logger
+func (s *SummaryLog) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.summarize()
+	if fc, ok := s.logger.(FlushCloser); ok {
+		return fc.Flush()
+	}
+	return nil
+}
+
+//Close stops the periodic summaries, writes the summary so far and closes the wrapped logger
+func (s *SummaryLog) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		close(s.done)
+		s.done = nil
+	}
+	s.summarize()
+	if c, ok := s.logger.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+//Init initializes the wrapped logger
+func (s *SummaryLog) Init() error {
+	return s.logger.Init()
+}
+
+//OnInit registers initializers on the wrapped logger
+func (s *SummaryLog) OnInit(f ...interface{}) {
+	s.logger.OnInit(f...)
+}
+
+//OnInitLogger registers an initializer on the wrapped logger, it is called with the wrapped logger
+func (s *SummaryLog) OnInitLogger(f func(s Logger)) {
+	s.OnInit(f)
+}
+
+func (s *SummaryLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *SummaryLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *SummaryLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *SummaryLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *SummaryLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *SummaryLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *SummaryLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *SummaryLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *SummaryLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *SummaryLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *SummaryLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *SummaryLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *SummaryLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *SummaryLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *SummaryLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *SummaryLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *SummaryLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+
+//Log counts the entry, writing the summary of the previous interval first if it is due
+func (s *SummaryLog) Log(level string, v ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.summarizeDue()
+	s.counts[level]++
+}
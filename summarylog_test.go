@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummaryLog(t *testing.T) {
+	ml := new(MemoryLog)
+	sl := NewSummaryLog(ml, time.Minute)
+	defer sl.Close()
+	clock := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	sl.SetClock(clock)
+	if err := sl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+
+	for i := 0; i < 1200; i++ {
+		sl.Info("request served")
+	}
+	sl.Error("request failed")
+	sl.Errorf("request %s", "failed")
+	sl.Log("Error", "request failed")
+	sl.Debug("cache miss")
+	if len(ml.Entries()) != 0 {
+		t.Fatal("expected the entries to be counted rather than written, got", ml.Entries())
+	}
+
+	clock.Advance(59 * time.Second)
+	sl.Info("request served")
+	if len(ml.Entries()) != 0 {
+		t.Fatal("expected no summary before the interval has passed")
+	}
+
+	//The first entry after the interval writes the summary of the previous one
+	clock.Advance(time.Second)
+	sl.Warning("slow request")
+	e, ok := ml.LastEntry()
+	if !ok || e.Level != "Info" || e.Message != "last 1m0s: 3 Error, 1201 Info, 1 Debug" {
+		t.Fatal("unexpected summary", e)
+	}
+
+	//A tick after the next interval writes the pending counts without another entry
+	clock.Advance(time.Minute)
+	sl.mu.Lock()
+	sl.summarizeDue()
+	sl.mu.Unlock()
+	if e, _ := ml.LastEntry(); len(ml.Entries()) != 2 || e.Message != "last 1m0s: 1 Warning" {
+		t.Fatal("unexpected summary", ml.Entries())
+	}
+
+	//Empty intervals are skipped
+	clock.Advance(time.Minute)
+	sl.Flush()
+	if len(ml.Entries()) != 2 {
+		t.Error("expected no summary for an empty interval, got", ml.Entries())
+	}
+}
+
+func TestSummaryLogClose(t *testing.T) {
+	ml := new(MemoryLog)
+	sl := NewSummaryLog(ml, time.Hour)
+	clock := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	sl.SetClock(clock)
+	defer unregisterLevel("Audit")
+	if err := RegisterLevel("Audit", int(LevelNotice)); err != nil {
+		t.Fatal(err)
+	}
+	sl.Log("Audit", "login")
+	sl.Log("zzz", "unknown")
+	sl.Critical("disk full")
+	clock.Advance(90 * time.Second)
+	if err := sl.Close(); err != nil {
+		t.Fatal(err)
+	}
+	e, _ := ml.LastEntry()
+	if e.Message != "last 1m30s: 1 Critical, 1 Audit, 1 zzz" {
+		t.Error("unexpected summary", e.Message)
+	}
+}
+
+func TestSummaryLogTicker(t *testing.T) {
+	ml := new(MemoryLog)
+	sl := NewSummaryLog(ml, 10*time.Millisecond)
+	sl.Info("request served")
+	deadline := time.Now().Add(5 * time.Second)
+	for len(ml.Entries()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	sl.Close()
+	if e, _ := ml.LastEntry(); e.Message == "" || e.Level != "Info" {
+		t.Error("expected the ticker to write the summary, got", ml.Entries())
+	}
+}
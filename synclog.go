@@ -0,0 +1,138 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+//SyncLog wraps a Logger so that it can be safely shared between goroutines
+//All writes to the wrapped logger are serialized behind a mutex
+type SyncLog struct {
+	mu     sync.Mutex
+	logger Logger
+}
+
+//NewSyncLog returns a SyncLog that serializes calls to l
+func NewSyncLog(l Logger) *SyncLog {
+	return &SyncLog{logger: l}
+}
+
+//Flush flushes the wrapped logger if it implements FlushCloser
+func (s *SyncLog) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if fc, ok := s.logger.(FlushCloser); ok {
+		return fc.Flush()
+	}
+	return nil
+}
+
+//Close closes the wrapped logger if it implements io.Closer
+func (s *SyncLog) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.logger.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+//Enabled reports whether the wrapped logger would write an entry at level
+func (s *SyncLog) Enabled(level string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return enabled(s.logger, level)
+}
+
+//Init initializes the wrapped logger
+func (s *SyncLog) Init() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logger.Init()
+}
+
+//OnInit registers initializers on the wrapped logger
+func (s *SyncLog) OnInit(f ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger.OnInit(f...)
+}
+
+//OnInitLogger registers an initializer on the wrapped logger, it is called with the wrapped logger
+func (s *SyncLog) OnInitLogger(f func(s Logger)) {
+	s.OnInit(f)
+}
+
+func (s *SyncLog) Emergency(v ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger.Emergency(v...)
+}
+func (s *SyncLog) Alert(v ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger.Alert(v...)
+}
+func (s *SyncLog) Critical(v ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger.Critical(v...)
+}
+func (s *SyncLog) Error(v ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger.Error(v...)
+}
+func (s *SyncLog) Warning(v ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger.Warning(v...)
+}
+func (s *SyncLog) Notice(v ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger.Notice(v...)
+}
+func (s *SyncLog) Info(v ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger.Info(v...)
+}
+func (s *SyncLog) Debug(v ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger.Debug(v...)
+}
+func (s *SyncLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *SyncLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *SyncLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *SyncLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *SyncLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *SyncLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *SyncLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *SyncLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *SyncLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+func (s *SyncLog) Log(level string, v ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger.Log(level, v...)
+}
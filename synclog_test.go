@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSyncLogConcurrentFileLog(t *testing.T) {
+	fl := new(FileLog)
+	fl.OnInit(tlCallback)
+	sl := NewSyncLog(fl)
+	if err := sl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer os.Remove(fl.logPath)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 100; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				sl.Info(fmt.Sprintf("goroutine %d line %d", g, i))
+			}
+		}(g)
+	}
+	wg.Wait()
+	fl.Close()
+
+	b, err := ioutil.ReadFile(fl.logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	if len(lines) != 10000 {
+		t.Fatal("expected 10000 lines, got", len(lines))
+	}
+	for _, line := range lines {
+		var g, i int
+		if _, err := fmt.Sscanf(line, "Info goroutine %d line %d", &g, &i); err != nil {
+			t.Fatal("malformed line", line, err)
+		}
+	}
+}
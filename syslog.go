@@ -0,0 +1,184 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/syslog"
+)
+
+//SyslogLog writes to the local syslog daemon, or a remote one set with SetRemote
+//Each level is written with the matching syslog priority
+type SyslogLog struct {
+	LogBase
+	w       *syslog.Writer
+	network string
+	raddr   string
+	tag     string
+}
+
+//SetRemote dials a remote syslog server instead of the local daemon, e.g. SetRemote("udp", "logs:514")
+func (s *SyslogLog) SetRemote(network, addr string) {
+	s.network = network
+	s.raddr = addr
+}
+
+//SetTag sets the tag written with each message, the program name is used by default
+func (s *SyslogLog) SetTag(tag string) {
+	s.tag = tag
+}
+
+//Init expects input to be a list of func(s *SyslogLog) and then connects to syslog
+func (s *SyslogLog) Init() error {
+	for _, fn := range s.initializers {
+		switch funct := fn.(type) {
+		case func(s *SyslogLog):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *SyslogLog) or func(s Logger)")
+		}
+	}
+	w, err := syslog.Dial(s.network, s.raddr, syslog.LOG_INFO|syslog.LOG_USER, s.tag)
+	if err != nil {
+		return err
+	}
+	s.w = w
+	return nil
+}
+
+//Close closes the connection to syslog
+func (s *SyslogLog) Close() error {
+	if s.w == nil {
+		return nil
+	}
+	err := s.w.Close()
+	s.w = nil
+	return err
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *SyslogLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *SyslogLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *SyslogLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *SyslogLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *SyslogLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *SyslogLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
+func (s *SyslogLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *SyslogLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *SyslogLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *SyslogLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *SyslogLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *SyslogLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *SyslogLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *SyslogLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+
+func (s *SyslogLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *SyslogLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *SyslogLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *SyslogLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *SyslogLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *SyslogLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *SyslogLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *SyslogLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *SyslogLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+
+//Log writes with the priority matching level, in any case, registered levels use the nearest priority
+//and unknown levels are written as LOG_INFO
+func (s *SyslogLog) Log(level string, v ...interface{}) {
+	if !s.shouldLog(level) {
+		return
+	}
+	if s.w == nil {
+		s.handleError(errors.New("syslog is not connected, Init must be called first"))
+		return
+	}
+	msg := s.text(level, v)
+	sev, err := ParseLevel(level)
+	if err != nil {
+		sev = LevelInfo
+	}
+	switch sev.standard() {
+	case LevelEmergency:
+		err = s.w.Emerg(msg)
+	case LevelAlert:
+		err = s.w.Alert(msg)
+	case LevelCritical:
+		err = s.w.Crit(msg)
+	case LevelError:
+		err = s.w.Err(msg)
+	case LevelWarning:
+		err = s.w.Warning(msg)
+	case LevelNotice:
+		err = s.w.Notice(msg)
+	case LevelDebug:
+		err = s.w.Debug(msg)
+	default:
+		err = s.w.Info(msg)
+	}
+	if err != nil {
+		s.handleError(err)
+	}
+}
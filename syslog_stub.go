@@ -0,0 +1,86 @@
+//go:build windows || plan9
+
+package logger
+
+import (
+	"errors"
+	"io"
+)
+
+//errSyslogUnsupported is returned by SyslogLog on platforms without syslog
+var errSyslogUnsupported = errors.New("syslog is not supported on this platform")
+
+//SyslogLog is unavailable on this platform, Init always fails and nothing is written
+type SyslogLog struct {
+	LogBase
+}
+
+//SetRemote is a no-op on this platform
+func (s *SyslogLog) SetRemote(network, addr string) {}
+
+//SetTag is a no-op on this platform
+func (s *SyslogLog) SetTag(tag string) {}
+
+//Init always returns an error on this platform
+func (s *SyslogLog) Init() error {
+	return errSyslogUnsupported
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+func (s *SyslogLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *SyslogLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Close is a no-op on this platform
+func (s *SyslogLog) Close() error {
+	return nil
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *SyslogLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *SyslogLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *SyslogLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *SyslogLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
+func (s *SyslogLog) Emergency(v ...interface{}) {}
+func (s *SyslogLog) Alert(v ...interface{})     {}
+func (s *SyslogLog) Critical(v ...interface{})  {}
+func (s *SyslogLog) Error(v ...interface{})     {}
+func (s *SyslogLog) Warning(v ...interface{})   {}
+func (s *SyslogLog) Notice(v ...interface{})    {}
+func (s *SyslogLog) Info(v ...interface{})      {}
+func (s *SyslogLog) Debug(v ...interface{})     {}
+func (s *SyslogLog) Log(level string, v ...interface{}) {
+	s.handleError(errSyslogUnsupported)
+}
+func (s *SyslogLog) Emergencyf(format string, args ...interface{}) {}
+func (s *SyslogLog) Alertf(format string, args ...interface{})     {}
+func (s *SyslogLog) Criticalf(format string, args ...interface{})  {}
+func (s *SyslogLog) Errorf(format string, args ...interface{})     {}
+func (s *SyslogLog) Warningf(format string, args ...interface{})   {}
+func (s *SyslogLog) Noticef(format string, args ...interface{})    {}
+func (s *SyslogLog) Infof(format string, args ...interface{})      {}
+func (s *SyslogLog) Debugf(format string, args ...interface{})     {}
+func (s *SyslogLog) Logf(level string, format string, args ...interface{}) {
+	s.handleError(errSyslogUnsupported)
+}
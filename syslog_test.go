@@ -0,0 +1,70 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogLog(t *testing.T) {
+	addr := filepath.Join(t.TempDir(), "syslog.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		t.Skip("unix datagram sockets unavailable", err)
+	}
+	defer conn.Close()
+
+	sl := new(SyslogLog)
+	sl.OnInit(func(s *SyslogLog) {
+		s.SetRemote("unixgram", addr)
+		s.SetTag("logger-test")
+	})
+	if err := sl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer sl.Close()
+	if err := RegisterLevel("Audit", int(LevelWarning)); err != nil {
+		t.Fatal(err)
+	}
+	defer unregisterLevel("Audit")
+
+	//Priorities are facility LOG_USER (8) plus the severity
+	cases := []struct {
+		log      func()
+		priority string
+	}{
+		{func() { sl.Emergency("This is a message") }, "<8>"},
+		{func() { sl.Alert("This is a message") }, "<9>"},
+		{func() { sl.Critical("This is a message") }, "<10>"},
+		{func() { sl.Error("This is a message") }, "<11>"},
+		{func() { sl.Warning("This is a message") }, "<12>"},
+		{func() { sl.Notice("This is a message") }, "<13>"},
+		{func() { sl.Info("This is a message") }, "<14>"},
+		{func() { sl.Debug("This is a message") }, "<15>"},
+		{func() { sl.Log("custom level", "This is a message") }, "<14>"},
+		//Level names are matched in any case and registered levels use their nearest priority
+		{func() { sl.Log("error", "This is a message") }, "<11>"},
+		{func() { sl.Log("WARNING", "This is a message") }, "<12>"},
+		{func() { sl.Log("Audit", "This is a message") }, "<12>"},
+	}
+	buf := make([]byte, 1024)
+	for _, c := range cases {
+		c.log()
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatal("no datagram received", err)
+		}
+		msg := string(buf[:n])
+		if !strings.HasPrefix(msg, c.priority) {
+			t.Error("expected priority", c.priority, "got", msg)
+		}
+		if !strings.Contains(msg, "logger-test") || !strings.Contains(msg, "This is a message") {
+			t.Error("unexpected message", msg)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+//lineTemplate is a parsed SetTemplate layout, alternating literal text and tokens
+type lineTemplate struct {
+	parts []templatePart
+}
+
+//templatePart is either literal text or a token with its optional time layout
+type templatePart struct {
+	literal string
+	token   string
+	layout  string
+}
+
+//parseTemplate splits a template into its parts, rejecting unknown or unterminated tokens
+func parseTemplate(template string) (*lineTemplate, error) {
+	t := new(lineTemplate)
+	rest := template
+	for rest != "" {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			t.parts = append(t.parts, templatePart{literal: rest})
+			break
+		}
+		if start > 0 {
+			t.parts = append(t.parts, templatePart{literal: rest[:start]})
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated token in template %q", template)
+		}
+		token := rest[start+1 : start+end]
+		part := templatePart{token: token}
+		if strings.HasPrefix(token, "time:") {
+			part.token, part.layout = "time", strings.TrimPrefix(token, "time:")
+		}
+		switch part.token {
+		case "time":
+			if part.layout == "" {
+				part.layout = time.RFC3339
+			}
+		case "level", "LEVEL", "msg", "fields", "caller", "func", "logger":
+		default:
+			return nil, fmt.Errorf("unknown token {%s} in template %q", token, template)
+		}
+		t.parts = append(t.parts, part)
+		rest = rest[start+end+1:]
+	}
+	return t, nil
+}
+
+//render fills in the template for a single entry, without a line ending
+//When color is set the level name is wrapped in its ANSI colour
+func (t *lineTemplate) render(now time.Time, level string, style LevelStyle, color bool, fields map[string]interface{}, args []interface{}, order []string) string {
+	var b strings.Builder
+	for _, p := range t.parts {
+		switch p.token {
+		case "":
+			b.WriteString(p.literal)
+		case "time":
+			b.WriteString(now.Format(p.layout))
+		case "level", "LEVEL":
+			name := style.format(level)
+			if p.token == "LEVEL" {
+				name = strings.ToUpper(name)
+			}
+			if color {
+				name = colorizeName(level, name)
+			}
+			b.WriteString(name)
+		case "msg":
+			b.WriteString(joinArgs(args))
+		case "fields":
+			b.WriteString(templateFields(fields, order))
+		case "caller", "func", "logger":
+			if v, ok := fields[p.token]; ok {
+				fmt.Fprint(&b, v)
+			}
+		}
+	}
+	return b.String()
+}
+
+//templateFields renders fields other than the caller, function, logger name and stack trace as key=value pairs in the field order
+func templateFields(fields map[string]interface{}, order []string) string {
+	fields = flattenGroups(fields)
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		if k != "caller" && k != "func" && k != "logger" && k != "stacktrace" {
+			keys = append(keys, k)
+		}
+	}
+	orderKeys(keys, order)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", k, fields[k])
+	}
+	return strings.Join(pairs, " ")
+}
+
+//SetTemplate replaces the "level [args] key=value" layout of the text output with template
+//Tokens are {time} (RFC 3339), {time:layout} (any time.Format layout), {level}, {LEVEL} (upper case),
+//{msg} (the arguments separated by spaces), {fields} (sorted key=value pairs), {caller}, {func} (see SetCallerFunc) and {logger} (the SetPrefix or Named name)
+//e.g. "{time:2006-01-02} {LEVEL}: {msg}" renders "2024-01-02 ERROR: something"
+//An empty template restores the default layout, unknown tokens are rejected and leave the template unchanged
+//Loggers given a Formatter with SetFormatter use that instead
+func (l *LogBase) SetTemplate(template string) error {
+	if template == "" {
+		l.template = nil
+		return nil
+	}
+	t, err := parseTemplate(template)
+	if err != nil {
+		return err
+	}
+	l.template = t
+	return nil
+}
+
+//textFormatter returns the TextFormatter for the logger's template
+func (l *LogBase) textFormatter() TextFormatter {
+	return TextFormatter{template: l.template, clock: l.clock, style: l.levelStyle, order: l.fieldOrder}
+}
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestSetTemplate(t *testing.T) {
+	stdLog := new(StdLog)
+	if err := stdLog.SetTemplate("{LEVEL}: {msg}"); err != nil {
+		t.Fatal(err)
+	}
+	output := captureOutput(func() {
+		stdLog.Error("something", 42)
+	})
+	testOutput(output, "ERROR: something 42\n", t)
+
+	if err := stdLog.SetTemplate("<{level}> {msg} | {fields}"); err != nil {
+		t.Fatal(err)
+	}
+	output = captureOutput(func() {
+		stdLog.Warning("something")
+	})
+	testOutput(output, "<Warning> something | \n", t)
+
+	//An empty template restores the default layout
+	if err := stdLog.SetTemplate(""); err != nil {
+		t.Fatal(err)
+	}
+	output = captureOutput(func() {
+		stdLog.Error("something")
+	})
+	testOutput(output, "Error something\n", t)
+}
+
+func TestSetTemplateTime(t *testing.T) {
+	var buf bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&buf)
+	if err := wl.SetTemplate("{time:2006-01-02} {LEVEL}: {msg} {fields}"); err != nil {
+		t.Fatal(err)
+	}
+	wl.WithFields(map[string]interface{}{"user": "bob", "id": 7}).Error("something")
+	day := time.Now().Format("2006-01-02")
+	testOutput(buf.String(), day+" ERROR: something id=7 user=bob\n", t)
+
+	buf.Reset()
+	if err := wl.SetTemplate("{time} {msg}"); err != nil {
+		t.Fatal(err)
+	}
+	wl.Info("something")
+	matched, _ := regexp.MatchString(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\S* something\n$`, buf.String())
+	if !matched {
+		t.Error("expected an RFC 3339 timestamp, got", buf.String())
+	}
+}
+
+func TestSetTemplateColor(t *testing.T) {
+	var buf bytes.Buffer
+	fmtLog := new(FmtLog)
+	fmtLog.SetOutput(&buf)
+	fmtLog.SetColor(true)
+	fmtLog.isTerminal = func() bool { return true }
+	if err := fmtLog.SetTemplate("{LEVEL}: {msg}"); err != nil {
+		t.Fatal(err)
+	}
+	fmtLog.Error("something")
+	testOutput(buf.String(), "\x1b[31mERROR\x1b[0m: something\n", t)
+}
+
+func TestSetTemplateInvalid(t *testing.T) {
+	stdLog := new(StdLog)
+	if err := stdLog.SetTemplate("{level} {msg}"); err != nil {
+		t.Fatal(err)
+	}
+	for _, template := range []string{"{level} {message}", "{level", "{}"} {
+		if err := stdLog.SetTemplate(template); err == nil {
+			t.Errorf("expected %q to be rejected", template)
+		}
+	}
+	//The previous template is kept
+	output := captureOutput(func() {
+		stdLog.Info("something")
+	})
+	testOutput(output, "Info something\n", t)
+}
@@ -0,0 +1,45 @@
+package logger
+
+import "unicode/utf8"
+
+//truncatedMarker is appended to messages cut short by SetMaxMessageLen
+const truncatedMarker = "…(truncated)"
+
+//SetMaxMessageLen limits the message, the arguments joined with spaces, to n bytes
+//Longer messages are cut at a character boundary and marked with "…(truncated)", map arguments are kept as they are
+//0 (the default) never truncates
+func (l *LogBase) SetMaxMessageLen(n int) {
+	l.maxMessageLen = n
+}
+
+//truncateArgs replaces the non map arguments with a single truncated message when it is longer than max bytes
+func truncateArgs(v []interface{}, max int) []interface{} {
+	if max <= 0 {
+		return v
+	}
+	var msg, maps []interface{}
+	for _, arg := range v {
+		if _, ok := arg.(map[string]interface{}); ok {
+			maps = append(maps, arg)
+			continue
+		}
+		msg = append(msg, arg)
+	}
+	text := joinArgs(msg)
+	if len(text) <= max {
+		return v
+	}
+	return append([]interface{}{truncateString(text, max)}, maps...)
+}
+
+//truncateString cuts s to at most max bytes without splitting a UTF-8 character and appends the marker
+func truncateString(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + truncatedMarker
+}
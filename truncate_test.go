@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSetMaxMessageLen(t *testing.T) {
+	stdLog := new(StdLog)
+	stdLog.SetMaxMessageLen(10)
+	output := captureOutput(func() {
+		stdLog.Info("short")
+		stdLog.Info("0123456789")
+		stdLog.Info("0123456789abcdef")
+		stdLog.Info("01234", "56789", "abcdef")
+	})
+	testOutput(output, "Info short\nInfo 0123456789\nInfo 0123456789…(truncated)\nInfo 01234 5678…(truncated)\n", t)
+}
+
+func TestSetMaxMessageLenMultibyte(t *testing.T) {
+	//Each é is two bytes, so a limit of 5 would split the third
+	for max, expected := range map[int]string{
+		4: "éé…(truncated)",
+		5: "éé…(truncated)",
+		6: "ééé…(truncated)",
+		1: "…(truncated)",
+	} {
+		got := truncateString("éééé", max)
+		if got != expected {
+			t.Errorf("max %d: expected %q, got %q", max, expected, got)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("max %d: invalid UTF-8 %q", max, got)
+		}
+	}
+}
+
+func TestSetMaxMessageLenJSON(t *testing.T) {
+	var buf bytes.Buffer
+	jl := new(JSONLog)
+	jl.SetOutput(&buf)
+	jl.SetMaxMessageLen(4)
+	jl.Info("abcdefgh", map[string]interface{}{"user": "bob"})
+	entry := decodeJSONLine(buf.Bytes(), t)
+	if entry["message"] != "abcd…(truncated)" || entry["user"] != "bob" {
+		t.Error("expected the message to be truncated and the map kept, got", entry)
+	}
+
+	ml := new(MemoryLog)
+	ml.SetMaxMessageLen(3)
+	ml.Info("abcdef")
+	if last, _ := ml.LastEntry(); len(last.Args) != 1 || last.Args[0] != "abc…(truncated)" {
+		t.Error("unexpected entry", last)
+	}
+}
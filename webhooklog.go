@@ -0,0 +1,365 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"text/template"
+	"time"
+)
+
+//WebhookLog posts each entry to an HTTP endpoint such as a Slack incoming webhook
+//The body is the entry as JSON unless a template is set with SetBodyTemplate
+//Use SetLevel to only post the entries that matter, e.g. SetLevel("Error")
+//After Init entries are queued and posted in order in the background, call Flush or Close to wait for them
+//Failed requests are retried with backoff, failures that persist are passed to the OnError handler
+type WebhookLog struct {
+	LogBase
+	url        string
+	headers    http.Header
+	body       *template.Template
+	client     *http.Client
+	timeout    time.Duration
+	retries    int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	mu sync.Mutex
+	//queue holds the rendered bodies that have not been posted yet
+	queue [][]byte
+	//sendMu serializes requests so that entries are posted in order
+	sendMu sync.Mutex
+	done   chan struct{}
+	//queued wakes the delivery goroutine when an entry is queued
+	queued chan struct{}
+	wg     sync.WaitGroup
+}
+
+//Defaults for WebhookLog
+const (
+	defaultWebhookTimeout = 10 * time.Second
+	defaultWebhookRetries = 2
+)
+
+//WebhookEntry is the data available to a SetBodyTemplate template
+type WebhookEntry = Entry
+
+//webhookFuncs are the functions available to body templates
+var webhookFuncs = template.FuncMap{
+	//json encodes a value, e.g. {"text": {{json .Message}}}
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+//SetURL sets the endpoint entries are posted to
+func (s *WebhookLog) SetURL(url string) {
+	s.url = url
+}
+
+//SetHeader sets a header sent with every request, e.g. SetHeader("Authorization", "Bearer ...")
+//Content-Type is application/json unless it is set here
+func (s *WebhookLog) SetHeader(key, value string) {
+	if s.headers == nil {
+		s.headers = make(http.Header)
+	}
+	s.headers.Set(key, value)
+}
+
+//SetBodyTemplate sets a text/template used to render the request body from a WebhookEntry
+//The json function encodes a value, e.g. `{"text": {{json (printf "%s: %s" .Level .Message)}}}` for Slack
+func (s *WebhookLog) SetBodyTemplate(body string) error {
+	t, err := template.New("body").Funcs(webhookFuncs).Parse(body)
+	if err != nil {
+		return err
+	}
+	s.body = t
+	return nil
+}
+
+//SetClient sets the HTTP client used for requests, overriding SetTimeout
+func (s *WebhookLog) SetClient(c *http.Client) {
+	s.client = c
+}
+
+//SetTimeout sets how long a single request may take, 10 seconds by default
+func (s *WebhookLog) SetTimeout(d time.Duration) {
+	s.timeout = d
+}
+
+//SetRetries sets how many times a failed request is retried, 2 by default, a negative value disables retries
+func (s *WebhookLog) SetRetries(n int) {
+	s.retries = n
+}
+
+//SetBackoff sets the first and the longest delay between retries
+func (s *WebhookLog) SetBackoff(min, max time.Duration) {
+	s.minBackoff = min
+	s.maxBackoff = max
+}
+
+//Init expects input to be a list of func(s *WebhookLog), typically used to call SetURL, and then starts delivery
+func (s *WebhookLog) Init() error {
+	for _, fn := range s.initializers {
+		switch funct := fn.(type) {
+		case func(s *WebhookLog):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *WebhookLog) or func(s Logger)")
+		}
+	}
+	if s.url == "" {
+		return errors.New("webhook URL is not set, SetURL must be called")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done == nil {
+		s.done = make(chan struct{})
+		s.queued = make(chan struct{}, 1)
+		s.wg.Add(1)
+		go s.deliver(s.done, s.queued)
+	}
+	return nil
+}
+
+//deliver posts queued entries whenever it is woken until done is closed
+func (s *WebhookLog) deliver(done, queued chan struct{}) {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-queued:
+			s.Flush()
+		case <-done:
+			return
+		}
+	}
+}
+
+//Flush waits for the request in flight and posts any queued entries, returning the last error
+func (s *WebhookLog) Flush() error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	s.mu.Lock()
+	queue := s.queue
+	s.queue = nil
+	s.mu.Unlock()
+	var last error
+	for _, body := range queue {
+		if err := s.post(body); err != nil {
+			last = err
+		}
+	}
+	return last
+}
+
+//Close stops delivery and posts any queued entries
+func (s *WebhookLog) Close() error {
+	s.mu.Lock()
+	done := s.done
+	s.done = nil
+	s.mu.Unlock()
+	if done != nil {
+		close(done)
+		s.wg.Wait()
+	}
+	return s.Flush()
+}
+
+//render builds the request body for an entry
+func (s *WebhookLog) render(level string, t time.Time, fields map[string]interface{}, args []interface{}) ([]byte, error) {
+	if s.body == nil {
+		return jsonLine(s.levelName(level), t, fields, args), nil
+	}
+	var buf bytes.Buffer
+	err := s.body.Execute(&buf, entryAt(t, level, fields, args))
+	return buf.Bytes(), err
+}
+
+//post sends body, retrying with backoff, and reports a persistent failure to the OnError handler
+func (s *WebhookLog) post(body []byte) error {
+	client := s.client
+	if client == nil {
+		timeout := s.timeout
+		if timeout <= 0 {
+			timeout = defaultWebhookTimeout
+		}
+		client = &http.Client{Timeout: timeout}
+	}
+	retries := s.retries
+	if retries < 0 {
+		retries = 0
+	} else if retries == 0 {
+		retries = defaultWebhookRetries
+	}
+	err := retry(retries, s.minBackoff, s.maxBackoff, func() error {
+		req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		for k, v := range s.headers {
+			req.Header[k] = v
+		}
+		if req.Header.Get("Content-Type") == "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		io.Copy(ioutil.Discard, resp.Body)
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("webhook returned %s", resp.Status)
+		}
+		return nil
+	})
+	if err != nil {
+		s.handleError(err)
+	}
+	return err
+}
+
+//write renders an entry, passing a template error to the OnError handler
+func (s *WebhookLog) write(level string, v []interface{}) ([]byte, error) {
+	fields, args := s.prepare(level, v)
+	body, err := s.render(level, s.now(), fields, args)
+	if err != nil {
+		s.handleError(err)
+		return nil, err
+	}
+	return body, nil
+}
+
+//TryLog is Log that posts the entry on the caller's goroutine and returns any error hit while posting
+//Entries queued by Log are posted first so that the order is kept
+func (s *WebhookLog) TryLog(level string, v ...interface{}) error {
+	if !s.shouldLog(level) {
+		return nil
+	}
+	body, err := s.write(level, v)
+	if err != nil {
+		return err
+	}
+	s.Flush()
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	return s.post(body)
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *WebhookLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *WebhookLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *WebhookLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *WebhookLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *WebhookLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *WebhookLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
+func (s *WebhookLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *WebhookLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *WebhookLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *WebhookLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *WebhookLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *WebhookLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *WebhookLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *WebhookLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *WebhookLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *WebhookLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *WebhookLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *WebhookLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *WebhookLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *WebhookLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *WebhookLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *WebhookLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *WebhookLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+
+//Log queues the entry, it is posted in the background so the caller does not wait for the request and its retries
+func (s *WebhookLog) Log(level string, v ...interface{}) {
+	if !s.shouldLog(level) {
+		return
+	}
+	body, err := s.write(level, v)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	if s.done != nil {
+		s.queue = append(s.queue, body)
+		select {
+		case s.queued <- struct{}{}:
+		default:
+		}
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+	//Without Init, or after Close, there is nothing to hand the entry to
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	s.post(body)
+}
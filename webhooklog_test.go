@@ -0,0 +1,174 @@
+package logger
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+//webhookServer records the requests it receives, failing the first failures with a 500
+type webhookServer struct {
+	mu       sync.Mutex
+	bodies   []string
+	headers  []http.Header
+	failures int
+}
+
+func (w *webhookServer) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.failures > 0 {
+		w.failures--
+		http.Error(rw, "failed", http.StatusInternalServerError)
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	w.bodies = append(w.bodies, string(body))
+	w.headers = append(w.headers, r.Header)
+}
+
+func TestWebhookLog(t *testing.T) {
+	ws := new(webhookServer)
+	srv := httptest.NewServer(ws)
+	defer srv.Close()
+
+	wl := new(WebhookLog)
+	wl.OnInit(func(s *WebhookLog) {
+		s.SetURL(srv.URL)
+		s.SetHeader("Authorization", "Bearer secret")
+		s.SetLevel("Error")
+	})
+	if err := wl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	defer wl.Close()
+	if err := wl.SetBodyTemplate(`{"text": {{json (printf "%s: %s" .Level .Message)}}}`); err != nil {
+		t.Fatal(err)
+	}
+
+	wl.Warning("not posted")
+	wl.Error("disk \"full\"", 95)
+	if err := wl.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if len(ws.bodies) != 1 {
+		t.Fatal("expected one request, got", len(ws.bodies))
+	}
+	var body map[string]string
+	if err := json.Unmarshal([]byte(ws.bodies[0]), &body); err != nil {
+		t.Fatal("invalid body", ws.bodies[0], err)
+	}
+	if body["text"] != `Error: disk "full" 95` {
+		t.Error("unexpected body", ws.bodies[0])
+	}
+	if ws.headers[0].Get("Authorization") != "Bearer secret" || ws.headers[0].Get("Content-Type") != "application/json" {
+		t.Error("unexpected headers", ws.headers[0])
+	}
+}
+
+func TestWebhookLogDefaultBody(t *testing.T) {
+	ws := new(webhookServer)
+	srv := httptest.NewServer(ws)
+	defer srv.Close()
+
+	wl := new(WebhookLog)
+	wl.SetURL(srv.URL)
+	wl.SetHeader("Content-Type", "application/vnd.custom+json")
+	wl.Critical("This is a message", map[string]interface{}{"user": "bob"})
+	if len(ws.bodies) != 1 {
+		t.Fatal("expected one request, got", len(ws.bodies))
+	}
+	entry := decodeJSONLine([]byte(ws.bodies[0]), t)
+	if entry["message"] != "This is a message" || entry["level"] != "Critical" || entry["user"] != "bob" {
+		t.Error("unexpected body", ws.bodies[0])
+	}
+	if ct := ws.headers[0].Get("Content-Type"); ct != "application/vnd.custom+json" {
+		t.Error("expected the configured content type, got", ct)
+	}
+}
+
+func TestWebhookLogRetry(t *testing.T) {
+	ws := &webhookServer{failures: 2}
+	srv := httptest.NewServer(ws)
+	defer srv.Close()
+
+	wl := new(WebhookLog)
+	wl.SetURL(srv.URL)
+	wl.SetBackoff(time.Millisecond, time.Millisecond)
+	if err := wl.TryLog("Error", "This is a message"); err != nil {
+		t.Fatal("expected the retries to succeed", err)
+	}
+
+	ws.mu.Lock()
+	ws.failures = 10
+	ws.mu.Unlock()
+	wl.SetRetries(-1)
+	if err := wl.TryLog("Error", "This is a message"); err == nil {
+		t.Error("expected the failure to be returned")
+	}
+	if wl.Err() == nil {
+		t.Error("expected the failure to be passed to the error handler")
+	}
+	if len(ws.bodies) != 1 {
+		t.Error("expected one delivered request, got", len(ws.bodies))
+	}
+}
+
+func TestWebhookLogDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	ws := &webhookServer{failures: 1}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		ws.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	wl := new(WebhookLog)
+	wl.SetURL(srv.URL)
+	wl.SetRetries(-1)
+	if err := wl.Init(); err != nil {
+		t.Fatal("Init failed", err)
+	}
+	logged := make(chan struct{})
+	go func() {
+		wl.Error("first")
+		wl.Error("second")
+		wl.Error("third")
+		close(logged)
+	}()
+	select {
+	case <-logged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Log to return while the request is in flight")
+	}
+	close(release)
+	if err := wl.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if wl.Err() == nil {
+		t.Error("expected the failed request to be passed to the error handler")
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if len(ws.bodies) != 2 {
+		t.Fatal("expected two delivered requests, got", ws.bodies)
+	}
+	if !strings.Contains(ws.bodies[0], `"second"`) || !strings.Contains(ws.bodies[1], `"third"`) {
+		t.Error("expected the entries in order, got", ws.bodies)
+	}
+}
+
+func TestWebhookLogInvalid(t *testing.T) {
+	wl := new(WebhookLog)
+	if err := wl.Init(); err == nil {
+		t.Error("expected an error without a URL")
+	}
+	if err := wl.SetBodyTemplate("{{.Message"); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+}
@@ -0,0 +1,318 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+//WriterLog writes entries to any io.Writer, rendered by a pluggable Formatter
+type WriterLog struct {
+	LogBase
+	out       io.Writer
+	formatter Formatter
+	//routes are the writers set with SetLevelWriter, most severe threshold first
+	routes []levelRoute
+	//buf buffers writes to out when SetBuffer is used
+	buf           *bufferedWriter
+	bufSize       int
+	flushInterval time.Duration
+}
+
+//levelRoute sends entries at threshold, and less severe entries down to the next route, to w
+type levelRoute struct {
+	threshold Severity
+	w         io.Writer
+}
+
+//Init expects input to be a list of func(s *WriterLog), typically used to call SetOutput and SetFormatter
+func (s *WriterLog) Init() error {
+	for _, fn := range s.initializers {
+		switch funct := fn.(type) {
+		case func(s *WriterLog):
+			funct(s)
+		case func(s Logger):
+			funct(s)
+		default:
+			return errors.New("Init callbacks must have signature func(s *WriterLog) or func(s Logger)")
+		}
+	}
+	return nil
+}
+
+//SetOutput sets the destination writer, os.Stdout is used when none is set
+func (s *WriterLog) SetOutput(w io.Writer) {
+	s.out = w
+	if s.buf != nil {
+		s.buffer()
+	}
+}
+
+//SetBuffer buffers up to size bytes of output to save a write call for every entry, 0 (the default) writes each entry straight away
+//The buffer is written out when it fills, every flushInterval if it is positive, on Flush and Close,
+//and immediately after any entry at Error or more severe
+//Only the SetOutput writer is buffered, writers set with SetLevelWriter are written to directly
+func (s *WriterLog) SetBuffer(size int, flushInterval time.Duration) {
+	s.bufSize = size
+	s.flushInterval = flushInterval
+	s.buffer()
+}
+
+//buffer replaces the buffered writer to match the output and buffer settings, writing out anything already buffered
+func (s *WriterLog) buffer() {
+	if s.buf != nil {
+		if err := s.buf.Close(); err != nil {
+			s.handleError(err)
+		}
+		s.buf = nil
+	}
+	if s.bufSize > 0 {
+		out := s.out
+		if out == nil {
+			out = os.Stdout
+		}
+		s.buf = newBufferedWriter(out, s.bufSize, s.flushInterval)
+	}
+}
+
+//Flush writes out anything buffered by SetBuffer
+func (s *WriterLog) Flush() error {
+	if s.buf == nil {
+		return nil
+	}
+	return s.buf.Flush()
+}
+
+//Close writes out anything buffered and stops the periodic flush, the output itself is not closed
+func (s *WriterLog) Close() error {
+	if s.buf == nil {
+		return nil
+	}
+	return s.buf.Close()
+}
+
+//SetLevelWriter sends entries at level or more severe to w, instead of the writer set with SetOutput
+//When several levels are set each writer receives the entries down from its level to the next more severe one,
+//e.g. SetLevelWriter("Error", os.Stderr) splits Error and above onto stderr with everything else going to stdout
+//Passing a nil writer removes the route, levels that are not known are always written to the SetOutput writer
+func (s *WriterLog) SetLevelWriter(level string, w io.Writer) error {
+	sev, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	routes := make([]levelRoute, 0, len(s.routes)+1)
+	for _, r := range s.routes {
+		if r.threshold != sev {
+			routes = append(routes, r)
+		}
+	}
+	if w != nil {
+		routes = append(routes, levelRoute{sev, w})
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].threshold < routes[j].threshold
+	})
+	s.routes = routes
+	return nil
+}
+
+//output returns the writer for an entry at level
+func (s *WriterLog) output(level string) io.Writer {
+	if len(s.routes) > 0 {
+		if sev, err := ParseLevel(level); err == nil {
+			for _, r := range s.routes {
+				if sev <= r.threshold {
+					return r.w
+				}
+			}
+		}
+	}
+	if s.buf != nil {
+		return s.buf
+	}
+	if s.out == nil {
+		return os.Stdout
+	}
+	return s.out
+}
+
+//SetFormatter sets how entries are rendered, TextFormatter is used when none is set
+func (s *WriterLog) SetFormatter(f Formatter) {
+	s.formatter = f
+}
+
+//WithFields returns a copy of the logger, sharing the same writer, that adds fields to every entry
+func (s *WriterLog) WithFields(fields map[string]interface{}) Logger {
+	s.tracker()
+	c := *s
+	c.fields = s.mergeFields(fields)
+	return &c
+}
+
+//WithContext returns a copy of the logger carrying the context values registered with RegisterContextField
+func (s *WriterLog) WithContext(ctx context.Context) Logger {
+	return s.WithFields(fieldsFromContext(ctx))
+}
+
+//WithError returns a copy of the logger carrying err as an error field, with a cause field when it wraps other errors
+//A nil err returns the logger itself
+func (s *WriterLog) WithError(err error) Logger {
+	if err == nil {
+		return s
+	}
+	return s.WithFields(errorFields([]interface{}{err}))
+}
+
+//Named returns a copy of the logger, sharing the same output, whose entries are tagged with name
+func (s *WriterLog) Named(name string) Logger {
+	s.tracker()
+	c := *s
+	c.name = s.childName(name)
+	return &c
+}
+
+//WithGroup returns a copy of the logger whose later fields are grouped under name, e.g. WithGroup("http")
+func (s *WriterLog) WithGroup(name string) Logger {
+	s.tracker()
+	c := *s
+	c.groups = s.childGroups(name)
+	return &c
+}
+
+//Clone returns a copy of the logger whose configuration can be changed independently, it writes to the same writers as s
+func (s *WriterLog) Clone() Logger {
+	c := *s
+	c.detach()
+	c.routes = append([]levelRoute(nil), s.routes...)
+	//A buffered clone has a buffer of its own in front of the shared writer
+	c.buf = nil
+	c.buffer()
+	return &c
+}
+
+//Writer returns an io.Writer that logs each line written to it at level
+//e.g. log.New(l.Writer("Info"), "", 0) captures standard library logging
+func (s *WriterLog) Writer(level string) io.Writer {
+	return newLineWriter(s, level)
+}
+
+//PipeFrom logs each line read from r at level until r is exhausted, e.g. the stderr of a subprocess
+//Lines longer than 64KiB are logged in pieces so that memory use stays bounded
+func (s *WriterLog) PipeFrom(level string, r io.Reader) error {
+	return pipeFrom(s, level, r)
+}
+
+//Fatal logs at Emergency, flushes anything buffered and then exits with status 1
+func (s *WriterLog) Fatal(v ...interface{}) {
+	s.fatal(s, v)
+}
+
+//Fatalf formats its arguments with fmt.Sprintf and then behaves like Fatal
+func (s *WriterLog) Fatalf(format string, args ...interface{}) {
+	s.fatalf(s, format, args)
+}
+
+//Panic logs at Emergency, flushes anything buffered and then panics with the message
+func (s *WriterLog) Panic(v ...interface{}) {
+	s.panic(s, v)
+}
+
+//Panicf formats its arguments with fmt.Sprintf and then behaves like Panic
+func (s *WriterLog) Panicf(format string, args ...interface{}) {
+	s.panicf(s, format, args)
+}
+
+func (s *WriterLog) Emergency(v ...interface{}) {
+	s.Log("Emergency", v...)
+}
+func (s *WriterLog) Alert(v ...interface{}) {
+	s.Log("Alert", v...)
+}
+func (s *WriterLog) Critical(v ...interface{}) {
+	s.Log("Critical", v...)
+}
+func (s *WriterLog) Error(v ...interface{}) {
+	s.Log("Error", v...)
+}
+func (s *WriterLog) Warning(v ...interface{}) {
+	s.Log("Warning", v...)
+}
+func (s *WriterLog) Notice(v ...interface{}) {
+	s.Log("Notice", v...)
+}
+func (s *WriterLog) Info(v ...interface{}) {
+	s.Log("Info", v...)
+}
+func (s *WriterLog) Debug(v ...interface{}) {
+	s.Log("Debug", v...)
+}
+func (s *WriterLog) Emergencyf(format string, args ...interface{}) {
+	s.Log("Emergency", fmt.Sprintf(format, args...))
+}
+func (s *WriterLog) Alertf(format string, args ...interface{}) {
+	s.Log("Alert", fmt.Sprintf(format, args...))
+}
+func (s *WriterLog) Criticalf(format string, args ...interface{}) {
+	s.Log("Critical", fmt.Sprintf(format, args...))
+}
+func (s *WriterLog) Errorf(format string, args ...interface{}) {
+	s.Log("Error", fmt.Sprintf(format, args...))
+}
+func (s *WriterLog) Warningf(format string, args ...interface{}) {
+	s.Log("Warning", fmt.Sprintf(format, args...))
+}
+func (s *WriterLog) Noticef(format string, args ...interface{}) {
+	s.Log("Notice", fmt.Sprintf(format, args...))
+}
+func (s *WriterLog) Infof(format string, args ...interface{}) {
+	s.Log("Info", fmt.Sprintf(format, args...))
+}
+func (s *WriterLog) Debugf(format string, args ...interface{}) {
+	s.Log("Debug", fmt.Sprintf(format, args...))
+}
+func (s *WriterLog) Logf(level string, format string, args ...interface{}) {
+	s.Log(level, fmt.Sprintf(format, args...))
+}
+func (s *WriterLog) Log(level string, v ...interface{}) {
+	if err := s.write(level, v); err != nil {
+		s.handleError(err)
+	}
+}
+
+//TryLog is Log that also returns any error hit while writing
+func (s *WriterLog) TryLog(level string, v ...interface{}) error {
+	err := s.write(level, v)
+	if err != nil {
+		s.handleError(err)
+	}
+	return err
+}
+
+//write formats and writes a single entry
+func (s *WriterLog) write(level string, v []interface{}) error {
+	if !s.shouldLog(level) {
+		return nil
+	}
+	out := s.output(level)
+	formatter := s.formatter
+	if formatter == nil {
+		formatter = s.textFormatter()
+	}
+	formatter = s.configureFormatter(formatter)
+	fields, args := s.prepare(level, v)
+	b, err := formatter.Format(level, fields, args)
+	if err != nil {
+		return err
+	}
+	if _, err = out.Write(b); err != nil {
+		return err
+	}
+	if s.buf != nil && out == io.Writer(s.buf) && flushesAt(level) {
+		return s.buf.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriterLogLevelWriter(t *testing.T) {
+	var stdout, stderr, pager bytes.Buffer
+	wl := new(WriterLog)
+	wl.SetOutput(&stdout)
+	if err := wl.SetLevelWriter("Error", &stderr); err != nil {
+		t.Fatal(err)
+	}
+
+	wl.Error("This is an error")
+	wl.Critical("This is critical")
+	wl.Warning("This is a warning")
+	wl.Info("This is info")
+	wl.Log("custom level", "This is custom")
+	testOutput(stderr.String(), "Error This is an error\nCritical This is critical\n", t)
+	testOutput(stdout.String(), "Warning This is a warning\nInfo This is info\ncustom level This is custom\n", t)
+
+	//A more severe route takes its range from the one below it
+	stdout.Reset()
+	stderr.Reset()
+	if err := wl.SetLevelWriter("Alert", &pager); err != nil {
+		t.Fatal(err)
+	}
+	wl.Emergency("a")
+	wl.Alert("b")
+	wl.Critical("c")
+	wl.Debug("d")
+	testOutput(pager.String(), "Emergency a\nAlert b\n", t)
+	testOutput(stderr.String(), "Critical c\n", t)
+	testOutput(stdout.String(), "Debug d\n", t)
+
+	//Removing a route sends its range to the next one
+	pager.Reset()
+	stderr.Reset()
+	wl.SetLevelWriter("Alert", nil)
+	wl.Alert("b")
+	testOutput(pager.String(), "", t)
+	testOutput(stderr.String(), "Alert b\n", t)
+
+	if err := wl.SetLevelWriter("nonsense", &stderr); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}